@@ -0,0 +1,59 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+
+	keeperapi "github.com/DanielPickens/Keeper/pkg/api"
+)
+
+var detect bool
+
+// adoptCmd represents the adopt command
+var adoptCmd = &cobra.Command{
+	Use:   "adopt",
+	Short: "Bring an existing namespace under Keeper management.",
+	Long: `This command labels an existing namespace as managed by Keeper and generates its inventory from the playbook defaults.
+Nothing is applied to the namespace : the namespace is reported as managed, and the next "apply" uses the generated inventory.
+
+Use --detect to seed the inventory with the values detected from the running deployments and statefulsets :
+the "replicas", "image" and "tag" values found under a key named after a workload or one of its containers
+are replaced by the running ones.
+`,
+	Run: func(cmd *cobra.Command, args []string) {
+		err := runAdopt(namespace)
+		if err != nil {
+			logrus.Fatal(err.Error())
+		}
+	},
+}
+
+func NewAdoptCommand() *cobra.Command {
+	addCommonNamespaceCommandFlags(adoptCmd)
+	adoptCmd.Flags().BoolVar(&detect, "detect", false, "Seed the inventory with the replicas and images of the running workloads")
+	return adoptCmd
+}
+
+func runAdopt(namespace string) error {
+
+	if namespace == "" {
+		return errors.New("you must specified a namespace using the --namespace flag")
+	}
+
+	files := newFileClient(playbookDir)
+
+	inv, err := newClusters(files).Adopt(namespace, kubeContext, keeperapi.AdoptOptions{Detect: detect})
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Namespace %s is now managed by Keeper.\n\n", inv.Namespace)
+	fmt.Printf("\tThe inventory file is %s\n", filepath.Join(files.InventoryPath(), inv.Namespace+".json"))
+	fmt.Println("\tReview it, then use the apply command to apply it to the namespace.")
+
+	return nil
+}
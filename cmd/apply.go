@@ -1,16 +1,24 @@
 package cmd
 
 import (
+	"context"
 	"errors"
+	"fmt"
+	"os"
+	"text/tabwriter"
 	"time"
 
 	"github.com/gosuri/uiprogress"
 	"github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
+
+	keeperapi "github.com/DanielPickens/Keeper/pkg/api"
+	"github.com/DanielPickens/Keeper/pkg/resource"
 )
 
 const (
-	defaultTimeout = 5 * time.Minute
+	defaultTimeout     = 5 * time.Minute
+	defaultMaxRestarts = 3
 )
 
 // applyCmd represents the apply command
@@ -19,6 +27,16 @@ var applyCmd = &cobra.Command{
 	Short: "Apply a given inventory to the associated namespace",
 	Long: `This command updates the configuration files for the given namespace using the inventory file
 and applies the changes to the Kubernetes namespace.
+
+Objects are applied in phases : service accounts, config maps, secrets and other namespaced infrastructure first,
+then custom resource definitions and custom resources, then workloads. Jobs annotated with
+keeper.io/hook: pre-apply, such as database migrations, are run and must complete before anything else is applied,
+and the ones annotated with keeper.io/hook: post-apply are run once everything is applied. A failed hook aborts the apply.
+
+Every applied object is labeled with the namespace. The labeled objects that are not part of the configs anymore,
+such as the objects of a deleted template, are pruned unless --prune=false is set. Only the kinds listed in the
+"prune" settings of the playbook, or common namespaced kinds by default, are pruned. Persistent volume claims are only
+pruned when listed in the "prune" settings.
 	`,
 	Run: func(cmd *cobra.Command, args []string) {
 		err := runApply(namespace)
@@ -31,8 +49,15 @@ and applies the changes to the Kubernetes namespace.
 
 func NewApplyCommand() *cobra.Command {
 	addCommonNamespaceCommandFlags(applyCmd)
+	applyCmd.Flags().BoolVar(&prune, "prune", true, "delete the objects previously applied that are not part of the configs anymore")
 	applyCmd.Flags().BoolVar(&wait, "wait", false, "wait until all pods are running")
 	applyCmd.Flags().DurationVarP(&timeout, "timeout", "t", defaultTimeout, "The max time to wait for pods to be all running.")
+	applyCmd.Flags().BoolVar(&failFast, "fail-fast", true, "stop waiting as soon as a job failed or a pod cannot start")
+	applyCmd.Flags().Int32Var(&maxRestarts, "max-restarts", defaultMaxRestarts, "The number of restarts after which a crash looping container is considered as failed.")
+	applyCmd.Flags().StringSliceVar(&waitFor, "wait-for", nil, "wait only for the given workloads (e.g. deployment/api,job/migrate). Implies --wait")
+	applyCmd.Flags().BoolVar(&runTests, "test", false, "run the smoke tests of the playbook once the namespace is ready. Implies --wait")
+	applyCmd.Flags().DurationVar(&testTimeout, "test-timeout", defaultTestTimeout, "The max time to wait for each test job to complete.")
+	applyCmd.Flags().StringVar(&junitFile, "junit", "", "Write a JUnit XML report of the smoke tests to the given file.")
 
 	return applyCmd
 }
@@ -44,9 +69,10 @@ func runApply(namespace string) error {
 	}
 
 	files := newFileClient(playbookDir)
-	api := newAPI(files, newKubernetesClient())
+	api := newNamespaceAPI(files, namespace)
 
-	err := api.Apply(namespace, files.ConfigPath())
+	results, err := api.Apply(namespace, files.ConfigPath(), keeperapi.ApplyOptions{Prune: prune, Actor: currentActor()})
+	printApplyResults(results)
 	if err != nil {
 		return err
 	}
@@ -55,7 +81,12 @@ func runApply(namespace string) error {
 		"namespace": namespace,
 	}).Info("Playbook has been deployed")
 
-	if wait {
+	if wait || len(waitFor) > 0 || runTests {
+		workloads, err := keeperapi.ParseWorkloadRefs(waitFor)
+		if err != nil {
+			return err
+		}
+
 		logrus.WithFields(logrus.Fields{
 			"namespace": namespace,
 		}).Info("Waiting for namespace to be ready...")
@@ -63,7 +94,22 @@ func runApply(namespace string) error {
 		uiprogress.Start()
 		bar := uiprogress.AddBar(100).AppendCompleted().PrependElapsed()
 
-		if err := api.WaitForNamespaceReady(namespace, timeout, bar); err != nil {
+		opts := keeperapi.WaitOptions{
+			Timeout:     timeout,
+			FailFast:    failFast,
+			MaxRestarts: maxRestarts,
+			Workloads:   workloads,
+		}
+
+		result, err := api.WaitForNamespaceReady(context.Background(), namespace, opts, bar)
+		uiprogress.Stop()
+		if err != nil {
+			switch e := err.(type) {
+			case keeperapi.ErrorNamespaceFailed:
+				printFailures(e.Failures)
+			case keeperapi.ErrorWaitTimeout:
+				printPendingWorkloads(result)
+			}
 			return err
 		}
 
@@ -73,5 +119,60 @@ func runApply(namespace string) error {
 
 	}
 
+	if runTests {
+		return runSmokeTests(api, namespace)
+	}
+
 	return nil
 }
+
+// printApplyResults displays what the apply did to each object of the configs
+func printApplyResults(results []resource.ApplyResult) {
+	if len(results) == 0 {
+		return
+	}
+
+	w := new(tabwriter.Writer)
+	w.Init(os.Stdout, 0, 8, 0, '\t', 0)
+	fmt.Fprintln(w, "Kind\tName\tResult\t")
+	for _, r := range results {
+		res := string(r.Action)
+		if r.Error != "" {
+			res = fmt.Sprintf("%s: %s", r.Action, r.Error)
+		}
+		kind := r.Kind
+		if r.Hook != "" {
+			kind = fmt.Sprintf("%s (%s hook)", r.Kind, r.Hook)
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t\n", kind, r.Name, res)
+	}
+	fmt.Fprintln(w)
+	w.Flush()
+}
+
+// printFailures displays a report of the terminal failures detected in a namespace
+func printFailures(failures []resource.Failure) {
+	w := new(tabwriter.Writer)
+	w.Init(os.Stdout, 0, 8, 0, '\t', 0)
+	fmt.Fprintln(w, "Kind\tName\tContainer\tReason\tMessage\t")
+	for _, f := range failures {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t\n", f.Kind, f.Name, f.Container, f.Reason, f.Message)
+	}
+	fmt.Fprintln(w)
+	w.Flush()
+}
+
+// printPendingWorkloads displays the workloads that were not ready when the wait ended
+func printPendingWorkloads(result *keeperapi.WaitResult) {
+	w := new(tabwriter.Writer)
+	w.Init(os.Stdout, 0, 8, 0, '\t', 0)
+	fmt.Fprintln(w, "Kind\tName\tReady\tStatus\t")
+	for _, wl := range result.PendingWorkloads {
+		fmt.Fprintf(w, "%s\t%s\t%d/%d\t%s\t\n", wl.Kind, wl.Name, wl.Ready, wl.Desired, "NotReady")
+	}
+	for _, ref := range result.Missing {
+		fmt.Fprintf(w, "%s\t%s\t-\t%s\t\n", ref.Kind, ref.Name, "NotFound")
+	}
+	fmt.Fprintln(w)
+	w.Flush()
+}
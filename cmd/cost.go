@@ -0,0 +1,113 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	keeperapi "github.com/DanielPickens/Keeper/pkg/api"
+)
+
+var costCmd = &cobra.Command{
+	Use:   "cost",
+	Short: "Estimate the monthly cost of the namespaces of the playbook",
+	Long: `This command estimates the monthly cost of the namespaces of the inventories of the playbook, or of a given
+namespace, from the CPU and the memory requested by their pods and the storage requested by their persistent volume
+claims, and aggregates it by owner, the owner of a namespace being the value of its owner label. Each namespace is
+estimated on the cluster its inventory targets.
+
+The prices are read from the cost section of the config file (default is $HOME/.keeper.yaml) :
+
+  cost:
+    currency: USD
+    cpu: 20.0        # per CPU core and per month
+    memory: 2.5      # per GiB and per month
+    storage: 0.1     # per GiB and per month
+    ownerLabel: team # label of the namespaces telling who owns them, owner by default
+`,
+	Run: func(cmd *cobra.Command, args []string) {
+		err := runCost()
+		if err != nil {
+			logrus.Fatal(err.Error())
+		}
+	},
+}
+
+func NewCostCommand() *cobra.Command {
+	addCommonNamespaceCommandFlags(costCmd)
+	costCmd.Flags().StringVarP(&output, "output", "o", "text", "Output format (text or json)")
+	return costCmd
+}
+
+func runCost() error {
+
+	if output != "text" && output != "json" {
+		return fmt.Errorf("unsupported output %q, expected text or json", output)
+	}
+
+	prices := keeperapi.PriceTable{}
+	if err := viper.UnmarshalKey("cost", &prices); err != nil {
+		return fmt.Errorf("invalid cost section of the config file : %v", err)
+	}
+	if prices.CPU == 0 && prices.Memory == 0 && prices.Storage == 0 {
+		return errors.New("no price is set, set the prices in the cost section of the config file (default is $HOME/.keeper.yaml)")
+	}
+
+	var report *keeperapi.CostReport
+	var err error
+
+	if namespace != "" {
+		api := newNamespaceAPI(newFileClient(playbookDir), namespace)
+		report, err = api.EstimateCost([]string{namespace}, prices)
+	} else {
+		report, err = newClusters(newFileClient(playbookDir)).EstimateCost(prices)
+	}
+	if err != nil {
+		return err
+	}
+
+	if namespace != "" && len(report.Namespaces) == 0 {
+		return fmt.Errorf("the cost of the namespace %s could not be estimated", namespace)
+	}
+
+	if output == "json" {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(report)
+	}
+
+	printCostReport(report)
+
+	return nil
+}
+
+// printCostReport prints the monthly cost of each namespace, then of each owner
+func printCostReport(report *keeperapi.CostReport) {
+	w := new(tabwriter.Writer)
+	w.Init(os.Stdout, 0, 8, 0, '\t', 0)
+
+	fmt.Fprintln(w, "Namespace\tOwner\tCPU\tMemory (GiB)\tStorage (GiB)\tMonthly Cost\t")
+	for _, ns := range report.Namespaces {
+		fmt.Fprintf(w, "%s\t%s\t%.2f\t%.2f\t%.2f\t%.2f %s\t\n", ns.Namespace, ns.Owner, ns.CPU, ns.Memory, ns.Storage, ns.Cost, report.Currency)
+	}
+	fmt.Fprintln(w)
+
+	fmt.Fprintln(w, "Owner\tNamespaces\tMonthly Cost\t")
+	for _, o := range report.Owners {
+		fmt.Fprintf(w, "%s\t%s\t%.2f %s\t\n", o.Owner, strings.Join(o.Namespaces, ","), o.Cost, report.Currency)
+	}
+	fmt.Fprintln(w)
+
+	fmt.Fprintf(w, "Total\t%.2f %s\t\n", report.Total, report.Currency)
+	if len(report.Skipped) > 0 {
+		fmt.Fprintf(w, "Skipped\t%s\t\n", strings.Join(report.Skipped, ","))
+	}
+	w.Flush()
+}
@@ -42,9 +42,7 @@ func runCreate(namespace string) error {
 
 	files := newFileClient(playbookDir)
 
-	api := newAPI(files, newKubernetesClient())
-
-	inv, err := api.Create(namespace)
+	inv, err := newClusters(files).Create(namespace, kubeContext)
 	if err != nil {
 		return err
 	}
@@ -0,0 +1,48 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"text/template"
+
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+// cronJobCmd represents the cronjob command
+var cronJobCmd = &cobra.Command{
+	Use:   "cronjob [command]",
+	Short: "Manage the cronjobs of a namespace",
+	Long: `Suspend, resume or trigger the cronjobs of a namespace, such as the nightly data refresh of a preview environment.
+
+Use "get cronjobs" to list the cronjobs of a namespace.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		runCronJob()
+	},
+}
+
+func NewCronJobCommand() *cobra.Command {
+	cronJobCmd.AddCommand(NewCronJobSuspendCommand())
+	cronJobCmd.AddCommand(NewCronJobResumeCommand())
+	cronJobCmd.AddCommand(NewCronJobTriggerCommand())
+
+	return cronJobCmd
+}
+
+func runCronJob() {
+	tpl := template.Must(template.New("cronJobCmd").Parse(`
+Using the cronjob command with a sub-command is helpful. Please use one of the following sub-command :
+{{range . -}}
+- {{.}}
+{{end -}}
+`))
+
+	data := []string{"cronjob suspend", "cronjob resume", "cronjob trigger"}
+
+	contents := bytes.Buffer{}
+	if err := tpl.Execute(&contents, data); err != nil {
+		logrus.Fatalf("error while executing template : %v", err)
+	}
+
+	fmt.Println(contents.String())
+}
@@ -0,0 +1,70 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+var cronJobSuspendCmd = &cobra.Command{
+	Use:   "suspend [NAME]",
+	Short: "Suspend the scheduling of a cronjob",
+	Long: `Suspend the scheduling of a cronjob of a namespace. The jobs already running are not stopped.
+
+The cronjob stays suspended until it is resumed, or until the configs are applied again with a suspend value.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		err := runCronJobSuspend(args[0], true)
+		if err != nil {
+			logrus.Fatal(err.Error())
+		}
+	},
+}
+
+var cronJobResumeCmd = &cobra.Command{
+	Use:   "resume [NAME]",
+	Short: "Resume the scheduling of a suspended cronjob",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		err := runCronJobSuspend(args[0], false)
+		if err != nil {
+			logrus.Fatal(err.Error())
+		}
+	},
+}
+
+func NewCronJobSuspendCommand() *cobra.Command {
+	addCommonNamespaceCommandFlags(cronJobSuspendCmd)
+	return cronJobSuspendCmd
+}
+
+func NewCronJobResumeCommand() *cobra.Command {
+	addCommonNamespaceCommandFlags(cronJobResumeCmd)
+	return cronJobResumeCmd
+}
+
+func runCronJobSuspend(name string, suspend bool) error {
+	if namespace == "" {
+		return errors.New("you must specify a namespace using the --namespace flag")
+	}
+
+	api := newNamespaceAPI(newFileClient(playbookDir), namespace)
+
+	if err := api.CronJobs().Suspend(namespace, name, suspend); err != nil {
+		return fmt.Errorf("an error occurred when updating the cronjob : %v", err)
+	}
+
+	msg := "cronjob resumed"
+	if suspend {
+		msg = "cronjob suspended"
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"namespace": namespace,
+		"cronjob":   name,
+	}).Info(msg)
+
+	return nil
+}
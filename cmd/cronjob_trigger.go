@@ -0,0 +1,81 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+
+	keeperapi "github.com/DanielPickens/Keeper/pkg/api"
+	"github.com/DanielPickens/Keeper/pkg/resource"
+)
+
+var cronJobTriggerCmd = &cobra.Command{
+	Use:   "trigger [NAME]",
+	Short: "Run a cronjob at once",
+	Long: `Create a job from the template of a cronjob of a namespace, to run it at once whatever its schedule,
+even if the cronjob is suspended.
+
+Use --wait to wait until the job is completed or failed while streaming its logs. The command fails if the job failed.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		err := runCronJobTrigger(args[0])
+		if err != nil {
+			logrus.Fatal(err.Error())
+		}
+	},
+}
+
+func NewCronJobTriggerCommand() *cobra.Command {
+	addCommonNamespaceCommandFlags(cronJobTriggerCmd)
+	cronJobTriggerCmd.Flags().BoolVar(&wait, "wait", false, "wait until the job is completed or failed, streaming its logs")
+	cronJobTriggerCmd.Flags().DurationVarP(&timeout, "timeout", "t", defaultTimeout, "The max time to wait for the job to complete.")
+	return cronJobTriggerCmd
+}
+
+func runCronJobTrigger(name string) error {
+	if namespace == "" {
+		return errors.New("you must specify a namespace using the --namespace flag")
+	}
+
+	api := newNamespaceAPI(newFileClient(playbookDir), namespace)
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	job, err := api.TriggerCronJob(ctx, namespace, name, keeperapi.RerunOptions{
+		Wait:    wait,
+		Timeout: timeout,
+		Output:  os.Stdout,
+	})
+	if err == context.DeadlineExceeded {
+		return fmt.Errorf("the job of cronjob %s did not complete within %s", name, timeout)
+	}
+	if err != nil {
+		return fmt.Errorf("an error occurred when triggering the cronjob : %v", err)
+	}
+
+	fields := logrus.Fields{
+		"namespace": namespace,
+		"cronjob":   name,
+		"job":       job.Name,
+	}
+
+	if !wait {
+		logrus.WithFields(fields).Info("job created")
+		return nil
+	}
+
+	if job.Status == resource.JobFailed {
+		return fmt.Errorf("the job %s failed: %s", job.Name, job.Failure())
+	}
+
+	logrus.WithFields(fields).Info("job completed")
+
+	return nil
+}
@@ -13,7 +13,8 @@ var deleteJobCmd = &cobra.Command{
 	Short: "Delete a job object from a namespace",
 	Long: `Delete a job object that started a pod.
 
-It won't remove any configuration in the inventory. Reapplying the inventory will redeploy it.
+It won't remove any configuration in the inventory. Reapplying the inventory will redeploy it,
+and "rerun job" deletes the job and runs it again at once.
 
 Kubernetes will also remove the pod for whatever the status.`,
 	Args: cobra.ExactArgs(1),
@@ -36,7 +37,7 @@ func runDeleteJob(resource string) error {
 		return errors.New("you must specify a namespace using the --namespace flag")
 	}
 
-	api := newAPI(newFileClient(playbookDir), newKubernetesClient())
+	api := newNamespaceAPI(newFileClient(playbookDir), namespace)
 	err := api.DeleteResource(namespace, resource)
 	if err != nil {
 		return errors.New(fmt.Sprintf("an error occurend when removing the job : %v", err))
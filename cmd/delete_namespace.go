@@ -1,19 +1,33 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
 	"os"
+	"strings"
+	"text/tabwriter"
 
 	"github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
+
+	keeperapi "github.com/DanielPickens/Keeper/pkg/api"
+	"github.com/DanielPickens/Keeper/pkg/resource"
 )
 
 // deleteCmd represents the create command
 var deleteNamespaceCmd = &cobra.Command{
 	Use:   "namespace [NAME]",
 	Short: "Delete a namespace",
-	Long:  `This command delete a namespace and all the associated resources`,
-	Args:  cobra.ExactArgs(1),
+	Long: `This command delete a namespace and all the associated resources.
+
+The jobs of the configs annotated with keeper.io/hook: pre-delete are run first, and the namespace is kept if one
+of them fails.
+
+With --wait, the inventory and the configs of the namespace are deleted once the namespace is actually gone.
+If the namespace is still terminating after --timeout, the resources and the finalizers blocking it are reported.
+With --force-finalizers, these finalizers are cleared instead. Clearing finalizers skips the cleanup they guard,
+such as releasing external resources : use it as a last resort.`,
+	Args: cobra.ExactArgs(1),
 	Run: func(cmd *cobra.Command, args []string) {
 		err := runDeleteNamespace(args[0])
 		if err != nil {
@@ -23,6 +37,10 @@ var deleteNamespaceCmd = &cobra.Command{
 }
 
 func NewDeleteNamespaceCommand() *cobra.Command {
+	deleteNamespaceCmd.Flags().BoolVar(&wait, "wait", false, "wait until the namespace is deleted")
+	deleteNamespaceCmd.Flags().DurationVarP(&timeout, "timeout", "t", defaultTimeout, "The max time to wait for the namespace to be deleted.")
+	deleteNamespaceCmd.Flags().BoolVar(&forceFinalizers, "force-finalizers", false, "clear the finalizers blocking the namespace once the timeout is reached. Implies --wait")
+
 	return deleteNamespaceCmd
 }
 
@@ -31,10 +49,28 @@ func runDeleteNamespace(namespace string) error {
 		return nil
 	}
 
-	api := newAPI(newFileClient(playbookDir), newKubernetesClient())
+	files := newFileClient(playbookDir)
+	api := newNamespaceAPI(files, namespace)
+
+	opts := keeperapi.DeleteOptions{
+		Wait:            wait || forceFinalizers,
+		Timeout:         timeout,
+		ForceFinalizers: forceFinalizers,
+		ConfigPath:      files.ConfigPath(),
+	}
+
+	if opts.Wait {
+		logrus.WithFields(logrus.Fields{
+			"namespace": namespace,
+		}).Info("Waiting for namespace to be deleted...")
+	}
 
-	err := api.Delete(namespace, false)
+	err := api.Delete(context.Background(), namespace, opts)
 	if err != nil {
+		if stuck, ok := err.(keeperapi.ErrorNamespaceStuck); ok && stuck.Blockers != nil {
+			printBlockers(stuck.Blockers)
+			return fmt.Errorf("namespace %s is still terminating, use --force-finalizers to clear the finalizers blocking it", namespace)
+		}
 		return err
 	}
 
@@ -43,4 +79,29 @@ func runDeleteNamespace(namespace string) error {
 	}).Info("namespace deleted")
 
 	return nil
-}
\ No newline at end of file
+}
+
+// printBlockers displays what prevents a terminating namespace from being deleted
+func printBlockers(blockers *resource.NamespaceBlockers) {
+	w := new(tabwriter.Writer)
+	w.Init(os.Stdout, 0, 8, 0, '\t', 0)
+
+	if len(blockers.Finalizers) > 0 || len(blockers.Conditions) > 0 {
+		fmt.Fprintln(w, "Namespace\tFinalizers\tReason\tMessage\t")
+		fmt.Fprintf(w, "%s\t%s\t\t\t\n", blockers.Namespace, strings.Join(blockers.Finalizers, ","))
+		for _, cond := range blockers.Conditions {
+			fmt.Fprintf(w, "\t\t%s\t%s\t\n", cond.Reason, cond.Message)
+		}
+		fmt.Fprintln(w)
+	}
+
+	if len(blockers.Resources) > 0 {
+		fmt.Fprintln(w, "Kind\tName\tFinalizers\t")
+		for _, r := range blockers.Resources {
+			fmt.Fprintf(w, "%s\t%s\t%s\t\n", r.Kind, r.Name, strings.Join(r.Finalizers, ","))
+		}
+		fmt.Fprintln(w)
+	}
+
+	w.Flush()
+}
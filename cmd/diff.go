@@ -0,0 +1,69 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+
+	keeperapi "github.com/DanielPickens/Keeper/pkg/api"
+	"github.com/DanielPickens/Keeper/pkg/resource"
+)
+
+var diffCmd = &cobra.Command{
+	Use:   "diff",
+	Short: "Show what applying the inventory of a namespace would change",
+	Long: `This command generates the configs of the namespace from its current inventory and compares them with the live
+objects of the namespace, using a server-side dry-run : nothing is applied.
+
+A unified diff is displayed for each object that would be created or configured.
+	`,
+	Run: func(cmd *cobra.Command, args []string) {
+		err := runDiff(namespace)
+		if err != nil {
+			logrus.Fatal(err)
+		}
+	},
+}
+
+func NewDiffCommand() *cobra.Command {
+	addCommonNamespaceCommandFlags(diffCmd)
+	diffCmd.Flags().BoolVar(&prune, "prune", true, "Show the objects that would be pruned because they are not part of the configs anymore")
+	return diffCmd
+}
+
+func runDiff(namespace string) error {
+
+	if namespace == "" {
+		return errors.New("you must specified a namespace using the --namespace flag")
+	}
+
+	files := newFileClient(playbookDir)
+	api := newNamespaceAPI(files, namespace)
+
+	results, err := api.Diff(namespace, keeperapi.ApplyOptions{Prune: prune})
+	printDiffs(results)
+
+	return err
+}
+
+// printDiffs displays the diff of each object that would change, then a summary of the changes
+func printDiffs(results []resource.ApplyResult) {
+	count := make(map[resource.ApplyAction]int)
+
+	for _, r := range results {
+		count[r.Action]++
+		if r.Diff != "" {
+			fmt.Println(r.Diff)
+		}
+	}
+
+	logrus.Infof("%d to create, %d to configure, %d unchanged, %d to prune, %d failing",
+		count[resource.ApplyCreated],
+		count[resource.ApplyConfigured],
+		count[resource.ApplyUnchanged],
+		count[resource.ApplyPruned],
+		count[resource.ApplyFailed],
+	)
+}
@@ -0,0 +1,160 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+
+	keeperapi "github.com/DanielPickens/Keeper/pkg/api"
+)
+
+var (
+	gcNamespaces  string
+	gcInventories string
+	gcConfigs     string
+)
+
+var gcCmd = &cobra.Command{
+	Use:   "gc",
+	Short: "Find and resolve orphan namespaces, inventories and configs",
+	Long: `This command cross-references the namespaces managed by Keeper with the inventories and the rendered configs
+of the playbook, and reports :
+- namespaces without inventory, that can be adopted (an inventory is created from the playbook defaults) or deleted
+- inventories without namespace, that can be recreated (the namespace is created and the inventory applied) or deleted
+- configs without inventory, that can be deleted
+
+For each orphan, the command asks what to do, unless the action is set using --namespaces, --inventories or --configs.
+Use --dry-run to only report what would be done and --output json for automation : neither of them asks anything.
+	`,
+	Run: func(cmd *cobra.Command, args []string) {
+		err := runGC()
+		if err != nil {
+			logrus.Fatal(err)
+		}
+	},
+}
+
+func NewGCCommand() *cobra.Command {
+	gcCmd.Flags().BoolVar(&dryRun, "dry-run", false, "only report the orphans and the actions that would be performed")
+	gcCmd.Flags().StringVarP(&output, "output", "o", "text", "Output format (text or json)")
+	gcCmd.Flags().StringVar(&gcNamespaces, "namespaces", "", "Action on namespaces without inventory (adopt, delete or skip)")
+	gcCmd.Flags().StringVar(&gcInventories, "inventories", "", "Action on inventories without namespace (recreate, delete or skip)")
+	gcCmd.Flags().StringVar(&gcConfigs, "configs", "", "Action on configs without inventory (delete or skip)")
+
+	return gcCmd
+}
+
+// gcResult represents an orphan and how it has been resolved
+type gcResult struct {
+	keeperapi.Orphan
+	Action keeperapi.GCAction `json:"action"`
+	DryRun bool               `json:"dryRun"`
+	Error  string             `json:"error,omitempty"`
+}
+
+func runGC() error {
+	if output != "text" && output != "json" {
+		return fmt.Errorf("unsupported output %q, expected text or json", output)
+	}
+
+	files := newFileClient(playbookDir)
+	clusters := newClusters(files)
+
+	orphans, err := clusters.FindOrphans()
+	if err != nil {
+		return err
+	}
+
+	policies := map[keeperapi.OrphanKind]keeperapi.GCAction{
+		keeperapi.OrphanNamespace: keeperapi.GCAction(gcNamespaces),
+		keeperapi.OrphanInventory: keeperapi.GCAction(gcInventories),
+		keeperapi.OrphanConfigs:   keeperapi.GCAction(gcConfigs),
+	}
+
+	results := make([]gcResult, 0, len(orphans))
+	failed := 0
+
+	for _, orphan := range orphans {
+		action := policies[orphan.Kind]
+
+		if action == "" {
+			if output == "json" || dryRun {
+				action = keeperapi.GCSkip
+			} else {
+				action = askForAction(orphan)
+			}
+		}
+
+		result := gcResult{Orphan: orphan, Action: action, DryRun: dryRun}
+
+		if !dryRun {
+			if err := clusters.ResolveOrphan(orphan, action, files.ConfigPath()); err != nil {
+				result.Error = err.Error()
+				failed++
+			}
+		}
+
+		results = append(results, result)
+	}
+
+	if output == "json" {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(results); err != nil {
+			return err
+		}
+	} else {
+		printGCResults(results)
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d orphans could not be resolved", failed)
+	}
+
+	return nil
+}
+
+// askForAction asks how to resolve an orphan. Skipping is the default.
+func askForAction(orphan keeperapi.Orphan) keeperapi.GCAction {
+	choices := []string{string(keeperapi.GCSkip)}
+	for _, a := range orphan.Actions() {
+		if a != keeperapi.GCSkip {
+			choices = append(choices, string(a))
+		}
+	}
+
+	question := fmt.Sprintf("%s %s", orphan.Kind, orphan.Namespace)
+	if orphan.Context != "" {
+		question = fmt.Sprintf("%s (%s)", question, orphan.Context)
+	}
+
+	return keeperapi.GCAction(askForChoice(question, choices, os.Stdin))
+}
+
+// printGCResults displays the orphans and how they have been resolved
+func printGCResults(results []gcResult) {
+	if len(results) == 0 {
+		logrus.Info("No orphan found")
+		return
+	}
+
+	w := new(tabwriter.Writer)
+	w.Init(os.Stdout, 0, 8, 0, '\t', 0)
+	fmt.Fprintln(w, "Kind\tNamespace\tContext\tAction\tResult\t")
+	for _, r := range results {
+		res := "done"
+		switch {
+		case r.DryRun:
+			res = "dry run"
+		case r.Error != "":
+			res = r.Error
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t\n", r.Kind, r.Namespace, orDash(r.Context), r.Action, res)
+	}
+	fmt.Fprintln(w)
+	w.Flush()
+}
@@ -22,8 +22,12 @@ or the url where you can join services throw ingress.`,
 func NewGetCommand() *cobra.Command {
 	addCommonNamespaceCommandFlags(getCmd)
 
+	getCmd.AddCommand(NewGetCronJobsCommand())
+	getCmd.AddCommand(NewGetEventsCommand())
 	getCmd.AddCommand(NewGetNamespacesCommand())
+	getCmd.AddCommand(NewGetPodsCommand())
 	getCmd.AddCommand(NewGetServicesCommand())
+	getCmd.AddCommand(NewGetStatusCommand())
 
 	return getCmd
 }
@@ -36,7 +40,7 @@ Using the get command with a sub-command is helpful. Please use one of the follo
 {{end}}
 `))
 
-	data := []string{"get services", "get namespaces"}
+	data := []string{"get services", "get namespaces", "get status", "get cronjobs", "get pods", "get events"}
 
 	contents := bytes.Buffer{}
 	if err := tpl.Execute(&contents, data); err != nil {
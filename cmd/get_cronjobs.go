@@ -0,0 +1,67 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+var getCronJobsCmd = &cobra.Command{
+	Use:   "cronjobs",
+	Short: "Show the cronjobs of a given namespace.",
+	Long: `This command display the cronjobs of a given namespace with their schedule, whether they are suspended,
+their number of running jobs and when they were last scheduled and last succeeded.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		err := runGetCronJobs()
+		if err != nil {
+			logrus.Fatal(err.Error())
+		}
+
+	},
+}
+
+func NewGetCronJobsCommand() *cobra.Command {
+	addCommonNamespaceCommandFlags(getCronJobsCmd)
+	return getCronJobsCmd
+}
+
+func runGetCronJobs() error {
+
+	if namespace == "" {
+		return errors.New("you must specify a namespace using the --namespace flag")
+	}
+
+	api := newNamespaceAPI(newFileClient(playbookDir), namespace)
+
+	cronjobs, err := api.CronJobs().List(namespace)
+	if err != nil {
+		return fmt.Errorf("an error occurred when getting the cronjobs of the namespace : %v", err)
+	}
+
+	w := new(tabwriter.Writer)
+	w.Init(os.Stdout, 0, 8, 0, '\t', 0)
+	fmt.Fprintln(w, "Name\tSchedule\tSuspended\tActive\tLast Schedule\tLast Success\tStatus\t")
+	for _, cj := range cronjobs {
+		fmt.Fprintf(w, "%s\t%s\t%t\t%d\t%s\t%s\t%s\t\n",
+			cj.Name, cj.Schedule, cj.Suspended, cj.Active, formatTime(cj.LastScheduleTime), formatTime(cj.LastSuccessfulTime), cj.Status)
+	}
+	fmt.Fprintln(w)
+	w.Flush()
+
+	return nil
+
+}
+
+// formatTime formats an optional time, - when unset
+func formatTime(t *time.Time) string {
+	if t == nil {
+		return "-"
+	}
+
+	return t.Local().Format("2006-01-02 15:04:05")
+}
@@ -0,0 +1,138 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"text/tabwriter"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/util/duration"
+
+	keeperapi "github.com/DanielPickens/Keeper/pkg/api"
+	"github.com/DanielPickens/Keeper/pkg/resource"
+)
+
+var (
+	eventsWarnings bool
+	eventsObject   string
+	eventsWatch    bool
+)
+
+var getEventsCmd = &cobra.Command{
+	Use:   "events",
+	Short: "Show the events of a given namespace.",
+	Long: `This command display the events of a given namespace, such as failed schedulings, image pull errors or
+containers restarting, the most recent last. The events occurring several times are displayed once, with their count.
+
+Use --warnings to only display the warnings, and --object to only display the events about an object, e.g.
+"keeper get events -n ns --object deployment/api". With --watch, the events occurring from then on are displayed
+as they occur, until the command is interrupted.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		err := runGetEvents()
+		if err != nil {
+			logrus.Fatal(err.Error())
+		}
+
+	},
+}
+
+func NewGetEventsCommand() *cobra.Command {
+	addCommonNamespaceCommandFlags(getEventsCmd)
+	getEventsCmd.Flags().BoolVar(&eventsWarnings, "warnings", false, "Only display the warning events")
+	getEventsCmd.Flags().StringVar(&eventsObject, "object", "", "Only display the events about an object (e.g. deployment/api or pod/api-7d9f-x2k)")
+	getEventsCmd.Flags().BoolVarP(&eventsWatch, "watch", "w", false, "Display the events as they occur until the command is interrupted")
+	getEventsCmd.Flags().StringVarP(&output, "output", "o", "text", "Output format (text or json)")
+	return getEventsCmd
+}
+
+func runGetEvents() error {
+
+	if namespace == "" {
+		return errors.New("you must specify a namespace using the --namespace flag")
+	}
+
+	if output != "text" && output != "json" {
+		return fmt.Errorf("unsupported output %q, expected text or json", output)
+	}
+
+	opts := resource.EventOptions{}
+	if eventsWarnings {
+		opts.Type = resource.EventWarning
+	}
+
+	if eventsObject != "" {
+		objects, err := keeperapi.ParseWorkloadRefs([]string{eventsObject})
+		if err != nil {
+			return err
+		}
+		opts.Kind, opts.Name = objects[0].Kind, objects[0].Name
+	}
+
+	api := newNamespaceAPI(newFileClient(playbookDir), namespace)
+
+	if eventsWatch {
+		return watchEvents(api, opts)
+	}
+
+	events, err := api.Events().List(namespace, opts)
+	if err != nil {
+		return fmt.Errorf("an error occurred when getting the events of the namespace : %v", err)
+	}
+
+	if output == "json" {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(events)
+	}
+
+	w := new(tabwriter.Writer)
+	w.Init(os.Stdout, 0, 8, 0, '\t', 0)
+	fmt.Fprintln(w, "Last Seen\tType\tReason\tObject\tCount\tMessage\t")
+	now := time.Now()
+	for _, e := range events {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s/%s\t%d\t%s\t\n",
+			duration.HumanDuration(now.Sub(e.LastSeen)), e.Type, e.Reason, e.Kind, e.Name, e.Count, e.Message)
+	}
+	fmt.Fprintln(w)
+	w.Flush()
+
+	return nil
+
+}
+
+// watchEvents prints the events of the namespace as they occur, one per line, until the command is interrupted
+func watchEvents(api keeperapi.Api, opts resource.EventOptions) error {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	events := make(chan resource.Event)
+	errs := make(chan error, 1)
+
+	go func() {
+		errs <- api.Events().Watch(ctx, namespace, opts, events)
+		close(events)
+	}()
+
+	enc := json.NewEncoder(os.Stdout)
+
+	for e := range events {
+		if output == "json" {
+			if err := enc.Encode(e); err != nil {
+				return err
+			}
+			continue
+		}
+
+		fmt.Printf("%s\t%s\t%s\t%s/%s\t(x%d)\t%s\n",
+			e.LastSeen.Local().Format("15:04:05"), e.Type, e.Reason, e.Kind, e.Name, e.Count, e.Message)
+	}
+
+	return <-errs
+}
@@ -14,7 +14,9 @@ var getNamespacesCmd = &cobra.Command{
 	Use:   "namespaces",
 	Short: "Show information about kubernetes namespaces.",
 	Long: `Show information about Kubernetes namespaces such as names, status (percentage of pods in a running status),
-managed or not with the current playbook, etc.`,
+managed or not with the current playbook, etc.
+
+The namespaces of every cluster targeted by an inventory are listed, unless a cluster is selected using --context.`,
 	Run: func(cmd *cobra.Command, args []string) {
 		err := runGetNamespaces()
 		if err != nil {
@@ -30,18 +32,26 @@ func NewGetNamespacesCommand() *cobra.Command {
 
 func runGetNamespaces() error {
 
-	api := newAPI(newFileClient(playbookDir), newKubernetesClient())
+	clusters := newClusters(newFileClient(playbookDir))
+
+	contexts := []string{kubeContext}
+	if kubeContext == "" {
+		var err error
+		if contexts, err = clusters.Contexts(); err != nil {
+			return err
+		}
+	}
 
-	namespaces, err := api.ListNamespaces()
+	namespaces, err := clusters.ListNamespaces(contexts)
 	if err != nil {
 		return errors.New(fmt.Sprintf("an error occured when getting information about namespaces : %v", err))
 	}
 
 	x := new(tabwriter.Writer)
 	x.Init(os.Stdout, 0, 8, 0, '\t', 0)
-	fmt.Fprintln(x, "Namespace\tPhase\tStatus\tManaged\t")
+	fmt.Fprintln(x, "Cluster\tNamespace\tPhase\tStatus\tManaged\t")
 	for _, namespace := range namespaces {
-		fmt.Fprint(x, fmt.Sprintf("%s\t%s\t%d%%\t%t\t\n", namespace.Name, namespace.Phase, namespace.Status, namespace.Managed))
+		fmt.Fprint(x, fmt.Sprintf("%s\t%s\t%s\t%d%%\t%t\t\n", namespace.Cluster, namespace.Name, namespace.Phase, namespace.Status, namespace.Managed))
 	}
 	fmt.Fprintln(x)
 	x.Flush()
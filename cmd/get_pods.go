@@ -0,0 +1,124 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/util/duration"
+	"sigs.k8s.io/yaml"
+
+	"github.com/DanielPickens/Keeper/pkg/resource"
+)
+
+var getPodsCmd = &cobra.Command{
+	Use:   "pods",
+	Short: "Show the pods of a given namespace.",
+	Long: `This command display the pods of a given namespace with their ready containers, their status, their restarts
+and their age. The status is the reason why a container is waiting or terminated, such as CrashLoopBackOff, when
+there is one.
+
+Use --output wide to also display the IP, the node, the workload owning each pod and the reason why a container of
+the pod last terminated, such as OOMKilled, and --output json or yaml to get the pods with all their containers.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		err := runGetPods()
+		if err != nil {
+			logrus.Fatal(err.Error())
+		}
+
+	},
+}
+
+func NewGetPodsCommand() *cobra.Command {
+	addCommonNamespaceCommandFlags(getPodsCmd)
+	getPodsCmd.Flags().StringVarP(&output, "output", "o", "text", "Output format (text, wide, json or yaml)")
+	return getPodsCmd
+}
+
+func runGetPods() error {
+
+	if namespace == "" {
+		return errors.New("you must specify a namespace using the --namespace flag")
+	}
+
+	if output != "text" && output != "wide" && output != "json" && output != "yaml" {
+		return fmt.Errorf("unsupported output %q, expected text, wide, json or yaml", output)
+	}
+
+	api := newNamespaceAPI(newFileClient(playbookDir), namespace)
+
+	pods, err := api.Pods().List(namespace)
+	if err != nil {
+		return fmt.Errorf("an error occurred when getting the pods of the namespace : %v", err)
+	}
+
+	switch output {
+	case "json":
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(pods)
+	case "yaml":
+		out, err := yaml.Marshal(pods)
+		if err != nil {
+			return err
+		}
+		fmt.Print(string(out))
+		return nil
+	}
+
+	printPods(pods, output == "wide", time.Now())
+
+	return nil
+
+}
+
+// printPods prints a table of the pods, with their IP, node, owner and last termination reason when wide
+func printPods(pods resource.Pods, wide bool, now time.Time) {
+	w := new(tabwriter.Writer)
+	w.Init(os.Stdout, 0, 8, 0, '\t', 0)
+
+	if wide {
+		fmt.Fprintln(w, "Name\tReady\tStatus\tRestarts\tAge\tIP\tNode\tOwner\tLast Termination\t")
+	} else {
+		fmt.Fprintln(w, "Name\tReady\tStatus\tRestarts\tAge\t")
+	}
+
+	for _, pod := range pods {
+		fmt.Fprintf(w, "%s\t%d/%d\t%s\t%d\t%s\t", pod.Name, pod.Ready, pod.Total, podStatus(pod), pod.Restarts,
+			duration.HumanDuration(now.Sub(pod.CreatedAt)))
+
+		if wide {
+			owner := "-"
+			if pod.Owner != nil {
+				owner = fmt.Sprintf("%s/%s", pod.Owner.Kind, pod.Owner.Name)
+			}
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t", orDash(pod.IP), orDash(pod.Node), owner, orDash(pod.LastTerminationReason))
+		}
+
+		fmt.Fprintln(w)
+	}
+
+	fmt.Fprintln(w)
+	w.Flush()
+}
+
+// podStatus returns the reason why a container of a pod is waiting or terminated, such as CrashLoopBackOff,
+// or the phase of the pod when its containers are running or it is succeeded
+func podStatus(pod resource.Pod) string {
+	if pod.Status == "Succeeded" {
+		return pod.Status
+	}
+
+	for _, c := range pod.Containers {
+		if c.State != resource.ContainerRunning && c.Reason != "" {
+			return c.Reason
+		}
+	}
+
+	return pod.Status
+}
@@ -10,11 +10,17 @@ import (
 	"github.com/spf13/cobra"
 )
 
+var servicesOpen bool
+
 var getServicesCmd = &cobra.Command{
 	Use:   "services",
 	Short: "Show information about exposed services from a given namespace.",
 	Long: `This command display informations from a given namespace such as the list of exposed services
-or the url where you can join services through ingress.`,
+or the url where you can join services through ingress.
+
+The URLs of the ingress rules and of the Gateway API HTTPRoutes come first, then the URLs of the load balancers
+and of the node ports, and last the DNS names of the services inside the cluster.
+Use --open to only print the URL to open to reach the namespace from outside of the cluster.`,
 	Run: func(cmd *cobra.Command, args []string) {
 		err := runGetServices()
 		if err != nil {
@@ -26,6 +32,7 @@ or the url where you can join services through ingress.`,
 
 func NewGetServicesCommand() *cobra.Command {
 	addCommonNamespaceCommandFlags(getServicesCmd)
+	getServicesCmd.Flags().BoolVar(&servicesOpen, "open", false, "Only print the primary URL of the namespace")
 	return getServicesCmd
 }
 
@@ -35,21 +42,31 @@ func runGetServices() error {
 		return errors.New("you must specify a namespace using the --namespace flag")
 	}
 
-	api := newAPI(newFileClient(playbookDir), newKubernetesClient())
+	api := newNamespaceAPI(newFileClient(playbookDir), namespace)
 
-	// get exposed services (NodePort, LoadBalancer)
-	services, err := api.ListExposedServices(namespace)
+	urls, err := api.ListServiceURLs(namespace)
 	if err != nil {
 		return errors.New(fmt.Sprintf("an error occurred when getting information about services : %v", err))
 	}
 
+	if servicesOpen {
+		primary, ok := urls.Primary()
+		if !ok {
+			return fmt.Errorf("no externally reachable URL for the services of %s", namespace)
+		}
+		fmt.Println(primary.URL)
+		return nil
+	}
+
 	w := new(tabwriter.Writer)
 	w.Init(os.Stdout, 0, 8, 0, '\t', 0)
-	fmt.Fprintln(w, "Service Name\tAddress\tPort\tExposed Port\t")
-	for _, svc := range services {
-		for _, p := range svc.Ports {
-			fmt.Fprintf(w, fmt.Sprintf("%s\t%s\t%d\t%d\t\n", svc.Name, svc.Addr, p.Port, p.ExposedPort))
+	fmt.Fprintln(w, "Service Name\tPort\tExposed By\tURL\t")
+	for _, u := range urls {
+		exposedBy := string(u.Kind)
+		if u.Source != "" {
+			exposedBy = fmt.Sprintf("%s/%s", u.Kind, u.Source)
 		}
+		fmt.Fprintf(w, "%s\t%d\t%s\t%s\t\n", u.Service, u.Port, exposedBy, u.URL)
 	}
 	fmt.Fprintln(w)
 	w.Flush()
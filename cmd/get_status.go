@@ -0,0 +1,83 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+var getStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Show the detailed status of a given namespace.",
+	Long: `This command display the readiness of each workload (deployments, statefulsets, jobs, daemonsets,
+persistent volume claims, ingresses and cronjobs) of a given namespace and the reasons why pods are failing,
+such as CrashLoopBackOff, ImagePullBackOff, OOMKilled or unschedulable pods, followed by the latest warning events
+of the namespace.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		err := runGetStatus()
+		if err != nil {
+			logrus.Fatal(err.Error())
+		}
+
+	},
+}
+
+func NewGetStatusCommand() *cobra.Command {
+	addCommonNamespaceCommandFlags(getStatusCmd)
+	return getStatusCmd
+}
+
+func runGetStatus() error {
+
+	if namespace == "" {
+		return errors.New("you must specify a namespace using the --namespace flag")
+	}
+
+	api := newNamespaceAPI(newFileClient(playbookDir), namespace)
+
+	status, err := api.GetStatus(namespace)
+	if err != nil {
+		return fmt.Errorf("an error occurred when getting the status of the namespace : %v", err)
+	}
+
+	w := new(tabwriter.Writer)
+	w.Init(os.Stdout, 0, 8, 0, '\t', 0)
+	fmt.Fprintf(w, "Namespace\tPhase\tStatus\t\n")
+	fmt.Fprintf(w, "%s\t%s\t%d%%\t\n", namespace, status.Phase, status.Status)
+	fmt.Fprintln(w)
+
+	fmt.Fprintln(w, "Kind\tName\tReady\tStatus\t")
+	for _, wl := range status.Workloads {
+		state := "NotReady"
+		if wl.IsReady {
+			state = "Ready"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%d/%d\t%s\t\n", wl.Kind, wl.Name, wl.Ready, wl.Desired, state)
+	}
+	fmt.Fprintln(w)
+
+	if len(status.Failures) > 0 {
+		fmt.Fprintln(w, "Pod\tContainer\tReason\tRestarts\tMessage\t")
+		for _, f := range status.Failures {
+			fmt.Fprintf(w, "%s\t%s\t%s\t%d\t%s\t\n", f.Pod, f.Container, f.Reason, f.Restarts, f.Message)
+		}
+		fmt.Fprintln(w)
+	}
+
+	if len(status.Warnings) > 0 {
+		fmt.Fprintln(w, "Warning\tObject\tCount\tMessage\t")
+		for _, e := range status.Warnings {
+			fmt.Fprintf(w, "%s\t%s/%s\t%d\t%s\t\n", e.Reason, e.Kind, e.Name, e.Count, e.Message)
+		}
+		fmt.Fprintln(w)
+	}
+
+	w.Flush()
+
+	return nil
+
+}
@@ -0,0 +1,87 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+
+	"github.com/DanielPickens/Keeper/pkg/playbook"
+)
+
+var historyCmd = &cobra.Command{
+	Use:   "history",
+	Short: "List the releases of a namespace",
+	Long: `This command lists the releases of a namespace : each apply of the namespace is recorded as a new release,
+with a snapshot of its inventory and of its rendered configs, the version of the playbook, who applied it and whether
+the apply succeeded. Use "rollback" to apply a previous release again.
+	`,
+	Run: func(cmd *cobra.Command, args []string) {
+		err := runHistory(namespace)
+		if err != nil {
+			logrus.Fatal(err)
+		}
+	},
+}
+
+func NewHistoryCommand() *cobra.Command {
+	addCommonNamespaceCommandFlags(historyCmd)
+	return historyCmd
+}
+
+func runHistory(namespace string) error {
+
+	if namespace == "" {
+		return errors.New("you must specified a namespace using the --namespace flag")
+	}
+
+	api := newNamespaceAPI(newFileClient(playbookDir), namespace)
+
+	records, err := api.History(namespace)
+	if err != nil {
+		return err
+	}
+
+	if len(records) == 0 {
+		logrus.WithFields(logrus.Fields{
+			"namespace": namespace,
+		}).Info("No release found")
+		return nil
+	}
+
+	printHistory(records)
+
+	return nil
+}
+
+// printHistory displays the releases of a namespace
+func printHistory(records []playbook.ReleaseRecord) {
+	w := new(tabwriter.Writer)
+	w.Init(os.Stdout, 0, 8, 0, '\t', 0)
+	fmt.Fprintln(w, "Revision\tDate\tStatus\tActor\tPlaybook\tDescription\t")
+	for _, r := range records {
+		description := "apply"
+		if r.RollbackOf > 0 {
+			description = fmt.Sprintf("rollback to %d", r.RollbackOf)
+		}
+		if r.Error != "" {
+			// only the first line of the error, the failed objects are listed on the following ones
+			description = fmt.Sprintf("%s: %s", description, strings.SplitN(r.Error, "\n", 2)[0])
+		}
+		fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%s\t%s\t\n", r.Revision, r.Date.Format("2006-01-02 15:04:05"), r.Status, orDash(r.Actor), orDash(r.PlaybookVersion), description)
+	}
+	fmt.Fprintln(w)
+	w.Flush()
+}
+
+// orDash returns the value, or a dash if it is empty
+func orDash(value string) string {
+	if value == "" {
+		return "-"
+	}
+	return value
+}
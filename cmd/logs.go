@@ -1,45 +1,133 @@
-package cmd
-
-import (
-	"context"
-	"fmt"
-
-	"github.com/creasty/defaults"
-	"github.com/rs/zerolog/log"
-	"github.com/spf13/cobra"
-)
-
-var logsCmd = &cobra.Command{
-	Use:   "logs",
-	Short: "Create a ZIP file with logs for GitHub issues or troubleshooting",
-	RunE: func(cmd *cobra.Command, args []string) error {
-		kubernetesProvider, err := getKubernetesProviderForCli()
-		if err != nil {
-			return nil
-		}
-		ctx := context.Background()
-
-		if validationErr := config.Config.Logs.Validate(); validationErr != nil {
-			return errormessage.FormatError(validationErr)
-		}
-
-		log.Debug().Str("logs-path", config.Config.Logs.FilePath()).Msg("Using this logs path...")
-
-		if dumpLogsErr := fsUtils.DumpLogs(ctx, kubernetesProvider, config.Config.Logs.FilePath()); dumpLogsErr != nil {
-			log.Error().Err(dumpLogsErr).Msg("Failed to dump logs.")
-		}
-
-		return nil
-	},
-}
-
-func init() {
-	rootCmd.AddCommand(logsCmd)
-
-	defaultLogsConfig := configStructs.LogsConfig{}
-	if err := defaults.Set(&defaultLogsConfig); err != nil {
-		log.Debug().Err(err).Send()
-	}
-
-	logsCmd.Flags().StringP(configStructs.FileLogsName, "f", defaultLogsConfig.FileStr, fmt.Sprintf("Path for zip file (default current <pwd>\\%s_logs.zip)", misc.Program))
-}
\ No newline at end of file
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+
+	keeperapi "github.com/DanielPickens/Keeper/pkg/api"
+	"github.com/DanielPickens/Keeper/pkg/resource"
+)
+
+var (
+	logsSelector string
+	logsWorkload string
+	logsSince    time.Duration
+	logsPrevious bool
+	logsFollow   bool
+)
+
+// logColors are the ANSI colors of the prefixes of the log lines, picked for each pod and container
+var logColors = []int{31, 32, 33, 34, 35, 36, 91, 92, 93, 94, 95, 96}
+
+var logsCmd = &cobra.Command{
+	Use:   "logs",
+	Short: "Stream the logs of the pods of a namespace",
+	Long: `This command streams the logs of all the containers of the pods of a namespace at once, each line being
+prefixed with its pod and its container. Use --selector or --workload (e.g. deployment/api) to restrict the pods.
+
+By default, the logs are followed until the command is interrupted, including the logs of the pods started afterwards.
+Use --output json to get one JSON object per line, with the pod, the container, the time and the message.
+	`,
+	Run: func(cmd *cobra.Command, args []string) {
+		err := runLogs(namespace)
+		if err != nil {
+			logrus.Fatal(err)
+		}
+	},
+}
+
+func NewLogsCommand() *cobra.Command {
+	addCommonNamespaceCommandFlags(logsCmd)
+	logsCmd.Flags().StringVarP(&logsSelector, "selector", "l", "", "Only stream the logs of the pods matching the label selector (e.g. app=api)")
+	logsCmd.Flags().StringVarP(&logsWorkload, "workload", "w", "", "Only stream the logs of the pods of the workload (e.g. deployment/api or job/migrate)")
+	logsCmd.Flags().DurationVar(&logsSince, "since", 0, "Only stream the logs newer than a duration (e.g. 10m)")
+	logsCmd.Flags().BoolVarP(&logsPrevious, "previous", "p", false, "Stream the logs of the previous instance of the restarted containers")
+	logsCmd.Flags().BoolVarP(&logsFollow, "follow", "f", true, "Keep streaming the logs until the command is interrupted")
+	logsCmd.Flags().StringVarP(&output, "output", "o", "text", "Output format (text or json)")
+	return logsCmd
+}
+
+func runLogs(namespace string) error {
+
+	if namespace == "" {
+		return errors.New("you must specified a namespace using the --namespace flag")
+	}
+
+	if output != "text" && output != "json" {
+		return fmt.Errorf("unsupported output %q, expected text or json", output)
+	}
+
+	opts := keeperapi.LogsOptions{
+		Selector: logsSelector,
+		Since:    logsSince,
+		Previous: logsPrevious,
+		Follow:   logsFollow,
+	}
+
+	if logsWorkload != "" {
+		workloads, err := keeperapi.ParseWorkloadRefs([]string{logsWorkload})
+		if err != nil {
+			return err
+		}
+		opts.Workload = &workloads[0]
+	}
+
+	api := newNamespaceAPI(newFileClient(playbookDir), namespace)
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	lines := make(chan resource.LogLine)
+	errs := make(chan error, 1)
+
+	go func() {
+		errs <- api.Logs(ctx, namespace, opts, lines)
+		close(lines)
+	}()
+
+	enc := json.NewEncoder(os.Stdout)
+	colored := isTerminal(os.Stdout)
+
+	for line := range lines {
+		if output == "json" {
+			if err := enc.Encode(line); err != nil {
+				return err
+			}
+			continue
+		}
+
+		fmt.Println(logPrefix(line, colored), line.Message)
+	}
+
+	return <-errs
+}
+
+// logPrefix returns the prefix of a log line naming its pod and its container,
+// colored with a color specific to the pod and the container if requested
+func logPrefix(line resource.LogLine, colored bool) string {
+	prefix := fmt.Sprintf("[%s/%s]", line.Pod, line.Container)
+	if !colored {
+		return prefix
+	}
+
+	h := fnv.New32a()
+	h.Write([]byte(prefix))
+
+	return fmt.Sprintf("\x1b[%dm%s\x1b[0m", logColors[h.Sum32()%uint32(len(logColors))], prefix)
+}
+
+// isTerminal returns true if the file is a terminal
+func isTerminal(f *os.File) bool {
+	fi, err := f.Stat()
+	return err == nil && fi.Mode()&os.ModeCharDevice != 0
+}
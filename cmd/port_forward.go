@@ -0,0 +1,94 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"text/tabwriter"
+
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+
+	keeperapi "github.com/DanielPickens/Keeper/pkg/api"
+	"github.com/DanielPickens/Keeper/pkg/resource"
+)
+
+var (
+	forwardAll     bool
+	forwardAddress string
+)
+
+var portForwardCmd = &cobra.Command{
+	Use:   "port-forward [service] [LOCAL_PORT:]PORT...",
+	Short: "Forward the ports of services of a namespace to localhost",
+	Long: `This command forwards the ports of a service of a namespace to localhost, through one of the ready pods of the
+service, until the command is interrupted. By default, all the ports of the service are forwarded on the same local
+ports. Pick some of the ports, and their local ports, with LOCAL_PORT:PORT arguments where PORT is a port of the service,
+e.g. "keeper port-forward -n ns api 8080:80". An empty local port, as in ":80", is chosen by the system.
+
+Use --all to forward all the services of the namespace having a ready pod, on local ports chosen by the system.
+The forwarded ports are printed once they listen.
+	`,
+	Run: func(cmd *cobra.Command, args []string) {
+		err := runPortForward(namespace, args)
+		if err != nil {
+			logrus.Fatal(err)
+		}
+	},
+}
+
+func NewPortForwardCommand() *cobra.Command {
+	addCommonNamespaceCommandFlags(portForwardCmd)
+	portForwardCmd.Flags().BoolVar(&forwardAll, "all", false, "Forward all the services of the namespace on local ports chosen by the system")
+	portForwardCmd.Flags().StringVar(&forwardAddress, "address", "localhost", "The local address to listen on")
+	return portForwardCmd
+}
+
+func runPortForward(namespace string, args []string) error {
+
+	if namespace == "" {
+		return errors.New("you must specified a namespace using the --namespace flag")
+	}
+
+	opts := keeperapi.PortForwardOptions{Address: forwardAddress}
+
+	switch {
+	case forwardAll && len(args) > 0:
+		return errors.New("a service cannot be given with --all")
+	case !forwardAll && len(args) == 0:
+		return errors.New("you must specify a service, or --all to forward all the services")
+	case !forwardAll:
+		ports, err := keeperapi.ParsePortMappings(args[1:])
+		if err != nil {
+			return err
+		}
+		opts.Services = []string{args[0]}
+		opts.Ports = ports
+	}
+
+	api := newNamespaceAPI(newFileClient(playbookDir), namespace)
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	return api.PortForward(ctx, namespace, opts, func(ports []resource.ForwardedPort) {
+		printForwardedPorts(ports, forwardAddress)
+	})
+}
+
+// printForwardedPorts prints the local address of each forwarded port of the services
+func printForwardedPorts(ports []resource.ForwardedPort, address string) {
+	w := new(tabwriter.Writer)
+	w.Init(os.Stdout, 0, 8, 0, '\t', 0)
+	fmt.Fprintln(w, "Service Name\tPort\tPod\tLocal Address\t")
+	for _, p := range ports {
+		fmt.Fprintf(w, "%s\t%d\t%s\t%s:%d\t\n", p.Service, p.Port, p.Pod, address, p.LocalPort)
+	}
+	fmt.Fprintln(w)
+	w.Flush()
+
+	fmt.Println("Forwarding, press Ctrl+C to stop")
+}
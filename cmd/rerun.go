@@ -0,0 +1,44 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"text/template"
+
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+// rerunCmd represents the rerun command
+var rerunCmd = &cobra.Command{
+	Use:   "rerun [command]",
+	Short: "Run an object again",
+	Long:  `Run an object of a namespace again from the rendered configs of the namespace, such as a seed or a migration job.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		runRerun()
+	},
+}
+
+func NewRerunCommand() *cobra.Command {
+	rerunCmd.AddCommand(NewRerunJobCommand())
+
+	return rerunCmd
+}
+
+func runRerun() {
+	tpl := template.Must(template.New("rerunCmd").Parse(`
+Using the rerun command with a sub-command is helpful. Please use one of the following sub-command :
+{{range . -}}
+- {{.}}
+{{end -}}
+`))
+
+	data := []string{"rerun job"}
+
+	contents := bytes.Buffer{}
+	if err := tpl.Execute(&contents, data); err != nil {
+		logrus.Fatalf("error while executing template : %v", err)
+	}
+
+	fmt.Println(contents.String())
+}
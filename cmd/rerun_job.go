@@ -0,0 +1,80 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+
+	keeperapi "github.com/DanielPickens/Keeper/pkg/api"
+	"github.com/DanielPickens/Keeper/pkg/resource"
+)
+
+var rerunJobCmd = &cobra.Command{
+	Use:   "job [NAME]",
+	Short: "Run a job of a namespace again",
+	Long: `Delete a job of a namespace, with its pods, and create it again from the rendered configs of the namespace.
+
+Use --wait to wait until the job is completed or failed while streaming its logs. The command fails if the job failed.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		err := runRerunJob(args[0])
+		if err != nil {
+			logrus.Fatal(err.Error())
+		}
+	},
+}
+
+func NewRerunJobCommand() *cobra.Command {
+	addCommonNamespaceCommandFlags(rerunJobCmd)
+	rerunJobCmd.Flags().BoolVar(&wait, "wait", false, "wait until the job is completed or failed, streaming its logs")
+	rerunJobCmd.Flags().DurationVarP(&timeout, "timeout", "t", defaultTimeout, "The max time to wait for the job to complete.")
+	return rerunJobCmd
+}
+
+func runRerunJob(name string) error {
+	if namespace == "" {
+		return errors.New("you must specify a namespace using the --namespace flag")
+	}
+
+	files := newFileClient(playbookDir)
+	api := newNamespaceAPI(files, namespace)
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	job, err := api.RerunJob(ctx, namespace, files.ConfigPath(), name, keeperapi.RerunOptions{
+		Wait:    wait,
+		Timeout: timeout,
+		Output:  os.Stdout,
+	})
+	if err == context.DeadlineExceeded {
+		return fmt.Errorf("the job %s did not complete within %s", name, timeout)
+	}
+	if err != nil {
+		return fmt.Errorf("an error occurred when running the job again : %v", err)
+	}
+
+	fields := logrus.Fields{
+		"namespace": namespace,
+		"job":       job.Name,
+	}
+
+	if !wait {
+		logrus.WithFields(fields).Info("job created")
+		return nil
+	}
+
+	if job.Status == resource.JobFailed {
+		return fmt.Errorf("the job %s failed: %s", job.Name, job.Failure())
+	}
+
+	logrus.WithFields(fields).Info("job completed")
+
+	return nil
+}
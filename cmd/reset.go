@@ -34,7 +34,7 @@ func runReset(namespace string) error {
 
 	files := newFileClient(playbookDir)
 
-	api := newAPI(files, newKubernetesClient())
+	api := newNamespaceAPI(files, namespace)
 
 	//Reset inventory file
 	err := api.Reset(namespace, files.ConfigPath())
@@ -0,0 +1,63 @@
+package cmd
+
+import (
+	"errors"
+
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+
+	keeperapi "github.com/DanielPickens/Keeper/pkg/api"
+)
+
+var rollbackRevision int
+
+var rollbackCmd = &cobra.Command{
+	Use:   "rollback",
+	Short: "Apply a previous release of a namespace again",
+	Long: `This command restores the inventory and the configs of a previous release of the namespace, as they were
+released, and applies them. The rollback is recorded as a new release in the history of the namespace.
+
+Use --to to choose the revision listed by the "history" command. By default, the namespace is rolled back to the last
+successfully deployed release before the current one.
+	`,
+	Run: func(cmd *cobra.Command, args []string) {
+		err := runRollback(namespace)
+		if err != nil {
+			logrus.Fatal(err)
+		}
+	},
+}
+
+func NewRollbackCommand() *cobra.Command {
+	addCommonNamespaceCommandFlags(rollbackCmd)
+	rollbackCmd.Flags().IntVar(&rollbackRevision, "to", 0, "The revision to roll back to. Default is the last deployed release before the current one.")
+	rollbackCmd.Flags().BoolVar(&prune, "prune", true, "delete the objects that are not part of the configs of the release")
+	return rollbackCmd
+}
+
+func runRollback(namespace string) error {
+
+	if namespace == "" {
+		return errors.New("you must specified a namespace using the --namespace flag")
+	}
+
+	if rollbackRevision < 0 {
+		return errors.New("the revision must be positive")
+	}
+
+	files := newFileClient(playbookDir)
+	api := newNamespaceAPI(files, namespace)
+
+	record, results, err := api.Rollback(namespace, files.ConfigPath(), rollbackRevision, keeperapi.ApplyOptions{Prune: prune, Actor: currentActor()})
+	printApplyResults(results)
+	if err != nil {
+		return err
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"namespace": namespace,
+		"revision":  record.Revision,
+	}).Infof("Namespace has been rolled back to revision %d", record.RollbackOf)
+
+	return nil
+}
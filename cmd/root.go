@@ -5,23 +5,40 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"os/user"
 	"strings"
 	"time"
 
 	"github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
+
+	"github.com/DanielPickens/Keeper/pkg/api"
+	"github.com/DanielPickens/Keeper/pkg/files"
+	"github.com/DanielPickens/Keeper/pkg/kubernetes"
 )
 
 var (
 	cfgFile           string
 	playbookDir       string
 	kubectlConfigPath string
+	kubeContext       string
 	v                 string
 	namespace         string
 	cors              bool
 	wait              bool
 	timeout           time.Duration
+	failFast          bool
+	maxRestarts       int32
+	waitFor           []string
+	runTests          bool
+	testTimeout       time.Duration
+	junitFile         string
+	forceFinalizers   bool
+	dryRun            bool
+	prune             bool
+	output            string
+	gcInterval        time.Duration
 	port              int
 )
 
@@ -52,17 +69,31 @@ func NewKeeperCommand() *cobra.Command {
 		return nil
 	}
 
+	rootCmd.AddCommand(NewAdoptCommand())
 	rootCmd.AddCommand(NewServeCommand())
 	rootCmd.AddCommand(NewApplyCommand())
+	rootCmd.AddCommand(NewCostCommand())
 	rootCmd.AddCommand(NewCreateCommand())
+	rootCmd.AddCommand(NewCronJobCommand())
 	rootCmd.AddCommand(NewDeleteCommand())
+	rootCmd.AddCommand(NewDiffCommand())
+	rootCmd.AddCommand(NewGCCommand())
 	rootCmd.AddCommand(NewGetCommand())
+	rootCmd.AddCommand(NewHistoryCommand())
+	rootCmd.AddCommand(NewLogsCommand())
+	rootCmd.AddCommand(NewPortForwardCommand())
+	rootCmd.AddCommand(NewRerunCommand())
 	rootCmd.AddCommand(NewResetCommand())
+	rootCmd.AddCommand(NewRollbackCommand())
+	rootCmd.AddCommand(NewSupportBundleCommand())
+	rootCmd.AddCommand(NewTestCommand())
+	rootCmd.AddCommand(NewTopCommand())
 	rootCmd.AddCommand(NewVersionCommand())
 
 	rootCmd.PersistentFlags().StringVar(&cfgFile, "config", "", "config file (default is $HOME/.keeper.yaml)")
 	rootCmd.PersistentFlags().StringVar(&playbookDir, "dir", "", "Use the specified directory as root path to execute commands. Default is the current directory.")
 	rootCmd.PersistentFlags().StringVar(&kubectlConfigPath, "kube-config-path", kubernetes.KubeConfigDefaultPath(), "kubectl config file")
+	rootCmd.PersistentFlags().StringVar(&kubeContext, "context", "", "The kubectl config context of the cluster to use. Default is the context of the namespace inventory, or the current context.")
 	rootCmd.PersistentFlags().StringVarP(&v, "verbosity", "v", logrus.InfoLevel.String(), "Log level (debug, info, warn, error, fatal, panic")
 
 	viper.BindPFlag("working-dir", rootCmd.PersistentFlags().Lookup("dir"))
@@ -126,8 +157,38 @@ func askForConfirmation(message string, reader io.Reader) bool {
 	}
 }
 
+// askForChoice asks to pick one of the choices until a valid one is entered. The first choice is the default one.
+func askForChoice(message string, choices []string, reader io.Reader) string {
+
+	r := bufio.NewReader(reader)
+
+	for {
+		fmt.Printf("%s [%s]: ", message, strings.Join(choices, "/"))
+
+		response, err := r.ReadString('\n')
+		response = strings.ToLower(strings.TrimSpace(response))
+
+		if response == "" {
+			if err != nil {
+				logrus.Fatal(err)
+			}
+			return choices[0]
+		}
+
+		for _, c := range choices {
+			if response == c {
+				return c
+			}
+		}
+
+		if err != nil {
+			logrus.Fatal(err)
+		}
+	}
+}
+
 func newKubernetesClient() *kubernetes.Client {
-	kube, err := kubernetes.NewClient(kubectlConfigPath)
+	kube, err := kubernetes.NewClient(kubectlConfigPath, kubeContext)
 	if err != nil {
 		logrus.Fatal(err.Error())
 	}
@@ -135,6 +196,54 @@ func newKubernetesClient() *kubernetes.Client {
 	return kube
 }
 
+// newClusters returns the apis of the clusters of the kubectl config file
+func newClusters(files *files.Client) *api.Clusters {
+	pool := kubernetes.NewClientPool(kubectlConfigPath)
+
+	_, current, err := pool.Contexts()
+	if err != nil {
+		// no kubectl config file, such as in a pod : only the in-cluster config can be used
+		logrus.Debug(err.Error())
+	}
+
+	return api.NewClusters(current, func(context string) (api.Api, error) {
+		kube, err := pool.Get(context)
+		if err != nil {
+			return nil, err
+		}
+		return newAPI(files, kube)
+	})
+}
+
+// newNamespaceAPI returns the api of the cluster hosting the namespace :
+// the cluster of the --context flag if set, the cluster targeted by the namespace inventory otherwise.
+func newNamespaceAPI(files *files.Client, namespace string) api.Api {
+	clusters := newClusters(files)
+
+	var a api.Api
+	var err error
+
+	if kubeContext != "" {
+		a, err = clusters.Get(kubeContext)
+	} else {
+		a, err = clusters.ForNamespace(namespace)
+	}
+	if err != nil {
+		logrus.Fatal(err.Error())
+	}
+
+	return a
+}
+
+// currentActor returns the name of the user running keeper, recorded in the release history of the namespaces
+func currentActor() string {
+	if u, err := user.Current(); err == nil && u.Username != "" {
+		return u.Username
+	}
+
+	return os.Getenv("USER")
+}
+
 func newFileClient(dir string) *files.Client {
 	f, err := files.NewClient(dir)
 	if err != nil {
@@ -145,14 +254,27 @@ func newFileClient(dir string) *files.Client {
 
 }
 
-func newAPI(files *files.Client, kube *kubernetes.Client) api.Api {
+func newAPI(files *files.Client, kube *kubernetes.Client) (api.Api, error) {
 	return api.NewApi(
 		files.Inventories(),
 		files.Configs(),
+		files.History(),
 		files.Playbooks(),
 		kube.Namespaces(),
 		kube.Pods(),
 		kube.Deployments(),
+		kube.Statefulsets(),
+		kube.Services(),
+		kube.Cluster(),
+		kube.Jobs(),
+		kube.Daemonsets(),
+		kube.PersistentVolumeClaims(),
+		kube.Ingresses(),
+		kube.CronJobs(),
+		kube.CustomResources(),
+		kube.Bundles(),
+		kube.Events(),
+		kube.Usages(),
 	)
 }
 
@@ -13,3 +13,11 @@ func TestAskForConfirmation(t *testing.T) {
 	retYes := askForConfirmation("test", strings.NewReader("yes\n"))
 	assert.True(t, retYes)
 }
+
+func TestAskForChoice(t *testing.T) {
+	choices := []string{"skip", "adopt", "delete"}
+
+	assert.Equal(t, "adopt", askForChoice("test", choices, strings.NewReader("Adopt\n")))
+	assert.Equal(t, "skip", askForChoice("test", choices, strings.NewReader("\n")))
+	assert.Equal(t, "delete", askForChoice("test", choices, strings.NewReader("nope\ndelete\n")))
+}
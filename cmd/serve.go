@@ -1,9 +1,13 @@
 package cmd
 
 import (
+	"time"
+
+	"github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 
-	"github.com/danielpickens/keeper/pkg/http"
+	keeperapi "github.com/DanielPickens/Keeper/pkg/api"
+	"github.com/DanielPickens/Keeper/pkg/http"
 )
 
 // serveCmd represents the serve command
@@ -20,6 +24,7 @@ This API let the client use all the features provided by Keeper such as create a
 func NewServeCommand() *cobra.Command {
 	serveCmd.Flags().BoolVar(&cors, "cors", false, "Enable cors")
 	serveCmd.Flags().IntVar(&port, "port", 8080, "Use a specific port")
+	serveCmd.Flags().DurationVar(&gcInterval, "gc-interval", 0, "Report orphan namespaces, inventories and configs at the given interval. Disabled by default")
 
 	return serveCmd
 }
@@ -27,13 +32,42 @@ func NewServeCommand() *cobra.Command {
 func runServe() {
 	files := newFileClient(playbookDir)
 
-	api := newAPI(files, newKubernetesClient())
+	clusters := newClusters(files)
+
+	api, err := clusters.Get(kubeContext)
+	if err != nil {
+		logrus.Fatal(err.Error())
+	}
 
 	go api.WatchNamespaceDeleted()
 
-	h := http.NewHandler(api, files.ConfigPath(), cors)
+	if gcInterval > 0 {
+		go reportOrphans(clusters, gcInterval)
+	}
+
+	h := http.NewHandler(api, clusters, files.ConfigPath(), cors)
 	s := http.NewServer(h)
 
 	// start http web server
 	s.Serve(port)
 }
+
+// reportOrphans periodically logs the orphan namespaces, inventories and configs.
+// Orphans are only reported : they are resolved using the gc command.
+func reportOrphans(clusters *keeperapi.Clusters, interval time.Duration) {
+	for range time.Tick(interval) {
+		orphans, err := clusters.FindOrphans()
+		if err != nil {
+			logrus.Errorf("unable to find orphans : %v", err)
+			continue
+		}
+
+		for _, orphan := range orphans {
+			logrus.WithFields(logrus.Fields{
+				"namespace": orphan.Namespace,
+				"kind":      orphan.Kind,
+				"context":   orphan.Context,
+			}).Warn("orphan found, use the gc command to resolve it")
+		}
+	}
+}
@@ -0,0 +1,81 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+
+	keeperapi "github.com/DanielPickens/Keeper/pkg/api"
+)
+
+const defaultBundleTailLines = 500
+
+var (
+	bundleFile      string
+	bundleTailLines int64
+)
+
+var supportBundleCmd = &cobra.Command{
+	Use:   "support-bundle",
+	Short: "Create a ZIP file to troubleshoot a namespace or attach to an incident ticket",
+	Long: `This command creates a ZIP file gathering the keeper and the kubernetes versions, the inventory and the rendered
+configs of the namespace, its events, the YAML of its workloads and of the objects they use, the description of its
+pods and the last log lines of each of their containers.
+
+The values of the secrets, and the inventory values whose key looks like a credential (password, token...), are
+redacted. Still, review the bundle before sharing it.
+	`,
+	Run: func(cmd *cobra.Command, args []string) {
+		err := runSupportBundle(namespace)
+		if err != nil {
+			logrus.Fatal(err)
+		}
+	},
+}
+
+func NewSupportBundleCommand() *cobra.Command {
+	addCommonNamespaceCommandFlags(supportBundleCmd)
+	supportBundleCmd.Flags().StringVarP(&bundleFile, "file", "f", "", "Path of the ZIP file (default keeper-<namespace>-<date>.zip in the current directory)")
+	supportBundleCmd.Flags().Int64Var(&bundleTailLines, "tail", defaultBundleTailLines, "The number of the last log lines collected for each container")
+	return supportBundleCmd
+}
+
+func runSupportBundle(namespace string) error {
+
+	if namespace == "" {
+		return errors.New("you must specified a namespace using the --namespace flag")
+	}
+
+	file := bundleFile
+	if file == "" {
+		file = fmt.Sprintf("keeper-%s-%s.zip", namespace, time.Now().Format("20060102-150405"))
+	}
+
+	api := newNamespaceAPI(newFileClient(playbookDir), namespace)
+
+	f, err := os.Create(file)
+	if err != nil {
+		return fmt.Errorf("unable to create the support bundle: %v", err)
+	}
+
+	err = api.SupportBundle(context.Background(), namespace, keeperapi.SupportBundleOptions{TailLines: bundleTailLines}, f)
+	if cerr := f.Close(); err == nil && cerr != nil {
+		err = fmt.Errorf("unable to write the support bundle: %v", cerr)
+	}
+	if err != nil {
+		os.Remove(file)
+		return err
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"namespace": namespace,
+		"file":      file,
+	}).Info("Support bundle created")
+
+	return nil
+}
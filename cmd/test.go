@@ -0,0 +1,118 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+
+	keeperapi "github.com/DanielPickens/Keeper/pkg/api"
+)
+
+const (
+	defaultTestTimeout = 5 * time.Minute
+)
+
+var testCmd = &cobra.Command{
+	Use:   "test",
+	Short: "Run the smoke tests of the playbook against a given namespace",
+	Long: `This command renders the smoke tests found in the "tests" directory of the playbook using the inventory
+of the given namespace, and runs them against the namespace.
+
+Each template of the "tests" directory renders a Kubernetes Job. The Job is run in the namespace, its logs are streamed,
+and the test passes if the Job completes. The "probes.json.tpl" template renders a list of HTTP probes
+sent to the services exposed by the namespace, such as :
+
+	[{"name": "api-health", "service": "api", "port": 80, "path": "/health", "expectedStatus": 200}]
+
+The command fails if a test fails. Use --junit to write a JUnit XML report.
+	`,
+	Run: func(cmd *cobra.Command, args []string) {
+		err := runTest(namespace)
+		if err != nil {
+			logrus.Fatal(err)
+		}
+	},
+}
+
+func NewTestCommand() *cobra.Command {
+	addCommonNamespaceCommandFlags(testCmd)
+	testCmd.Flags().DurationVar(&testTimeout, "timeout", defaultTestTimeout, "The max time to wait for each test job to complete.")
+	testCmd.Flags().StringVar(&junitFile, "junit", "", "Write a JUnit XML report of the tests to the given file.")
+
+	return testCmd
+}
+
+func runTest(namespace string) error {
+
+	if namespace == "" {
+		return errors.New("you must specified a namespace using the --namespace flag")
+	}
+
+	api := newNamespaceAPI(newFileClient(playbookDir), namespace)
+
+	return runSmokeTests(api, namespace)
+}
+
+// runSmokeTests runs the smoke tests of a namespace, displays their results and writes the JUnit report if requested.
+// An error is returned if a test failed.
+func runSmokeTests(api keeperapi.Api, namespace string) error {
+	logrus.WithFields(logrus.Fields{
+		"namespace": namespace,
+	}).Info("Running smoke tests...")
+
+	report, err := api.RunSmokeTests(context.Background(), namespace, keeperapi.TestOptions{
+		Timeout: testTimeout,
+		Output:  os.Stdout,
+	})
+	if err != nil {
+		return err
+	}
+
+	if len(report.Results) == 0 {
+		logrus.WithFields(logrus.Fields{
+			"namespace": namespace,
+		}).Warn("No smoke test found in the playbook")
+		return nil
+	}
+
+	w := new(tabwriter.Writer)
+	w.Init(os.Stdout, 0, 8, 0, '\t', 0)
+	fmt.Fprintln(w, "Test\tKind\tResult\tDuration\tMessage\t")
+	for _, r := range report.Results {
+		result := "PASS"
+		if !r.Passed {
+			result = "FAIL"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t\n", r.Name, r.Kind, result, r.Duration.Round(time.Millisecond), r.Message)
+	}
+	fmt.Fprintln(w)
+	w.Flush()
+
+	if junitFile != "" {
+		f, err := os.Create(junitFile)
+		if err != nil {
+			return fmt.Errorf("unable to create junit report: %v", err)
+		}
+		defer f.Close()
+
+		if err := report.WriteJUnit(f); err != nil {
+			return err
+		}
+	}
+
+	if !report.Passed() {
+		return fmt.Errorf("%d of %d smoke tests failed", report.Failed(), len(report.Results))
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"namespace": namespace,
+	}).Info("All smoke tests passed")
+
+	return nil
+}
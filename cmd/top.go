@@ -0,0 +1,95 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"text/tabwriter"
+
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+
+	"github.com/DanielPickens/Keeper/pkg/resource"
+)
+
+var topSortBy string
+
+var topCmd = &cobra.Command{
+	Use:   "top",
+	Short: "Show the CPU and the memory used by the pods of a namespace",
+	Long: `This command displays the CPU, in millicores, and the memory used by each pod of a namespace, as reported by
+the metrics server of the cluster, along with the resources the pod requests.
+
+Use --sort-by cpu or --sort-by memory to display the pods using the most resources first.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		err := runTop()
+		if err != nil {
+			logrus.Fatal(err.Error())
+		}
+	},
+}
+
+func NewTopCommand() *cobra.Command {
+	addCommonNamespaceCommandFlags(topCmd)
+	topCmd.Flags().StringVar(&topSortBy, "sort-by", "", "Sort the pods by the resource they use (cpu or memory)")
+	topCmd.Flags().StringVarP(&output, "output", "o", "text", "Output format (text or json)")
+	return topCmd
+}
+
+func runTop() error {
+
+	if namespace == "" {
+		return errors.New("you must specify a namespace using the --namespace flag")
+	}
+
+	if output != "text" && output != "json" {
+		return fmt.Errorf("unsupported output %q, expected text or json", output)
+	}
+
+	if topSortBy != "" && topSortBy != "cpu" && topSortBy != "memory" {
+		return fmt.Errorf("unsupported sort %q, expected cpu or memory", topSortBy)
+	}
+
+	api := newNamespaceAPI(newFileClient(playbookDir), namespace)
+
+	usages, err := api.Usages().Top(namespace)
+	if err != nil {
+		return err
+	}
+
+	switch topSortBy {
+	case "cpu":
+		sort.SliceStable(usages, func(i, j int) bool { return usages[i].CPU > usages[j].CPU })
+	case "memory":
+		sort.SliceStable(usages, func(i, j int) bool { return usages[i].Memory > usages[j].Memory })
+	}
+
+	if output == "json" {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(usages)
+	}
+
+	printUsages(usages)
+
+	return nil
+}
+
+// printUsages prints the CPU and the memory used and requested by each pod
+func printUsages(usages []resource.PodUsage) {
+	w := new(tabwriter.Writer)
+	w.Init(os.Stdout, 0, 8, 0, '\t', 0)
+	fmt.Fprintln(w, "Pod\tCPU\tCPU Requests\tMemory\tMemory Requests\t")
+	for _, u := range usages {
+		fmt.Fprintf(w, "%s\t%dm\t%dm\t%s\t%s\t\n", u.Name, u.CPU, u.CPURequest, formatMemory(u.Memory), formatMemory(u.MemoryRequest))
+	}
+	fmt.Fprintln(w)
+	w.Flush()
+}
+
+// formatMemory formats a quantity of memory in bytes as mebibytes
+func formatMemory(bytes int64) string {
+	return fmt.Sprintf("%dMi", bytes/(1024*1024))
+}
@@ -0,0 +1,88 @@
+package api
+
+import (
+	"github.com/sirupsen/logrus"
+
+	"github.com/DanielPickens/Keeper/pkg/playbook"
+	"github.com/DanielPickens/Keeper/pkg/resource"
+)
+
+// AdoptOptions defines how an existing namespace is adopted.
+// When Detect is true, the inventory is seeded with the replicas and the images of the deployments and statefulsets
+// running in the namespace instead of the playbook defaults.
+type AdoptOptions struct {
+	Detect bool
+}
+
+// Adopt brings an existing kubernetes namespace under keeper management, without applying anything to it :
+// an inventory and its configs are created from the playbook defaults, then the namespace is labeled as managed.
+// If the namespace already has an inventory, it is kept as is. The created inventory and its configs are deleted
+// when the namespace cannot be adopted, such as when it does not exist.
+func (api *api) Adopt(namespace string, opts AdoptOptions) (playbook.Inventory, error) {
+	inv, created, err := api.adoptInventory(namespace, opts)
+	if err == nil {
+		err = api.configs.Generate(inv)
+	}
+	if err == nil {
+		err = api.namespaces.Adopt(namespace)
+	}
+
+	if err != nil {
+		if created {
+			api.deletePlaybook(namespace)
+		}
+		return playbook.Inventory{}, err
+	}
+
+	return inv, nil
+}
+
+// adoptInventory creates the inventory of a namespace being adopted, seeded from its running workloads with Detect,
+// or returns its existing inventory. It tells whether the inventory has been created.
+func (api *api) adoptInventory(namespace string, opts AdoptOptions) (playbook.Inventory, bool, error) {
+	inv, err := api.inventories.Create(namespace)
+	switch err.(type) {
+	case nil:
+	case playbook.ErrorInventoryAlreadyExist:
+		logrus.Warn(err.Error())
+		inv, err = api.inventories.Get(namespace)
+		return inv, false, err
+	default:
+		return playbook.Inventory{}, false, err
+	}
+
+	if opts.Detect {
+		inv, err = api.seedInventory(inv)
+	}
+
+	return inv, true, err
+}
+
+// seedInventory replaces the values of the inventory with the ones detected from the running workloads and saves it
+func (api *api) seedInventory(inv playbook.Inventory) (playbook.Inventory, error) {
+	status, err := api.namespaces.GetStatus(inv.Namespace)
+	if err != nil {
+		return inv, err
+	}
+
+	var workloads []playbook.DetectedWorkload
+	for _, w := range status.Workloads {
+		if w.Kind != resource.KindDeployment && w.Kind != resource.KindStatefulset {
+			continue
+		}
+
+		detected := playbook.DetectedWorkload{Name: w.Name, Replicas: w.Desired}
+		for _, c := range w.Containers {
+			detected.Containers = append(detected.Containers, playbook.DetectedContainer{Name: c.Name, Image: c.Image})
+		}
+		workloads = append(workloads, detected)
+	}
+
+	inv.Values = playbook.SeedValues(inv.Values, workloads)
+
+	if err := api.inventories.Update(inv.Namespace, inv); err != nil {
+		return inv, err
+	}
+
+	return inv, nil
+}
@@ -0,0 +1,76 @@
+package api_test
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	"k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/DanielPickens/Keeper/pkg/api"
+	"github.com/DanielPickens/Keeper/pkg/playbook"
+)
+
+func TestAdopt(t *testing.T) {
+	replicas := int32(3)
+	deployment := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "api", Namespace: "legacy"},
+		Spec: appsv1.DeploymentSpec{
+			Replicas: &replicas,
+			Template: v1.PodTemplateSpec{
+				Spec: v1.PodSpec{Containers: []v1.Container{{Name: "api", Image: "registry:5000/api:1.4.2"}}},
+			},
+		},
+	}
+
+	a, f, client := newTestApi(t, &v1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "legacy"}}, deployment)
+
+	defaults := `{"values": {"api": {"replicas": 1, "image": "registry:5000/api", "tag": "latest"}, "front": {"replicas": 1}}}`
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(filepath.Dir(f.ConfigPath()), "defaults.json"), []byte(defaults), 0644))
+
+	inv, err := a.Adopt("legacy", api.AdoptOptions{Detect: true})
+	assert.NoError(t, err)
+
+	expected := map[string]interface{}{
+		"api":   map[string]interface{}{"replicas": float64(3), "image": "registry:5000/api", "tag": "1.4.2"},
+		"front": map[string]interface{}{"replicas": float64(1)},
+	}
+	assert.Equal(t, expected, inv.Values)
+
+	saved, err := f.Inventories().Get("legacy")
+	assert.NoError(t, err)
+	assert.Equal(t, expected, saved.Values)
+
+	ns, err := client.CoreV1().Namespaces().Get(context.Background(), "legacy", metav1.GetOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, "keeper", ns.Labels["manager"])
+
+	namespaces, err := a.ListNamespaces()
+	assert.NoError(t, err)
+	assert.Len(t, namespaces, 1)
+	assert.True(t, namespaces[0].Managed)
+
+	// adopting again keeps the existing inventory
+	assert.NoError(t, f.Inventories().Update("legacy", saved))
+	_, err = a.Adopt("legacy", api.AdoptOptions{})
+	assert.NoError(t, err)
+	saved, err = f.Inventories().Get("legacy")
+	assert.NoError(t, err)
+	assert.Equal(t, expected, saved.Values)
+
+	// nothing is left behind when the namespace cannot be adopted
+	for _, opts := range []api.AdoptOptions{{}, {Detect: true}} {
+		_, err = a.Adopt("missing", opts)
+		assert.Error(t, err)
+
+		_, err = f.Inventories().Get("missing")
+		assert.IsType(t, playbook.ErrorInventoryNotFound{}, err)
+		_, err = os.Stat(filepath.Join(f.ConfigPath(), "missing"))
+		assert.True(t, os.IsNotExist(err))
+	}
+}
@@ -1,80 +1,210 @@
 package api
 
 import (
-	"crypto/x509"
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
 	"strings"
 	"time"
 
-	"github.com/sirpusen/logrus"
+	"github.com/sirupsen/logrus"
 
 	"github.com/DanielPickens/Keeper/pkg/playbook"
 	"github.com/DanielPickens/Keeper/pkg/resource"
 	"github.com/DanielPickens/Keeper/pkg/version"
 )
 
-//api interface is inferred keeper entrypoint by defining the list of actions keeper is able to perform
+//Api interface is inferred keeper entrypoint by defining the list of actions keeper is able to perform
 
-type api interface {
-	Namespaces() resource.NamespaceService
+type Api interface {
 	Inventories() playbook.InventoryService
-	Playbooks() resource.PlaybookService
-	Pod() resource.PodService
+	Namespaces() resource.NamespaceService
+	Playbooks() playbook.PlaybookService
+	Pods() resource.PodService
+	CronJobs() resource.CronJobService
+	Events() resource.EventService
+	Usages() resource.UsageService
 	Create(namespace string) (playbook.Inventory, error)
-	Delete(namespace string, wait bool) error 
-	
+	Adopt(namespace string, opts AdoptOptions) (playbook.Inventory, error)
+	Delete(ctx context.Context, namespace string, opts DeleteOptions) error
+	ListExposedServices(namespace string) ([]resource.Service, error)
+	ListServiceURLs(namespace string) (resource.ServiceURLs, error)
+	ListNamespaces() ([]Namespace, error)
+	GetStatus(namespace string) (*resource.NamespaceStatus, error)
+	Logs(ctx context.Context, namespace string, opts LogsOptions, lines chan<- resource.LogLine) error
+	PortForward(ctx context.Context, namespace string, opts PortForwardOptions, ready func([]resource.ForwardedPort)) error
+	Reset(namespace string, configPath string) error
+	Apply(namespace string, configPath string, opts ApplyOptions) ([]resource.ApplyResult, error)
+	Diff(namespace string, opts ApplyOptions) ([]resource.ApplyResult, error)
+	History(namespace string) ([]playbook.ReleaseRecord, error)
+	Rollback(namespace string, configPath string, revision int, opts ApplyOptions) (playbook.ReleaseRecord, []resource.ApplyResult, error)
+	Update(namespace string, inventory playbook.Inventory, configPath string) error
+	WaitForNamespaceReady(ctx context.Context, namespace string, opts WaitOptions, bar progress) (*WaitResult, error)
+	GetVersion() (*Version, error)
+	DeleteResource(namespace string, resource string) error
+	RerunJob(ctx context.Context, namespace, configPath, name string, opts RerunOptions) (*resource.Job, error)
+	TriggerCronJob(ctx context.Context, namespace, name string, opts RerunOptions) (*resource.Job, error)
+	WatchNamespaceDeleted()
+	RunSmokeTests(ctx context.Context, namespace string, opts TestOptions) (*TestReport, error)
+	FindOrphans(opts OrphanOptions) ([]Orphan, error)
+	ResolveOrphan(orphan Orphan, action GCAction, configPath string) error
+	SupportBundle(ctx context.Context, namespace string, opts SupportBundleOptions, w io.Writer) error
+	EstimateCost(namespaces []string, prices PriceTable) (*CostReport, error)
 }
 
 type api struct {
 	inventories playbook.InventoryService
-	configs playbook.ConfigService
-	playbooks playbook.PlaybookService
-	namespace resource.NamespaceService
-	pods resource.PodService
-	services resource.ServiceService
-	cluster resource.ClusterService
-	job resource.JobService
-}
-
-type version struct {
-	Keeper string `json:keeper`
-	Kubernetes string `json:kubernetes`
-	Kubectl string `json:kubectl`
-}
-
-//NewAPI creates the keeper api. the keeper api is resposibile for the managing of active playbooks and parameters are structs : Inventory, Config, Namespace,Pod, Service respectively
-
-func NewAPi (
-	inventories playbook.InventoryRepository
-	configs playbook.ConfigsRepository
-	playbooks playbook.PlaybookRespository
-	namespaces resource.NameSpaceRepository
-	pods resource.PodsRepository
-	deployments resource.DeploymentsRepository
-	services resource.ServiceRepository
-	job resource.JobsRepository
-) Api {
+	configs     playbook.ConfigService
+	history     playbook.HistoryService
+	playbooks   playbook.PlaybookService
+	namespaces  resource.NamespaceService
+	pods        resource.PodService
+	services    resource.ServiceService
+	cluster     resource.ClusterService
+	job         resource.JobService
+	cronjobs    resource.CronJobService
+	events      resource.EventService
+	usages      resource.UsageService
+	smoketests  playbook.SmokeTestService
+	bundles     resource.BundleService
+	pruneKinds  []resource.PruneKind
+	// playbookVersion is the version of the playbook recorded in the release history
+	playbookVersion string
+}
+
+// ApplyOptions defines how the configs of a namespace are applied.
+// With Prune, the objects previously applied to the namespace that are no longer part of its configs are deleted,
+// provided their kind is allowed by the prune settings of the playbook.
+// Actor is who applies the configs, recorded in the release history of the namespace.
+type ApplyOptions struct {
+	Prune bool
+	Actor string
+}
+
+// Version represents the versions of keeper, of the kubernetes client keeper is built with and of the kubernetes
+// api server, and the api group versions served by the api server
+type Version struct {
+	Keeper     string   `json:"keeper"`
+	Kubernetes string   `json:"kubernetes"`
+	Client     string   `json:"client"`
+	APIGroups  []string `json:"apiGroups"`
+}
+
+//NewApi creates the keeper api. the keeper api is resposibile for the managing of active playbooks and parameters are structs : Inventory, Config, Namespace,Pod, Service respectively
+//The readiness settings of the playbook define which kinds of resources, including custom resources, are taken into account in namespaces status.
+//An error is returned when the settings of the playbook cannot be read or are invalid.
+
+func NewApi(
+	inventories playbook.InventoryRepository,
+	configs playbook.ConfigRepository,
+	history playbook.HistoryRepository,
+	playbooks playbook.PlaybookRepository,
+	namespaces resource.NamespaceRepository,
+	pods resource.PodRepository,
+	deployments resource.DeploymentRepository,
+	statefulsets resource.StatefulsetRepository,
+	services resource.ServiceRepository,
+	cluster resource.ClusterRepository,
+	job resource.JobRepository,
+	daemonsets resource.DaemonsetRepository,
+	pvcs resource.PersistentVolumeClaimRepository,
+	ingresses resource.IngressRepository,
+	cronjobs resource.CronJobRepository,
+	customs resource.CustomResourceRepository,
+	bundles resource.BundleRepository,
+	events resource.EventRepository,
+	usages resource.UsageRepository,
+) (Api, error) {
+	settings, err := playbook.NewPlaybookService(playbooks).GetSettings()
+	if err != nil {
+		return nil, err
+	}
+
 	api := &api{
-		inventories: playbook.NewInventoryService(inventories,playbook.NewPlaybookService(playbooks)),
-		playbooks: playbook.NewPlayBookService(playbooks),
-		configs: playbook.NewConfigService(configs, playbook.NewPlaybookService(playbooks)),
-		namespaces: resource.NewNamespaceServices(namespaces, pods, deployments), 
-		pods: resource.NewPodServicw(pods),
-		services: resource.NewServiceService(services)
-		cluster: resource.NewClusterService(cluster),
-		job: resource.NewJobService(job),
-	} 
-	return api
-	
-}
-
-
-//func Inventories will return the Inventory Servicve from the api
-func(api *api) Inventories() playbook.InventoryService {
+		inventories: playbook.NewInventoryService(inventories, playbook.NewPlaybookService(playbooks)),
+		playbooks:   playbook.NewPlaybookService(playbooks),
+		configs:     playbook.NewConfigService(configs, playbook.NewPlaybookService(playbooks)),
+		history:     playbook.NewHistoryService(history),
+		namespaces: resource.NewNamespaceService(
+			namespaces,
+			pods,
+			deployments,
+			statefulsets,
+			job,
+			daemonsets,
+			pvcs,
+			ingresses,
+			cronjobs,
+			customs,
+			newReadiness(settings.Readiness),
+		),
+		pods:       resource.NewPodService(pods),
+		services:   resource.NewServiceService(services),
+		cluster:    resource.NewClusterService(cluster),
+		job:        resource.NewJobService(job),
+		cronjobs:   resource.NewCronJobService(cronjobs),
+		events:     resource.NewEventService(events),
+		usages:     resource.NewUsageService(usages),
+		smoketests: playbook.NewSmokeTestService(playbook.NewPlaybookService(playbooks)),
+		bundles:    resource.NewBundleService(bundles),
+		pruneKinds: newPruneKinds(settings.Prune),
+
+		playbookVersion: settings.Version,
+	}
+	return api, nil
+
+}
+
+// newReadiness converts the readiness settings of a playbook into readiness options of the namespace service
+func newReadiness(settings playbook.ReadinessSettings) resource.Readiness {
+	readiness := resource.Readiness{Ignore: settings.Ignore}
+
+	for _, r := range settings.Rules {
+		rule := resource.ReadinessRule{
+			Group:     r.Group,
+			Version:   r.Version,
+			Kind:      r.Kind,
+			JSONPath:  r.JSONPath,
+			Operator:  r.Operator,
+			Value:     r.Value,
+			ValuePath: r.ValuePath,
+		}
+
+		if r.Condition != nil {
+			rule.ConditionType = r.Condition.Type
+			rule.ConditionStatus = r.Condition.Status
+		}
+
+		readiness.Rules = append(readiness.Rules, rule)
+	}
+
+	return readiness
+}
+
+// newPruneKinds converts the prune settings of a playbook into the kinds allowed to be pruned
+func newPruneKinds(settings playbook.PruneSettings) []resource.PruneKind {
+	if len(settings.Kinds) == 0 {
+		return resource.DefaultPruneKinds
+	}
+
+	kinds := make([]resource.PruneKind, 0, len(settings.Kinds))
+	for _, k := range settings.Kinds {
+		kinds = append(kinds, resource.PruneKind{Group: k.Group, Version: k.Version, Kind: k.Kind})
+	}
+
+	return kinds
+}
+
+// func Inventories will return the Inventory Servicve from the api
+func (api *api) Inventories() playbook.InventoryService {
 	return api.inventories
 }
 
-//func Namespaces returns the Namespace Service from the api
-func(api *api) Namespaces() resource.NamespaceService {
+// func Namespaces returns the Namespace Service from the api
+func (api *api) Namespaces() resource.NamespaceService {
 	return api.namespaces
 }
 
@@ -85,22 +215,37 @@ func (api *api) Playbooks() playbook.PlaybookService {
 }
 
 func (api *api) Pods() resource.PodService {
-	return api.Pods
+	return api.pods
+}
+
+// CronJobs returns the CronJob service from the api
+func (api *api) CronJobs() resource.CronJobService {
+	return api.cronjobs
+}
+
+// Events returns the Event service from the api
+func (api *api) Events() resource.EventService {
+	return api.events
+}
+
+// Usages returns the Usage service from the api
+func (api *api) Usages() resource.UsageService {
+	return api.usages
 }
 
 //func Create creates a inventory, configs, and kubernetes namespace for the given namespace
 
 func (api *api) Create(namespace string) (playbook.Inventory, error) {
 	if err := api.namespaces.Create(namespace); err != nil {
-		return playbook.Inventory(), err
+		return playbook.Inventory{}, err
 	}
 
 	inv, err := api.inventories.Create(namespace)
 	if err != nil {
 		switch x := err.(type) {
-		default: 
+		default:
 			return playbook.Inventory{}, x
-		case *playbook.ErrorInventoryAlreadyExists:
+		case playbook.ErrorInventoryAlreadyExist:
 			logrus.Warn(x.Error())
 			logrus.Info("Process continue")
 		}
@@ -112,57 +257,244 @@ func (api *api) Create(namespace string) (playbook.Inventory, error) {
 	return inv, nil
 }
 
+// Delete deletes the kubernetes namespace, then the inventory and the configs of the given namespace.
+// Without opts.Wait, the inventory and the configs are deleted as soon as the namespace deletion is requested.
+// With opts.Wait, they are deleted once the namespace is actually gone. If the namespace is still terminating after
+// opts.Timeout, an ErrorNamespaceStuck describing what blocks it is returned, unless opts.ForceFinalizers is set :
+// the blocking finalizers are then cleared and the namespace is waited for again. When ctx is done first, its error
+// is returned.
+// When opts.ConfigPath is set, the pre-delete hooks of the configs are run first : the namespace is not deleted
+// if one of them failed.
+func (api *api) Delete(ctx context.Context, namespace string, opts DeleteOptions) error {
+	if opts.ConfigPath != "" {
+		if _, err := api.namespaces.RunHooks(namespace, opts.ConfigPath, resource.HookPreDelete); err != nil {
+			return err
+		}
+	}
 
-//func Delete deletes all inventory, configs, and kubernetes namespace for a given namespace
-
-func (api *api) Delete(namespace string, wait bool) error {
-	//delete logic
 	if err := api.namespaces.Delete(namespace); err != nil {
 		return err
 	}
-	if !wait {
+
+	if !opts.Wait {
 		api.deletePlaybook(namespace)
+		return nil
+	}
+
+	err := api.waitNamespaceDeleted(ctx, namespace, opts.Timeout)
+	if err != nil && ctx.Err() != nil {
+		// the caller gave up, the namespace is not known to be stuck
+		return ctx.Err()
+	}
+	if err == context.DeadlineExceeded {
+		blockers, berr := api.namespaces.GetBlockers(namespace)
+		if berr != nil {
+			return berr
+		}
+
+		if !opts.ForceFinalizers {
+			return ErrorNamespaceStuck{Namespace: namespace, Blockers: blockers}
+		}
+
+		logrus.WithFields(logrus.Fields{
+			"namespace": namespace,
+		}).Warnf("namespace is still terminating after %s, clearing finalizers of %d resources", opts.Timeout, len(blockers.Resources))
+
+		if err := api.namespaces.ClearFinalizers(blockers); err != nil {
+			return err
+		}
+
+		err = api.waitNamespaceDeleted(ctx, namespace, opts.Timeout)
+		if err != nil && ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if err == context.DeadlineExceeded {
+			if blockers, berr = api.namespaces.GetBlockers(namespace); berr != nil {
+				return berr
+			}
+			return ErrorNamespaceStuck{Namespace: namespace, Blockers: blockers}
+		}
+	}
+	if err != nil {
+		return err
 	}
+
+	api.deletePlaybook(namespace)
+
 	return nil
 }
 
+// waitNamespaceDeleted waits until the namespace is deleted, for timeout at most if it is positive.
+// context.DeadlineExceeded is returned when the timeout, or the deadline of ctx, is reached.
+func (api *api) waitNamespaceDeleted(ctx context.Context, namespace string, timeout time.Duration) error {
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	return api.namespaces.WaitDeleted(ctx, namespace)
+}
+
 //func deletePlaybook deletes a playbook from a kubenetes namespace
 
-func deletePlaybook(namespace string) {
-	if inv := api.inventories.Get(namespace); inv.Namespace == namespace {
+func (api *api) deletePlaybook(namespace string) {
+	if inv, _ := api.inventories.Get(namespace); inv.Namespace == namespace {
 		api.inventories.Delete(namespace)
 		api.configs.Delete(namespace)
+		api.history.Delete(namespace)
+	}
+}
+
+// WatchNamespaceDeleted deletes the inventory and the configs of each managed namespace once it is deleted
+func (api *api) WatchNamespaceDeleted() {
+	events := make(chan resource.NamespaceEvent)
+
+	go api.namespaces.Watch(events)
+
+	for e := range events {
+		if e.Type == "DELETED" {
+			api.deletePlaybook(e.Namespace)
+		}
 	}
 }
 
 func (api *api) GetVersion() (*Version, error) {
-	w, err := api.clusterGetVersion()
+	w, err := api.cluster.GetVersion()
 
 	if err != nil {
 		return nil, err
 	}
 
 	return &Version{
-		Keeper: version.GetVersion(),
-		Kubectl: strings.Join([]string{w.ClientVersion.Major, w.ClientVersion.Minor}, "."),
+		Keeper:     version.GetVersion(),
+		Client:     strings.Join([]string{w.ClientVersion.Major, w.ClientVersion.Minor}, "."),
 		Kubernetes: strings.Join([]string{w.ServerVersion.Major, w.ServerVersion.Minor}, "."),
-	}
+		APIGroups:  w.APIGroups,
+	}, nil
+}
+
+// ListExposedServices returns the services of the namespace reachable from outside of the cluster
+func (api *api) ListExposedServices(namespace string) ([]resource.Service, error) {
+	return api.services.ListExposed(namespace)
 }
-//deletes a resource from a kubernetes namespace
+
+// ListServiceURLs returns the URLs the services of the namespace can be reached at, from outside or inside the cluster
+func (api *api) ListServiceURLs(namespace string) (resource.ServiceURLs, error) {
+	return api.services.ListURLs(namespace)
+}
+
+// deletes a resource from a kubernetes namespace
 func (api *api) DeleteResource(namespace, resource string) error {
-	if err := api.Resource.Delete(namespace, resource); err != nil {
+	if err := api.job.Delete(context.Background(), namespace, resource); err != nil {
 		return err
 	}
 	return nil
 }
 
+// Apply generates the kubernetes configs of the next release of the namespace from its inventory and applies them
+// to the namespace. The release, its inventory, its configs and the outcome of the apply are recorded in the
+// history of the namespace. It returns the result of the apply of each object of the configs, and of each pruned object.
+func (api *api) Apply(namespace string, configPath string, opts ApplyOptions) ([]resource.ApplyResult, error) {
+	inv, err := api.inventories.Get(namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	revision, err := api.history.NextRevision(namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	configs, err := api.configs.Release(inv, revision)
+	if err != nil {
+		return nil, err
+	}
+
+	results, err := api.namespaces.ApplyConfig(namespace, configPath, api.applyOptions(opts))
+
+	api.recordRelease(playbook.ReleaseRecord{
+		Namespace: namespace,
+		Revision:  revision,
+		Actor:     opts.Actor,
+		Inventory: inv,
+		Configs:   configs,
+	}, err)
+
+	return results, err
+}
+
+// Diff generates the kubernetes configs of the namespace from its inventory and returns what applying them would change.
+// Each result holds the unified diff between the live object and the object as it would be applied, or pruned.
+// The configs are rendered in a temporary directory : the configs of the namespace are left as they are.
+func (api *api) Diff(namespace string, opts ApplyOptions) ([]resource.ApplyResult, error) {
+	inv, err := api.inventories.Get(namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	revision, err := api.history.NextRevision(namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	configs, err := api.configs.Render(inv, revision)
+	if err != nil {
+		return nil, err
+	}
+
+	dir, err := ioutil.TempDir("", "keeper-diff")
+	if err != nil {
+		return nil, fmt.Errorf("unable to create diff directory: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	if err := writeConfigs(filepath.Join(dir, namespace), configs); err != nil {
+		return nil, err
+	}
+
+	return api.namespaces.DiffConfig(namespace, dir, api.applyOptions(opts))
+}
+
+// writeConfigs writes configs in the given directory
+func writeConfigs(dir string, configs []playbook.Config) error {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("unable to create configs directory: %v", err)
+	}
+
+	for _, cfg := range configs {
+		if err := ioutil.WriteFile(filepath.Join(dir, cfg.Name), []byte(cfg.Values), 0644); err != nil {
+			return fmt.Errorf("unable to write config %s: %v", cfg.Name, err)
+		}
+	}
+
+	return nil
+}
+
+// applyOptions returns the options of the namespace service matching the apply options
+func (api *api) applyOptions(opts ApplyOptions) resource.ApplyOptions {
+	return resource.ApplyOptions{
+		Prune:      opts.Prune,
+		PruneKinds: api.pruneKinds,
+	}
+}
+
+// Reset resets the inventory of the namespace to the playbook defaults and applies it
+func (api *api) Reset(namespace string, configPath string) error {
+	if _, err := api.inventories.Reset(namespace); err != nil {
+		return err
+	}
+
+	_, err := api.Apply(namespace, configPath, ApplyOptions{Prune: true})
+	return err
+}
+
 func (api *api) Update(namespace string, inventory playbook.Inventory, configPath string) error {
 	if err := api.inventories.Update(namespace, inventory); err != nil {
 		return err
 	}
-	if err := api.Apply(namespace, configPath); err != nil {
+	if _, err := api.Apply(namespace, configPath, ApplyOptions{Prune: true}); err != nil {
 		return err
 	}
-	return nil 
+	return nil
 }
-	
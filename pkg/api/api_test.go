@@ -0,0 +1,120 @@
+package api_test
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/api/meta/testrestmapper"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/kubernetes/scheme"
+	k8stesting "k8s.io/client-go/testing"
+
+	"github.com/DanielPickens/Keeper/pkg/api"
+	"github.com/DanielPickens/Keeper/pkg/files"
+	"github.com/DanielPickens/Keeper/pkg/kubernetes"
+)
+
+// newTestApi creates an api using a playbook in a temporary directory and a fake kubernetes cluster
+func newTestApi(t *testing.T, objects ...runtime.Object) (api.Api, *files.Client, *fake.Clientset) {
+	f := newTestPlaybook(t)
+	a, client := newTestClusterApi(t, f, objects...)
+
+	return a, f, client
+}
+
+func TestNewApiInvalidSettings(t *testing.T) {
+	f := newTestPlaybook(t)
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(filepath.Dir(f.ConfigPath()), "settings.json"), []byte(`{"readiness": `), 0644))
+
+	_, err := newClusterApi(f, fake.NewSimpleClientset(), dynamicfake.NewSimpleDynamicClient(runtime.NewScheme()))
+	assert.Error(t, err)
+}
+
+// newTestPlaybook creates a playbook in a temporary directory
+func newTestPlaybook(t *testing.T) *files.Client {
+	dir, err := ioutil.TempDir("", "keeper")
+	assert.NoError(t, err)
+	t.Cleanup(func() { os.RemoveAll(dir) })
+
+	assert.NoError(t, os.Mkdir(filepath.Join(dir, "templates"), 0755))
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(dir, "templates", "config.yml.tpl"), []byte("namespace: {{ .Namespace }}"), 0644))
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(dir, "defaults.json"), []byte(`{"values": {"replicas": 1}}`), 0644))
+
+	f, err := files.NewClient(dir)
+	assert.NoError(t, err)
+
+	return f
+}
+
+// newTestClusterApi creates an api using the given playbook and a fake kubernetes cluster
+func newTestClusterApi(t *testing.T, f *files.Client, objects ...runtime.Object) (api.Api, *fake.Clientset) {
+	client := fake.NewSimpleClientset(objects...)
+	dynamic := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), map[schema.GroupVersionResource]string{
+		{Group: "gateway.networking.k8s.io", Version: "v1", Resource: "httproutes"}: "HTTPRouteList",
+	})
+
+	// the fake object tracker is unable to apply unstructured objects : applied objects replace the existing ones
+	dynamic.PrependReactor("patch", "*", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		patch := action.(k8stesting.PatchAction)
+		if patch.GetPatchType() != types.ApplyPatchType {
+			return false, nil, nil
+		}
+
+		obj := &unstructured.Unstructured{}
+		if err := json.Unmarshal(patch.GetPatch(), &obj.Object); err != nil {
+			return true, nil, err
+		}
+
+		tracker := dynamic.Tracker()
+		if _, err := tracker.Get(patch.GetResource(), patch.GetNamespace(), patch.GetName()); err != nil {
+			return true, obj, tracker.Create(patch.GetResource(), obj, patch.GetNamespace())
+		}
+		return true, obj, tracker.Update(patch.GetResource(), obj, patch.GetNamespace())
+	})
+
+	a, err := newClusterApi(f, client, dynamic)
+	assert.NoError(t, err)
+
+	return a, client
+}
+
+// newClusterApi creates an api using the given playbook and kubernetes clients
+func newClusterApi(f *files.Client, client *fake.Clientset, dynamic *dynamicfake.FakeDynamicClient) (api.Api, error) {
+	return api.NewApi(
+		f.Inventories(),
+		f.Configs(),
+		f.History(),
+		f.Playbooks(),
+		kubernetes.NewNamespaceRepository(client, dynamic, testrestmapper.TestOnlyStaticRESTMapper(scheme.Scheme)),
+		kubernetes.NewPodRepository(client),
+		kubernetes.NewDeploymentRepository(client),
+		kubernetes.NewStatefulsetRepository(client),
+		kubernetes.NewServiceRepository(client, dynamic, "localhost", nil),
+		kubernetes.NewClusterRepository(client),
+		kubernetes.NewJobRepository(client),
+		kubernetes.NewDaemonsetRepository(client),
+		kubernetes.NewPersistentVolumeClaimRepository(client),
+		kubernetes.NewIngressRepository(client),
+		kubernetes.NewCronJobRepository(client),
+		kubernetes.NewCustomResourceRepository(dynamic, meta.NewDefaultRESTMapper(nil)),
+		kubernetes.NewBundleRepository(client),
+		kubernetes.NewEventRepository(client),
+		kubernetes.NewUsageRepository(client, dynamic),
+	)
+}
+
+func newManagedNamespace(name string) *v1.Namespace {
+	return &v1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: name, Labels: map[string]string{"manager": "keeper"}}}
+}
@@ -0,0 +1,156 @@
+package api
+
+import (
+	"archive/zip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/DanielPickens/Keeper/pkg/resource"
+)
+
+// SupportBundleOptions defines what is collected in a support bundle.
+// TailLines is the number of the last log lines collected for each container.
+type SupportBundleOptions struct {
+	TailLines int64
+}
+
+// SupportBundle writes a zip archive gathering what is needed to troubleshoot a namespace : the keeper and the
+// kubernetes versions, the inventory and the rendered configs of the namespace, its events, the YAML of its workloads
+// and of the objects they use, the description of its pods and the last log lines of their containers.
+// The values of the secrets, and the inventory values and the environment variables looking like credentials, are redacted.
+// What could not be collected is listed in the errors.txt file of the archive.
+func (api *api) SupportBundle(ctx context.Context, namespace string, opts SupportBundleOptions, w io.Writer) error {
+	var files []resource.BundleFile
+	var failures []string
+
+	inv, invErr := api.inventories.Get(namespace)
+	if invErr == nil {
+		inv.Values = redactValues(inv.Values)
+		files = append(files, jsonBundleFile("inventory.json", inv))
+	} else {
+		failures = append(failures, invErr.Error())
+	}
+
+	configs, err := api.configs.Get(namespace)
+	if err != nil {
+		failures = append(failures, err.Error())
+	}
+	for _, cfg := range configs {
+		content, err := api.bundles.RedactConfig([]byte(cfg.Values))
+		if err != nil {
+			// the config is left out as its secrets may not be redacted
+			failures = append(failures, fmt.Sprintf("config %s left out: %v", cfg.Name, err))
+			continue
+		}
+		files = append(files, resource.BundleFile{Path: path.Join("configs", cfg.Name), Content: content})
+	}
+
+	if version, err := api.GetVersion(); err != nil {
+		failures = append(failures, fmt.Sprintf("unable to get the cluster version: %v", err))
+	} else {
+		files = append(files, jsonBundleFile("version.json", version))
+	}
+
+	collected, err := api.bundles.Collect(ctx, namespace, resource.BundleOptions{TailLines: opts.TailLines})
+	if err != nil {
+		if invErr != nil {
+			// neither the inventory nor the namespace exist
+			return err
+		}
+		failures = append(failures, err.Error())
+	}
+	for _, f := range collected {
+		files = append(files, resource.BundleFile{Path: path.Join("kubernetes", f.Path), Content: f.Content})
+	}
+
+	if len(failures) > 0 {
+		files = append(files, resource.BundleFile{Path: "errors.txt", Content: []byte(strings.Join(failures, "\n") + "\n")})
+	}
+
+	return writeZip(w, namespace, files)
+}
+
+// writeZip writes the files in a zip archive, in a directory named after the namespace
+func writeZip(w io.Writer, namespace string, files []resource.BundleFile) error {
+	archive := zip.NewWriter(w)
+	now := time.Now()
+
+	for _, f := range files {
+		fw, err := archive.CreateHeader(&zip.FileHeader{
+			Name:     path.Join(namespace, f.Path),
+			Method:   zip.Deflate,
+			Modified: now,
+		})
+		if err != nil {
+			return fmt.Errorf("unable to write %s in the support bundle: %v", f.Path, err)
+		}
+
+		if _, err := fw.Write(f.Content); err != nil {
+			return fmt.Errorf("unable to write %s in the support bundle: %v", f.Path, err)
+		}
+	}
+
+	if err := archive.Close(); err != nil {
+		return fmt.Errorf("unable to write the support bundle: %v", err)
+	}
+
+	return nil
+}
+
+// jsonBundleFile returns a file holding the indented JSON of a value
+func jsonBundleFile(name string, v interface{}) resource.BundleFile {
+	content, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		content = []byte(err.Error())
+	}
+
+	return resource.BundleFile{Path: name, Content: content}
+}
+
+// redactValues returns a copy of inventory values where the values of the keys looking like credentials are redacted.
+// The value of a name/value pair, such as an environment variable, is redacted when its name looks like a credential.
+func redactValues(values map[string]interface{}) map[string]interface{} {
+	redacted := make(map[string]interface{}, len(values))
+
+	for k, v := range values {
+		switch v.(type) {
+		case map[string]interface{}, []interface{}:
+			redacted[k] = redactValue(v)
+		default:
+			if resource.SensitiveKey.MatchString(k) {
+				redacted[k] = resource.Redacted
+			} else {
+				redacted[k] = v
+			}
+		}
+	}
+
+	if name, ok := values["name"].(string); ok && resource.SensitiveKey.MatchString(name) {
+		if _, ok := values["value"]; ok {
+			redacted["value"] = resource.Redacted
+		}
+	}
+
+	return redacted
+}
+
+// redactValue returns a copy of an inventory value where the credentials of the maps it contains are redacted
+func redactValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		return redactValues(v)
+	case []interface{}:
+		redacted := make([]interface{}, len(v))
+		for i, item := range v {
+			redacted[i] = redactValue(item)
+		}
+		return redacted
+	}
+
+	return value
+}
@@ -0,0 +1,98 @@
+package api_test
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"io/ioutil"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	"k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/DanielPickens/Keeper/pkg/api"
+	"github.com/DanielPickens/Keeper/pkg/playbook"
+)
+
+const deploymentConfig = `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: api
+spec:
+  template:
+    spec:
+      initContainers:
+      - name: migrate
+        env:
+        - name: DB_PASSWORD
+          value: s3cr3t
+      containers:
+      - name: api
+        env:
+        - name: DB_PASSWORD
+          value: s3cr3t
+        - name: LOG_LEVEL
+          value: debug
+`
+
+func TestSupportBundle(t *testing.T) {
+	env := []v1.EnvVar{{Name: "DB_PASSWORD", Value: "s3cr3t"}, {Name: "LOG_LEVEL", Value: "debug"}}
+	spec := v1.PodSpec{
+		InitContainers: []v1.Container{{Name: "migrate", Env: env}},
+		Containers:     []v1.Container{{Name: "api", Env: env}},
+	}
+	deployment := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "api", Namespace: "test"},
+		Spec:       appsv1.DeploymentSpec{Template: v1.PodTemplateSpec{Spec: spec}},
+	}
+	pod := &v1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "api-x2k", Namespace: "test"}, Spec: spec}
+
+	a, f, _ := newTestApi(t, newManagedNamespace("test"), deployment, pod)
+
+	assert.NoError(t, f.Inventories().Create(playbook.Inventory{
+		Namespace: "test",
+		Values: map[string]interface{}{
+			"db": map[string]interface{}{"host": "db", "password": "s3cr3t"},
+			"env": []interface{}{
+				map[string]interface{}{"name": "DB_PASSWORD", "value": "s3cr3t"},
+				map[string]interface{}{"name": "LOG_LEVEL", "value": "debug"},
+			},
+		},
+	}))
+	assert.NoError(t, f.Configs().Save("test", []playbook.Config{
+		{Name: "secret.yml", Values: "kind: Secret\nstringData:\n  token: s3cr3t\n"},
+		{Name: "deployment.yml", Values: deploymentConfig},
+	}))
+
+	var buf bytes.Buffer
+	assert.NoError(t, a.SupportBundle(context.Background(), "test", api.SupportBundleOptions{TailLines: 10}, &buf))
+
+	archive, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	assert.NoError(t, err)
+
+	contents := make(map[string]string)
+	for _, file := range archive.File {
+		r, err := file.Open()
+		assert.NoError(t, err)
+		content, err := ioutil.ReadAll(r)
+		assert.NoError(t, err)
+		contents[file.Name] = string(content)
+	}
+
+	assert.Contains(t, contents["test/inventory.json"], `"host": "db"`)
+	assert.Contains(t, contents["test/inventory.json"], `"value": "debug"`)
+	assert.Contains(t, contents["test/configs/secret.yml"], "token: REDACTED")
+	assert.Contains(t, contents["test/configs/deployment.yml"], "value: REDACTED")
+	assert.Contains(t, contents["test/configs/deployment.yml"], "value: debug")
+	assert.Contains(t, contents["test/kubernetes/objects/deployment/api.yaml"], "value: REDACTED")
+	assert.Contains(t, contents["test/kubernetes/pods/api-x2k/pod.yaml"], "value: REDACTED")
+	assert.Contains(t, contents, "test/version.json")
+	assert.Contains(t, contents, "test/kubernetes/namespace.yaml")
+	for name, content := range contents {
+		assert.NotContains(t, content, "s3cr3t", name)
+	}
+
+	assert.Error(t, a.SupportBundle(context.Background(), "missing", api.SupportBundleOptions{}, &buf))
+}
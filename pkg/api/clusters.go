@@ -0,0 +1,336 @@
+package api
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/DanielPickens/Keeper/pkg/playbook"
+)
+
+// Clusters gives access to the api of each cluster, identified by a context of the kubeconfig file.
+// The apis are created the first time their context is used. The empty context is the current context.
+type Clusters struct {
+	current string
+	newApi  func(context string) (Api, error)
+	mu      sync.Mutex
+	apis    map[string]Api
+}
+
+// NewClusters creates the clusters of a kubeconfig file whose current context is the given one.
+// newApi creates the api of the cluster of a context.
+func NewClusters(current string, newApi func(context string) (Api, error)) *Clusters {
+	return &Clusters{
+		current: current,
+		newApi:  newApi,
+		apis:    make(map[string]Api),
+	}
+}
+
+// Get returns the api of the cluster of the given context
+func (c *Clusters) Get(context string) (Api, error) {
+	context = c.resolve(context)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if api, ok := c.apis[context]; ok {
+		return api, nil
+	}
+
+	api, err := c.newApi(context)
+	if err != nil {
+		return nil, err
+	}
+
+	c.apis[context] = api
+
+	return api, nil
+}
+
+// ForNamespace returns the api of the cluster targeted by the inventory of the namespace.
+// The api of the current context is returned when the namespace has no inventory or its inventory has no context.
+func (c *Clusters) ForNamespace(namespace string) (Api, error) {
+	api, err := c.Get("")
+	if err != nil {
+		return nil, err
+	}
+
+	inv, err := api.Inventories().Get(namespace)
+	if err != nil {
+		if _, ok := err.(playbook.ErrorInventoryNotFound); ok {
+			return api, nil
+		}
+		return nil, err
+	}
+
+	if c.resolve(inv.Context) == c.current {
+		return api, nil
+	}
+
+	return c.Get(inv.Context)
+}
+
+// Create creates a namespace, its inventory and its configs on the cluster of the given context.
+// The context is recorded in the inventory so that the namespace is then managed on this cluster.
+func (c *Clusters) Create(namespace string, context string) (playbook.Inventory, error) {
+	api, err := c.Get(context)
+	if err != nil {
+		return playbook.Inventory{}, err
+	}
+
+	inv, err := api.Create(namespace)
+	if err != nil {
+		return playbook.Inventory{}, err
+	}
+
+	return c.recordContext(api, inv, context)
+}
+
+// Adopt adopts an existing namespace of the cluster of the given context.
+// The context is recorded in the inventory so that the namespace is then managed on this cluster.
+func (c *Clusters) Adopt(namespace string, context string, opts AdoptOptions) (playbook.Inventory, error) {
+	api, err := c.Get(context)
+	if err != nil {
+		return playbook.Inventory{}, err
+	}
+
+	inv, err := api.Adopt(namespace, opts)
+	if err != nil {
+		return playbook.Inventory{}, err
+	}
+
+	return c.recordContext(api, inv, context)
+}
+
+// Contexts returns the contexts of the clusters managed by keeper : the current context and the contexts targeted
+// by the inventories.
+func (c *Clusters) Contexts() ([]string, error) {
+	api, err := c.Get("")
+	if err != nil {
+		return nil, err
+	}
+
+	inventories, err := api.Inventories().List()
+	if err != nil {
+		return nil, err
+	}
+
+	found := map[string]bool{c.current: true}
+	contexts := []string{c.current}
+
+	for _, inv := range inventories {
+		context := c.resolve(inv.Context)
+		if !found[context] {
+			found[context] = true
+			contexts = append(contexts, context)
+		}
+	}
+
+	sort.Strings(contexts[1:])
+
+	return contexts, nil
+}
+
+// ListNamespaces returns the namespaces of the clusters of the given contexts.
+// A namespace is managed when its inventory targets its cluster.
+// The clusters that cannot be reached are reported and skipped : an error is returned only if none can be reached.
+func (c *Clusters) ListNamespaces(contexts []string) ([]Namespace, error) {
+	var namespaces []Namespace
+	var lastErr error
+	reached := false
+
+	for _, context := range contexts {
+		context = c.resolve(context)
+
+		nsList, err := c.listNamespaces(context)
+		if err != nil {
+			logrus.WithFields(logrus.Fields{
+				"context": context,
+			}).Warnf("unable to list the namespaces of the cluster : %v", err)
+			lastErr = err
+			continue
+		}
+
+		namespaces = append(namespaces, nsList...)
+		reached = true
+	}
+
+	if !reached && lastErr != nil {
+		return nil, lastErr
+	}
+
+	return namespaces, nil
+}
+
+// listNamespaces returns the namespaces of the cluster of the given context
+func (c *Clusters) listNamespaces(context string) ([]Namespace, error) {
+	api, err := c.Get(context)
+	if err != nil {
+		return nil, err
+	}
+
+	namespaces, err := api.ListNamespaces()
+	if err != nil {
+		return nil, err
+	}
+
+	for i, ns := range namespaces {
+		namespaces[i].Cluster = context
+		if ns.Managed {
+			inv, err := api.Inventories().Get(ns.Name)
+			namespaces[i].Managed = err == nil && c.resolve(inv.Context) == context
+		}
+	}
+
+	return namespaces, nil
+}
+
+// FindOrphans looks for the orphans of each cluster managed by keeper : the namespaces of a cluster are only compared
+// with the inventories targeting it. The configs without inventory are looked for once, with the current context.
+// The clusters that cannot be reached, other than the current one, are reported and skipped.
+func (c *Clusters) FindOrphans() ([]Orphan, error) {
+	contexts, err := c.Contexts()
+	if err != nil {
+		return nil, err
+	}
+
+	orphans := make([]Orphan, 0)
+
+	for _, context := range contexts {
+		found, err := c.findOrphans(context)
+		if err != nil {
+			if context == c.current {
+				return nil, err
+			}
+			logrus.WithFields(logrus.Fields{
+				"context": context,
+			}).Warnf("unable to find the orphans of the cluster : %v", err)
+			continue
+		}
+
+		orphans = append(orphans, found...)
+	}
+
+	return orphans, nil
+}
+
+// findOrphans looks for the orphans of the cluster of the given context
+func (c *Clusters) findOrphans(context string) ([]Orphan, error) {
+	api, err := c.Get(context)
+	if err != nil {
+		return nil, err
+	}
+
+	orphans, err := api.FindOrphans(OrphanOptions{
+		Targets: func(inv playbook.Inventory) bool {
+			return c.resolve(inv.Context) == context
+		},
+		SkipConfigs: context != c.current,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if context != c.current {
+		for i := range orphans {
+			orphans[i].Context = context
+		}
+	}
+
+	return orphans, nil
+}
+
+// ResolveOrphan resolves an orphan with the given action on the cluster of its context.
+// An adopted namespace is then managed on this cluster.
+func (c *Clusters) ResolveOrphan(orphan Orphan, action GCAction, configPath string) error {
+	if orphan.Kind == OrphanNamespace && action == GCAdopt {
+		_, err := c.Adopt(orphan.Namespace, orphan.Context, AdoptOptions{})
+		return err
+	}
+
+	api, err := c.Get(orphan.Context)
+	if err != nil {
+		return err
+	}
+
+	return api.ResolveOrphan(orphan, action, configPath)
+}
+
+// resolve returns the name of the given context, the current one if it is empty
+func (c *Clusters) resolve(context string) string {
+	if context == "" {
+		return c.current
+	}
+	return context
+}
+
+// recordContext saves the context in the inventory, unless it is the current one
+func (c *Clusters) recordContext(api Api, inv playbook.Inventory, context string) (playbook.Inventory, error) {
+	if c.resolve(context) == c.current || inv.Context == context {
+		return inv, nil
+	}
+
+	inv.Context = context
+	if err := api.Inventories().Update(inv.Namespace, inv); err != nil {
+		return playbook.Inventory{}, err
+	}
+
+	return inv, nil
+}
+
+// EstimateCost estimates the monthly cost of the namespaces of the inventories of the playbook, each one on the
+// cluster its inventory targets, aggregated by owner. The clusters that cannot be reached are reported and their
+// namespaces skipped.
+func (c *Clusters) EstimateCost(prices PriceTable) (*CostReport, error) {
+	api, err := c.Get("")
+	if err != nil {
+		return nil, err
+	}
+
+	inventories, err := api.Inventories().List()
+	if err != nil {
+		return nil, err
+	}
+
+	var contexts []string
+	namespaces := make(map[string][]string)
+
+	for _, inv := range inventories {
+		context := c.resolve(inv.Context)
+		if _, ok := namespaces[context]; !ok {
+			contexts = append(contexts, context)
+		}
+		namespaces[context] = append(namespaces[context], inv.Namespace)
+	}
+
+	costs := make([]NamespaceCost, 0, len(inventories))
+	var skipped []string
+
+	for _, context := range contexts {
+		report, err := c.estimateCost(context, namespaces[context], prices)
+		if err != nil {
+			logrus.WithFields(logrus.Fields{
+				"context": context,
+			}).Warnf("unable to estimate the cost of the namespaces of the cluster : %v", err)
+			skipped = append(skipped, namespaces[context]...)
+			continue
+		}
+
+		costs = append(costs, report.Namespaces...)
+		skipped = append(skipped, report.Skipped...)
+	}
+
+	return newCostReport(prices.Currency, costs, skipped), nil
+}
+
+// estimateCost estimates the monthly cost of namespaces of the cluster of the given context
+func (c *Clusters) estimateCost(context string, namespaces []string, prices PriceTable) (*CostReport, error) {
+	api, err := c.Get(context)
+	if err != nil {
+		return nil, err
+	}
+
+	return api.EstimateCost(namespaces, prices)
+}
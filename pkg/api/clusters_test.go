@@ -0,0 +1,174 @@
+package api_test
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	kerr "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	k8stesting "k8s.io/client-go/testing"
+
+	"github.com/DanielPickens/Keeper/pkg/api"
+	"github.com/DanielPickens/Keeper/pkg/playbook"
+)
+
+func TestClusters(t *testing.T) {
+	f := newTestPlaybook(t)
+
+	staging, _ := newTestClusterApi(t, f, newManagedNamespace("api"), newManagedNamespace("legacy"))
+	perf, _ := newTestClusterApi(t, f, newManagedNamespace("api"), newManagedNamespace("load"))
+
+	created := map[string]int{}
+	clusters := api.NewClusters("staging", func(context string) (api.Api, error) {
+		created[context]++
+		switch context {
+		case "staging":
+			return staging, nil
+		case "perf":
+			return perf, nil
+		}
+		return nil, assert.AnError
+	})
+
+	assert.NoError(t, f.Inventories().Create(playbook.Inventory{Namespace: "api"}))
+	assert.NoError(t, f.Inventories().Create(playbook.Inventory{Namespace: "load", Context: "perf"}))
+	assert.NoError(t, f.Inventories().Create(playbook.Inventory{Namespace: "broken", Context: "unreachable"}))
+
+	a, err := clusters.ForNamespace("load")
+	assert.NoError(t, err)
+	assert.Equal(t, perf, a)
+
+	a, err = clusters.ForNamespace("api")
+	assert.NoError(t, err)
+	assert.Equal(t, staging, a)
+
+	a, err = clusters.ForNamespace("unknown")
+	assert.NoError(t, err)
+	assert.Equal(t, staging, a)
+
+	// an unreadable inventory is not a missing one : its namespace may target another cluster
+	corrupted := filepath.Join(f.InventoryPath(), "corrupted.json")
+	assert.NoError(t, ioutil.WriteFile(corrupted, []byte("{"), 0644))
+	_, err = clusters.ForNamespace("corrupted")
+	assert.Error(t, err)
+	assert.NoError(t, os.Remove(corrupted))
+
+	contexts, err := clusters.Contexts()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"staging", "perf", "unreachable"}, contexts)
+
+	namespaces, err := clusters.ListNamespaces(contexts)
+	assert.NoError(t, err)
+
+	managed := map[string]bool{}
+	for _, ns := range namespaces {
+		managed[ns.Cluster+"/"+ns.Name] = ns.Managed
+	}
+	assert.Equal(t, map[string]bool{
+		"staging/api":    true,
+		"staging/legacy": false,
+		"perf/api":       false,
+		"perf/load":      true,
+	}, managed)
+
+	// apis are created once per context
+	assert.Equal(t, 1, created["staging"])
+	assert.Equal(t, 1, created["perf"])
+
+	_, err = clusters.ListNamespaces([]string{"unreachable"})
+	assert.Error(t, err)
+
+	inv, err := clusters.Create("batch", "perf")
+	assert.NoError(t, err)
+	assert.Equal(t, "perf", inv.Context)
+
+	inv, err = f.Inventories().Get("batch")
+	assert.NoError(t, err)
+	assert.Equal(t, "perf", inv.Context)
+
+	inv, err = clusters.Create("front", "")
+	assert.NoError(t, err)
+	assert.Empty(t, inv.Context)
+}
+
+func TestClustersListDeletedNamespace(t *testing.T) {
+	f := newTestPlaybook(t)
+
+	staging, _ := newTestClusterApi(t, f, newManagedNamespace("api"))
+	perf, client := newTestClusterApi(t, f, newManagedNamespace("load"), newManagedNamespace("gone"))
+
+	// the namespace is deleted between the list of the namespaces and the get of its status
+	client.PrependReactor("get", "namespaces", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		if action.(k8stesting.GetAction).GetName() != "gone" {
+			return false, nil, nil
+		}
+		return true, nil, kerr.NewNotFound(schema.GroupResource{Resource: "namespaces"}, "gone")
+	})
+
+	clusters := api.NewClusters("staging", func(context string) (api.Api, error) {
+		if context == "perf" {
+			return perf, nil
+		}
+		return staging, nil
+	})
+
+	namespaces, err := clusters.ListNamespaces([]string{"staging", "perf"})
+	assert.NoError(t, err)
+
+	var listed []string
+	for _, ns := range namespaces {
+		listed = append(listed, ns.Cluster+"/"+ns.Name)
+		if ns.Name == "gone" {
+			assert.Equal(t, 0, ns.Status)
+		}
+	}
+	assert.ElementsMatch(t, []string{"staging/api", "perf/load", "perf/gone"}, listed)
+}
+
+func TestClustersFindAndResolveOrphans(t *testing.T) {
+	f := newTestPlaybook(t)
+
+	staging, stagingClient := newTestClusterApi(t, f, newManagedNamespace("api"), newManagedNamespace("legacy"))
+	perf, perfClient := newTestClusterApi(t, f, newManagedNamespace("load"), newManagedNamespace("api"))
+
+	clusters := api.NewClusters("staging", func(context string) (api.Api, error) {
+		switch context {
+		case "staging":
+			return staging, nil
+		case "perf":
+			return perf, nil
+		}
+		return nil, assert.AnError
+	})
+
+	assert.NoError(t, f.Inventories().Create(playbook.Inventory{Namespace: "api"}))
+	assert.NoError(t, f.Inventories().Create(playbook.Inventory{Namespace: "load", Context: "perf"}))
+	assert.NoError(t, f.Inventories().Create(playbook.Inventory{Namespace: "batch", Context: "perf"}))
+	assert.NoError(t, f.Inventories().Create(playbook.Inventory{Namespace: "broken", Context: "unreachable"}))
+	assert.NoError(t, f.Configs().Save("stale", []playbook.Config{{Name: "config.yml", Values: "namespace: stale"}}))
+
+	orphans, err := clusters.FindOrphans()
+
+	// the namespaces and inventories of each cluster are only compared with each other
+	assert.NoError(t, err)
+	assert.Equal(t, []api.Orphan{
+		{Kind: api.OrphanConfigs, Namespace: "stale"},
+		{Kind: api.OrphanNamespace, Namespace: "legacy"},
+		{Kind: api.OrphanInventory, Namespace: "batch", Context: "perf"},
+		{Kind: api.OrphanNamespace, Namespace: "api", Context: "perf"},
+	}, orphans)
+
+	// the orphan is resolved on its cluster
+	assert.NoError(t, clusters.ResolveOrphan(orphans[3], api.GCDelete, f.ConfigPath()))
+
+	_, err = perfClient.CoreV1().Namespaces().Get(context.Background(), "api", metav1.GetOptions{})
+	assert.Error(t, err)
+	_, err = stagingClient.CoreV1().Namespaces().Get(context.Background(), "api", metav1.GetOptions{})
+	assert.NoError(t, err)
+}
@@ -0,0 +1,137 @@
+package api
+
+import (
+	"math"
+	"sort"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/DanielPickens/Keeper/pkg/resource"
+)
+
+const (
+	// defaultOwnerLabel is the label of the namespaces telling which team owns them, when none is set in the prices
+	defaultOwnerLabel = "owner"
+
+	// unassignedOwner is the owner of the namespaces without owner label
+	unassignedOwner = "unassigned"
+
+	gibibyte = 1024 * 1024 * 1024
+)
+
+// PriceTable defines the monthly prices of the resources requested in namespaces, in Currency.
+// CPU is the price of a CPU core, and Memory and Storage the price of a GiB. OwnerLabel is the label of the
+// namespaces telling which team owns them, owner when empty.
+type PriceTable struct {
+	Currency   string
+	CPU        float64
+	Memory     float64
+	Storage    float64
+	OwnerLabel string
+}
+
+// NamespaceCost is the estimated monthly cost of a namespace, from the CPU cores, the GiB of memory and the GiB of
+// storage it requests
+type NamespaceCost struct {
+	Namespace string  `json:"namespace"`
+	Owner     string  `json:"owner"`
+	CPU       float64 `json:"cpu"`
+	Memory    float64 `json:"memory"`
+	Storage   float64 `json:"storage"`
+	Cost      float64 `json:"cost"`
+}
+
+// OwnerCost is the estimated monthly cost of the namespaces of an owner
+type OwnerCost struct {
+	Owner      string   `json:"owner"`
+	Namespaces []string `json:"namespaces"`
+	Cost       float64  `json:"cost"`
+}
+
+// CostReport is the estimated monthly cost of namespaces, detailed by namespace and by owner from the most
+// expensive to the cheapest. Skipped are the namespaces whose cost could not be estimated.
+type CostReport struct {
+	Currency   string          `json:"currency"`
+	Namespaces []NamespaceCost `json:"namespaces"`
+	Owners     []OwnerCost     `json:"owners"`
+	Total      float64         `json:"total"`
+	Skipped    []string        `json:"skipped,omitempty"`
+}
+
+// EstimateCost estimates the monthly cost of the given namespaces from the resources they request and a price table,
+// aggregated by owner. The namespaces whose requested resources cannot be retrieved are reported and skipped.
+func (api *api) EstimateCost(namespaces []string, prices PriceTable) (*CostReport, error) {
+	ownerLabel := prices.OwnerLabel
+	if ownerLabel == "" {
+		ownerLabel = defaultOwnerLabel
+	}
+
+	costs := make([]NamespaceCost, 0, len(namespaces))
+	var skipped []string
+
+	for _, namespace := range namespaces {
+		requests, err := api.usages.Requests(namespace)
+		if err != nil {
+			logrus.WithField("namespace", namespace).Warnf("unable to estimate the cost of the namespace : %v", err)
+			skipped = append(skipped, namespace)
+			continue
+		}
+
+		cost := namespaceCost(requests, prices)
+		cost.Owner = requests.Labels[ownerLabel]
+		if cost.Owner == "" {
+			cost.Owner = unassignedOwner
+		}
+
+		costs = append(costs, cost)
+	}
+
+	return newCostReport(prices.Currency, costs, skipped), nil
+}
+
+// newCostReport returns the report of the cost of namespaces, aggregated by owner
+func newCostReport(currency string, costs []NamespaceCost, skipped []string) *CostReport {
+	report := &CostReport{Currency: currency, Namespaces: costs, Owners: make([]OwnerCost, 0), Skipped: skipped}
+	owners := make(map[string]int)
+
+	for _, cost := range costs {
+		report.Total += cost.Cost
+
+		i, ok := owners[cost.Owner]
+		if !ok {
+			i = len(report.Owners)
+			owners[cost.Owner] = i
+			report.Owners = append(report.Owners, OwnerCost{Owner: cost.Owner})
+		}
+		report.Owners[i].Namespaces = append(report.Owners[i].Namespaces, cost.Namespace)
+		report.Owners[i].Cost += cost.Cost
+	}
+
+	sort.SliceStable(report.Namespaces, func(i, j int) bool { return report.Namespaces[i].Cost > report.Namespaces[j].Cost })
+	sort.SliceStable(report.Owners, func(i, j int) bool { return report.Owners[i].Cost > report.Owners[j].Cost })
+
+	for i := range report.Owners {
+		report.Owners[i].Cost = roundCost(report.Owners[i].Cost)
+	}
+	report.Total = roundCost(report.Total)
+
+	return report
+}
+
+// namespaceCost returns the monthly cost of the resources requested in a namespace
+func namespaceCost(requests resource.ResourceRequests, prices PriceTable) NamespaceCost {
+	cost := NamespaceCost{
+		Namespace: requests.Namespace,
+		CPU:       float64(requests.CPU) / 1000,
+		Memory:    float64(requests.Memory) / gibibyte,
+		Storage:   float64(requests.Storage) / gibibyte,
+	}
+	cost.Cost = roundCost(cost.CPU*prices.CPU + cost.Memory*prices.Memory + cost.Storage*prices.Storage)
+
+	return cost
+}
+
+// roundCost rounds a cost to the cent
+func roundCost(cost float64) float64 {
+	return math.Round(cost*100) / 100
+}
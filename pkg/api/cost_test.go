@@ -0,0 +1,95 @@
+package api_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/api/core/v1"
+	apiresource "k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/DanielPickens/Keeper/pkg/api"
+	"github.com/DanielPickens/Keeper/pkg/playbook"
+)
+
+func newOwnedNamespace(name, team string) *v1.Namespace {
+	ns := newManagedNamespace(name)
+	if team != "" {
+		ns.Labels["team"] = team
+	}
+	return ns
+}
+
+func newRequestingPod(namespace, cpu, memory string) *v1.Pod {
+	return &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: namespace},
+		Spec: v1.PodSpec{Containers: []v1.Container{{
+			Name: "app",
+			Resources: v1.ResourceRequirements{Requests: v1.ResourceList{
+				v1.ResourceCPU:    apiresource.MustParse(cpu),
+				v1.ResourceMemory: apiresource.MustParse(memory),
+			}},
+		}}},
+		Status: v1.PodStatus{Phase: v1.PodRunning},
+	}
+}
+
+func TestEstimateCost(t *testing.T) {
+	f := newTestPlaybook(t)
+
+	staging, _ := newTestClusterApi(t, f,
+		newOwnedNamespace("pr-1", "payments"), newRequestingPod("pr-1", "2", "4Gi"),
+		newOwnedNamespace("pr-2", "search"), newRequestingPod("pr-2", "500m", "1Gi"),
+	)
+	perf, _ := newTestClusterApi(t, f,
+		newOwnedNamespace("load", "payments"), newRequestingPod("load", "4", "8Gi"),
+		newOwnedNamespace("sandbox", ""), newRequestingPod("sandbox", "100m", "128Mi"),
+	)
+
+	clusters := api.NewClusters("staging", func(context string) (api.Api, error) {
+		switch context {
+		case "staging":
+			return staging, nil
+		case "perf":
+			return perf, nil
+		}
+		return nil, assert.AnError
+	})
+
+	for _, inv := range []playbook.Inventory{
+		{Namespace: "pr-1"},
+		{Namespace: "pr-2"},
+		{Namespace: "gone"},
+		{Namespace: "load", Context: "perf"},
+		{Namespace: "sandbox", Context: "perf"},
+		{Namespace: "broken", Context: "unreachable"},
+	} {
+		assert.NoError(t, f.Inventories().Create(inv))
+	}
+
+	prices := api.PriceTable{Currency: "EUR", CPU: 20, Memory: 2.5, OwnerLabel: "team"}
+
+	report, err := clusters.EstimateCost(prices)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "EUR", report.Currency)
+	assert.Equal(t, []api.NamespaceCost{
+		{Namespace: "load", Owner: "payments", CPU: 4, Memory: 8, Cost: 100},
+		{Namespace: "pr-1", Owner: "payments", CPU: 2, Memory: 4, Cost: 50},
+		{Namespace: "pr-2", Owner: "search", CPU: 0.5, Memory: 1, Cost: 12.5},
+		{Namespace: "sandbox", Owner: "unassigned", CPU: 0.1, Memory: 0.125, Cost: 2.31},
+	}, report.Namespaces)
+	assert.Equal(t, []api.OwnerCost{
+		{Owner: "payments", Namespaces: []string{"pr-1", "load"}, Cost: 150},
+		{Owner: "search", Namespaces: []string{"pr-2"}, Cost: 12.5},
+		{Owner: "unassigned", Namespaces: []string{"sandbox"}, Cost: 2.31},
+	}, report.Owners)
+	assert.Equal(t, 164.81, report.Total)
+	assert.ElementsMatch(t, []string{"gone", "broken"}, report.Skipped)
+
+	report, err = staging.EstimateCost([]string{"pr-2"}, prices)
+
+	assert.NoError(t, err)
+	assert.Len(t, report.Namespaces, 1)
+	assert.Equal(t, 12.5, report.Total)
+}
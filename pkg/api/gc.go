@@ -0,0 +1,176 @@
+package api
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/DanielPickens/Keeper/pkg/playbook"
+)
+
+// OrphanKind represents the kind of mismatch between the managed namespaces and the playbook files
+type OrphanKind string
+
+// Kinds of orphans
+const (
+	// OrphanNamespace is a managed namespace without inventory
+	OrphanNamespace OrphanKind = "NamespaceWithoutInventory"
+	// OrphanInventory is an inventory without namespace
+	OrphanInventory OrphanKind = "InventoryWithoutNamespace"
+	// OrphanConfigs are rendered configs without inventory
+	OrphanConfigs OrphanKind = "ConfigsWithoutInventory"
+)
+
+// GCAction represents the way an orphan is resolved
+type GCAction string
+
+// Actions resolving orphans
+const (
+	// GCAdopt creates the missing inventory of a namespace from the playbook defaults, without applying it
+	GCAdopt GCAction = "adopt"
+	// GCRecreate creates the missing namespace of an inventory and applies the inventory
+	GCRecreate GCAction = "recreate"
+	// GCDelete deletes the orphan namespace, inventory or configs
+	GCDelete GCAction = "delete"
+	// GCSkip leaves the orphan as is
+	GCSkip GCAction = "skip"
+)
+
+// orphanActions are the actions able to resolve each kind of orphan
+var orphanActions = map[OrphanKind][]GCAction{
+	OrphanNamespace: {GCAdopt, GCDelete, GCSkip},
+	OrphanInventory: {GCRecreate, GCDelete, GCSkip},
+	OrphanConfigs:   {GCDelete, GCSkip},
+}
+
+// Orphan represents a managed namespace, an inventory or rendered configs not matching the others.
+// Context is the context of the cluster of the namespace or targeted by the inventory, empty for the current one.
+type Orphan struct {
+	Kind      OrphanKind `json:"kind"`
+	Namespace string     `json:"namespace"`
+	Context   string     `json:"context,omitempty"`
+}
+
+// OrphanOptions defines which orphans are looked for.
+// Targets tells whether an inventory targets the cluster of the api : only these inventories are compared with the
+// namespaces of the cluster. Every inventory targets it when Targets is nil.
+// With SkipConfigs, the configs without inventory are not looked for.
+type OrphanOptions struct {
+	Targets     func(inv playbook.Inventory) bool
+	SkipConfigs bool
+}
+
+// Actions returns the actions able to resolve the orphan
+func (o Orphan) Actions() []GCAction {
+	return orphanActions[o.Kind]
+}
+
+// ErrorInvalidGCAction represents an error due to an action unable to resolve an orphan
+type ErrorInvalidGCAction struct {
+	Orphan Orphan
+	Action GCAction
+}
+
+// Error returns the error message
+func (err ErrorInvalidGCAction) Error() string {
+	return fmt.Sprintf("%s cannot be resolved with %q, expected one of %v", err.Orphan.Kind, err.Action, err.Orphan.Actions())
+}
+
+// FindOrphans cross-references the managed namespaces with the inventories and the rendered configs of the playbook.
+// It returns the namespaces without inventory, the inventories without namespace and the configs without inventory.
+func (api *api) FindOrphans(opts OrphanOptions) ([]Orphan, error) {
+	// only the names of the namespaces are needed, their status is not computed
+	namespaces, err := api.namespaces.ListWithoutStatus()
+	if err != nil {
+		return nil, err
+	}
+
+	inventories, err := api.inventories.List()
+	if err != nil {
+		return nil, err
+	}
+
+	configs, err := api.configs.List()
+	if err != nil {
+		return nil, err
+	}
+
+	hasNamespace := make(map[string]bool)
+	for _, ns := range namespaces {
+		hasNamespace[ns.Name] = true
+	}
+
+	hasInventory := make(map[string]bool)
+	isTargeted := make(map[string]bool)
+	for _, inv := range inventories {
+		hasInventory[inv.Namespace] = true
+		isTargeted[inv.Namespace] = opts.Targets == nil || opts.Targets(inv)
+	}
+
+	orphans := make([]Orphan, 0)
+
+	for _, ns := range namespaces {
+		if !isTargeted[ns.Name] {
+			orphans = append(orphans, Orphan{Kind: OrphanNamespace, Namespace: ns.Name})
+		}
+	}
+
+	for _, inv := range inventories {
+		if isTargeted[inv.Namespace] && !hasNamespace[inv.Namespace] {
+			orphans = append(orphans, Orphan{Kind: OrphanInventory, Namespace: inv.Namespace})
+		}
+	}
+
+	if !opts.SkipConfigs {
+		for _, namespace := range configs {
+			if !hasInventory[namespace] {
+				orphans = append(orphans, Orphan{Kind: OrphanConfigs, Namespace: namespace})
+			}
+		}
+	}
+
+	sort.SliceStable(orphans, func(i, j int) bool {
+		if orphans[i].Kind != orphans[j].Kind {
+			return orphans[i].Kind < orphans[j].Kind
+		}
+		return orphans[i].Namespace < orphans[j].Namespace
+	})
+
+	return orphans, nil
+}
+
+// ResolveOrphan resolves an orphan with the given action. An ErrorInvalidGCAction is returned if the action
+// cannot resolve this kind of orphan.
+func (api *api) ResolveOrphan(orphan Orphan, action GCAction, configPath string) error {
+	valid := false
+	for _, a := range orphan.Actions() {
+		if a == action {
+			valid = true
+		}
+	}
+	if !valid {
+		return ErrorInvalidGCAction{Orphan: orphan, Action: action}
+	}
+
+	switch {
+	case action == GCSkip:
+		return nil
+	case orphan.Kind == OrphanNamespace && action == GCAdopt:
+		_, err := api.Adopt(orphan.Namespace, AdoptOptions{})
+		return err
+	case orphan.Kind == OrphanNamespace && action == GCDelete:
+		return api.namespaces.Delete(orphan.Namespace)
+	case orphan.Kind == OrphanInventory && action == GCRecreate:
+		if err := api.namespaces.Create(orphan.Namespace); err != nil {
+			return err
+		}
+		_, err := api.Apply(orphan.Namespace, configPath, ApplyOptions{Prune: true})
+		return err
+	case orphan.Kind == OrphanInventory && action == GCDelete:
+		api.deletePlaybook(orphan.Namespace)
+		return nil
+	case orphan.Kind == OrphanConfigs && action == GCDelete:
+		return api.configs.Delete(orphan.Namespace)
+	}
+
+	return nil
+}
@@ -0,0 +1,48 @@
+package api_test
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/DanielPickens/Keeper/pkg/api"
+	"github.com/DanielPickens/Keeper/pkg/playbook"
+)
+
+func TestFindAndResolveOrphans(t *testing.T) {
+	a, f, _ := newTestApi(t, newManagedNamespace("managed"), newManagedNamespace("legacy"))
+
+	assert.NoError(t, f.Inventories().Create(playbook.Inventory{Namespace: "managed"}))
+	assert.NoError(t, f.Inventories().Create(playbook.Inventory{Namespace: "gone"}))
+	assert.NoError(t, f.Configs().Save("managed", nil))
+	assert.NoError(t, f.Configs().Save("stale", []playbook.Config{{Name: "config.yml", Values: "namespace: stale"}}))
+
+	orphans, err := a.FindOrphans(api.OrphanOptions{})
+
+	assert.NoError(t, err)
+	assert.Equal(t, []api.Orphan{
+		{Kind: api.OrphanConfigs, Namespace: "stale"},
+		{Kind: api.OrphanInventory, Namespace: "gone"},
+		{Kind: api.OrphanNamespace, Namespace: "legacy"},
+	}, orphans)
+
+	assert.IsType(t, api.ErrorInvalidGCAction{}, a.ResolveOrphan(orphans[0], api.GCAdopt, f.ConfigPath()))
+
+	assert.NoError(t, a.ResolveOrphan(orphans[0], api.GCDelete, f.ConfigPath()))
+	assert.NoError(t, a.ResolveOrphan(orphans[1], api.GCDelete, f.ConfigPath()))
+	assert.NoError(t, a.ResolveOrphan(orphans[2], api.GCAdopt, f.ConfigPath()))
+
+	inv, err := f.Inventories().Get("legacy")
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"replicas": float64(1)}, inv.Values)
+
+	config, err := ioutil.ReadFile(filepath.Join(f.ConfigPath(), "legacy", "config.yml"))
+	assert.NoError(t, err)
+	assert.Equal(t, "namespace: legacy", string(config))
+
+	orphans, err = a.FindOrphans(api.OrphanOptions{})
+	assert.NoError(t, err)
+	assert.Empty(t, orphans)
+}
@@ -0,0 +1,78 @@
+package api
+
+import (
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/DanielPickens/Keeper/pkg/playbook"
+	"github.com/DanielPickens/Keeper/pkg/resource"
+)
+
+// History returns the releases of the namespace, ordered by revision
+func (api *api) History(namespace string) ([]playbook.ReleaseRecord, error) {
+	return api.history.List(namespace)
+}
+
+// Rollback applies again the inventory and the configs of a previous release of the namespace, as they were released.
+// With a revision of 0, the namespace is rolled back to the last deployed release before the current one.
+// The rollback is recorded as a new release of the namespace, which is returned with the result of the apply of
+// each object of the configs.
+func (api *api) Rollback(namespace string, configPath string, revision int, opts ApplyOptions) (playbook.ReleaseRecord, []resource.ApplyResult, error) {
+	next, err := api.history.NextRevision(namespace)
+	if err != nil {
+		return playbook.ReleaseRecord{}, nil, err
+	}
+
+	var target playbook.ReleaseRecord
+	if revision == 0 {
+		target, err = api.history.LastDeployed(namespace, next-1)
+	} else {
+		target, err = api.history.Get(namespace, revision)
+	}
+	if err != nil {
+		return playbook.ReleaseRecord{}, nil, err
+	}
+
+	if err := api.inventories.Update(namespace, target.Inventory); err != nil {
+		return playbook.ReleaseRecord{}, nil, err
+	}
+
+	if err := api.configs.Restore(namespace, target.Configs); err != nil {
+		return playbook.ReleaseRecord{}, nil, err
+	}
+
+	results, err := api.namespaces.ApplyConfig(namespace, configPath, api.applyOptions(opts))
+
+	record := api.recordRelease(playbook.ReleaseRecord{
+		Namespace:  namespace,
+		Revision:   next,
+		Actor:      opts.Actor,
+		RollbackOf: target.Revision,
+		Inventory:  target.Inventory,
+		Configs:    target.Configs,
+	}, err)
+
+	return record, results, err
+}
+
+// recordRelease records a release and the outcome of its apply in the history of its namespace.
+// As the configs have already been applied, failing to record the release is only logged.
+func (api *api) recordRelease(record playbook.ReleaseRecord, applyErr error) playbook.ReleaseRecord {
+	record.Date = time.Now()
+	record.PlaybookVersion = api.playbookVersion
+	record.Status = playbook.ReleaseDeployed
+	if applyErr != nil {
+		record.Status = playbook.ReleaseFailed
+		record.Error = applyErr.Error()
+	}
+
+	if err := api.history.Save(record); err != nil {
+		logrus.WithFields(logrus.Fields{
+			"namespace": record.Namespace,
+			"revision":  record.Revision,
+		}).Warnf("unable to record the release: %v", err)
+	}
+
+	return record
+}
@@ -0,0 +1,100 @@
+package api_test
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/DanielPickens/Keeper/pkg/api"
+	"github.com/DanielPickens/Keeper/pkg/playbook"
+)
+
+const releaseTemplate = `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: settings
+  annotations:
+    keeper.io/revision: "{{ .Release.Revision }}"
+data:
+  level: {{ .Values.level }}
+`
+
+func TestApplyHistoryAndRollback(t *testing.T) {
+	a, f, _ := newTestApi(t, newManagedNamespace("test"))
+
+	templates := filepath.Join(filepath.Dir(f.ConfigPath()), "templates")
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(templates, "config.yml.tpl"), []byte(releaseTemplate), 0644))
+
+	for _, level := range []string{"debug", "info"} {
+		assert.NoError(t, f.Inventories().Update("test", playbook.Inventory{Values: map[string]interface{}{"level": level}}))
+		_, err := a.Apply("test", f.ConfigPath(), api.ApplyOptions{Actor: "jane"})
+		assert.NoError(t, err)
+	}
+
+	records, err := a.History("test")
+	assert.NoError(t, err)
+	assert.Len(t, records, 2)
+	assert.Equal(t, 2, records[1].Revision)
+	assert.Equal(t, playbook.ReleaseDeployed, records[1].Status)
+	assert.Equal(t, "jane", records[1].Actor)
+	assert.Equal(t, "info", records[1].Inventory.Values["level"])
+	assert.Contains(t, records[1].Configs[0].Values, `keeper.io/revision: "2"`)
+
+	record, _, err := a.Rollback("test", f.ConfigPath(), 0, api.ApplyOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, 3, record.Revision)
+	assert.Equal(t, 1, record.RollbackOf)
+
+	inv, err := f.Inventories().Get("test")
+	assert.NoError(t, err)
+	assert.Equal(t, "debug", inv.Values["level"])
+
+	configs, err := ioutil.ReadFile(filepath.Join(f.ConfigPath(), "test", "config.yml"))
+	assert.NoError(t, err)
+	assert.Contains(t, string(configs), `keeper.io/revision: "1"`)
+
+	_, _, err = a.Rollback("test", f.ConfigPath(), 7, api.ApplyOptions{})
+	assert.IsType(t, playbook.ErrorReleaseNotFound{}, err)
+}
+
+func TestDiffKeepsConfigs(t *testing.T) {
+	a, f, _ := newTestApi(t, newManagedNamespace("test"))
+
+	templates := filepath.Join(filepath.Dir(f.ConfigPath()), "templates")
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(templates, "config.yml.tpl"), []byte(releaseTemplate), 0644))
+
+	assert.NoError(t, f.Inventories().Update("test", playbook.Inventory{Values: map[string]interface{}{"level": "debug"}}))
+	_, err := a.Apply("test", f.ConfigPath(), api.ApplyOptions{})
+	assert.NoError(t, err)
+
+	assert.NoError(t, f.Inventories().Update("test", playbook.Inventory{Values: map[string]interface{}{"level": "info"}}))
+	results, err := a.Diff("test", api.ApplyOptions{})
+	assert.NoError(t, err)
+	assert.Len(t, results, 1)
+	assert.Contains(t, results[0].Diff, "+  level: info")
+
+	configs, err := ioutil.ReadFile(filepath.Join(f.ConfigPath(), "test", "config.yml"))
+	assert.NoError(t, err)
+	assert.Contains(t, string(configs), "level: debug")
+}
+
+func TestApplyTemplateError(t *testing.T) {
+	a, f, _ := newTestApi(t, newManagedNamespace("test"))
+
+	templates := filepath.Join(filepath.Dir(f.ConfigPath()), "templates")
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(templates, "config.yml.tpl"), []byte(`level: {{ .Values.log.level }}`), 0644))
+
+	assert.NoError(t, f.Inventories().Update("test", playbook.Inventory{Values: map[string]interface{}{"log": "debug"}}))
+
+	_, err := a.Diff("test", api.ApplyOptions{})
+	assert.Error(t, err)
+
+	_, err = a.Apply("test", f.ConfigPath(), api.ApplyOptions{})
+	assert.Error(t, err)
+
+	records, err := a.History("test")
+	assert.NoError(t, err)
+	assert.Empty(t, records)
+}
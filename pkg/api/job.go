@@ -0,0 +1,80 @@
+package api
+
+import (
+	"context"
+	"io"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/DanielPickens/Keeper/pkg/resource"
+)
+
+// RerunOptions defines how a job is run again.
+// When Wait is true, the job is waited for until it is completed or failed, for Timeout at most when it is not zero,
+// its logs being streamed to Output when it is set.
+type RerunOptions struct {
+	Wait    bool
+	Timeout time.Duration
+	Output  io.Writer
+}
+
+// RerunJob deletes a job of the namespace and creates it again from the rendered configs of the namespace, such as a
+// seed or a migration job. It returns the created job, whose status is only known once it has been waited for.
+// A failed job is not an error : its status is JobFailed. The error of the context is returned when it is done while
+// the job is waited for.
+func (api *api) RerunJob(ctx context.Context, namespace, configPath, name string, opts RerunOptions) (*resource.Job, error) {
+	if opts.Wait && opts.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, opts.Timeout)
+		defer cancel()
+	}
+
+	created, err := api.namespaces.RerunJob(ctx, namespace, configPath, name)
+	if err != nil {
+		return nil, err
+	}
+
+	return api.waitJob(ctx, namespace, created, opts)
+}
+
+// TriggerCronJob creates a job from the template of a cronjob of the namespace, to run it at once.
+// It returns the created job, whose status is only known once it has been waited for, as with RerunJob.
+func (api *api) TriggerCronJob(ctx context.Context, namespace, name string, opts RerunOptions) (*resource.Job, error) {
+	if opts.Wait && opts.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, opts.Timeout)
+		defer cancel()
+	}
+
+	created, err := api.cronjobs.Trigger(namespace, name)
+	if err != nil {
+		return nil, err
+	}
+
+	return api.waitJob(ctx, namespace, created, opts)
+}
+
+// waitJob waits for a created job if requested, streaming its logs to the output of the options
+func (api *api) waitJob(ctx context.Context, namespace, created string, opts RerunOptions) (*resource.Job, error) {
+	if !opts.Wait {
+		return &resource.Job{Name: created, Status: resource.JobNotReady}, nil
+	}
+
+	if opts.Output != nil {
+		if err := api.job.Logs(ctx, namespace, created, opts.Output); err != nil {
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+			// the job is still waited for without its logs
+			logrus.WithField("job", created).Warn(err.Error())
+		}
+	}
+
+	job, err := api.job.Wait(ctx, namespace, created)
+	if err != nil && ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+
+	return job, err
+}
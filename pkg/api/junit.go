@@ -0,0 +1,79 @@
+package api
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"time"
+)
+
+// junitTestSuites is the root element of a JUnit XML report
+type junitTestSuites struct {
+	XMLName xml.Name         `xml:"testsuites"`
+	Suites  []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name     string          `xml:"name,attr"`
+	Tests    int             `xml:"tests,attr"`
+	Failures int             `xml:"failures,attr"`
+	Time     string          `xml:"time,attr"`
+	Cases    []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Time      string        `xml:"time,attr"`
+	Failure   *junitFailure `xml:"failure,omitempty"`
+	SystemOut string        `xml:"system-out,omitempty"`
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+}
+
+// WriteJUnit writes the report as a JUnit XML report, with one test suite for the namespace
+// and one test case per smoke test. The output of each test is reported as its system-out.
+func (r *TestReport) WriteJUnit(w io.Writer) error {
+	suite := junitTestSuite{
+		Name:     r.Namespace,
+		Tests:    len(r.Results),
+		Failures: r.Failed(),
+		Time:     junitTime(r.Duration),
+	}
+
+	for _, result := range r.Results {
+		tc := junitTestCase{
+			Name:      result.Name,
+			ClassName: fmt.Sprintf("%s.%s", r.Namespace, result.Kind),
+			Time:      junitTime(result.Duration),
+			SystemOut: result.Output,
+		}
+
+		if !result.Passed {
+			tc.Failure = &junitFailure{Message: result.Message, Type: result.Kind}
+		}
+
+		suite.Cases = append(suite.Cases, tc)
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(junitTestSuites{Suites: []junitTestSuite{suite}}); err != nil {
+		return fmt.Errorf("unable to write junit report: %v", err)
+	}
+
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+// junitTime formats a duration in seconds
+func junitTime(d time.Duration) string {
+	return fmt.Sprintf("%.3f", d.Seconds())
+}
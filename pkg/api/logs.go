@@ -0,0 +1,46 @@
+package api
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/DanielPickens/Keeper/pkg/resource"
+)
+
+// LogsOptions defines which logs of a namespace are streamed.
+// Selector is a label selector restricting the pods, and Workload restricts them to the pods of a workload.
+// Since only streams the logs newer than a duration, Previous streams the logs of the previous instance of the
+// restarted containers, and Follow keeps streaming until the context is done.
+type LogsOptions struct {
+	Selector string
+	Workload *WorkloadRef
+	Since    time.Duration
+	Previous bool
+	Follow   bool
+}
+
+// Logs streams the log lines of the containers of the pods of the namespace into lines, multiplexed.
+// It returns once every stream ended, or once the context is done when following the logs.
+// lines is not closed.
+func (api *api) Logs(ctx context.Context, namespace string, opts LogsOptions, lines chan<- resource.LogLine) error {
+	selectors := make([]string, 0, 2)
+	if opts.Selector != "" {
+		selectors = append(selectors, opts.Selector)
+	}
+
+	if opts.Workload != nil {
+		selector, err := api.pods.WorkloadSelector(namespace, opts.Workload.Kind, opts.Workload.Name)
+		if err != nil {
+			return err
+		}
+		selectors = append(selectors, selector)
+	}
+
+	return api.pods.Logs(ctx, namespace, resource.LogOptions{
+		Selector: strings.Join(selectors, ","),
+		Since:    opts.Since,
+		Previous: opts.Previous,
+		Follow:   opts.Follow,
+	}, lines)
+}
@@ -2,15 +2,17 @@ package api
 
 import (
 	"fmt"
+	"strings"
 	"time"
 
-	"github.com/danielpickens/keeper/pkg/resource"
-)
+	"github.com/sirupsen/logrus"
 
-const (
-	tickerDuration = 2 * time.Second
+	"github.com/DanielPickens/Keeper/pkg/resource"
 )
 
+// maxStatusWarnings is the number of latest warning events reported in a namespace status
+const maxStatusWarnings = 5
+
 // Namespace represents a kubernetes namespace enriched with information from the playbook.
 type Namespace struct {
 	//Name is the namespace name
@@ -21,6 +23,8 @@ type Namespace struct {
 	Status int
 	//Managed is true if the namespace as an associated inventory on the current playbook. False if not.
 	Managed bool
+	//Cluster is the kubeconfig context of the cluster hosting the namespace, when namespaces of several clusters are listed.
+	Cluster string
 }
 
 // ListNamespaces returns a list of Namespace.
@@ -53,38 +57,63 @@ func (api *api) ListNamespaces() ([]Namespace, error) {
 
 }
 
-type progress interface {
-	Set(int) error
+// GetStatus returns the status of the namespace with its latest warning events.
+// The warnings are best-effort : when the events cannot be listed, the error is logged and the status has no warnings.
+func (api *api) GetStatus(namespace string) (*resource.NamespaceStatus, error) {
+	status, err := api.namespaces.GetStatus(namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	warnings, err := api.events.List(namespace, resource.EventOptions{Type: resource.EventWarning})
+	if err != nil {
+		logrus.WithFields(logrus.Fields{
+			"namespace": namespace,
+		}).Warnf("unable to list the warning events of the namespace : %v", err)
+		return status, nil
+	}
+
+	status.Warnings = warnings.Latest(maxStatusWarnings)
+
+	return status, nil
 }
 
-// WaitForNamespaceReady wait until all pods in the specified namespace are ready.
-// And error is returned if the timeout is reach.
-func (api *api) WaitForNamespaceReady(namespace string, timeout time.Duration, bar progress) error {
-
-	ticker := time.NewTicker(tickerDuration)
-	timerCh := time.NewTimer(timeout).C
-	doneCh := make(chan bool)
-
-	go func(bar progress, ns resource.NamespaceService, namespace string) {
-		for range ticker.C {
-			status, err := ns.GetStatus(namespace)
-			if err != nil {
-				ticker.Stop()
-			}
-			bar.Set(status.Status)
-			if status.Status == 100 {
-				doneCh <- true
-			}
-		}
-	}(bar, api.namespaces, namespace)
-
-	for {
-		select {
-		case <-timerCh:
-			ticker.Stop()
-			return fmt.Errorf("time out : Some pods are not yet ready")
-		case <-doneCh:
-			return nil
-		}
+// DeleteOptions defines how a namespace is deleted.
+// When Wait is true, the deletion waits until the namespace is gone, for Timeout at most.
+// When ForceFinalizers is true, the finalizers still blocking the namespace after Timeout are cleared.
+// ConfigPath is the path of the configs whose pre-delete hooks are run before the namespace is deleted.
+type DeleteOptions struct {
+	Wait            bool
+	Timeout         time.Duration
+	ForceFinalizers bool
+	ConfigPath      string
+}
+
+// ErrorNamespaceStuck represents an error due to a namespace that stays terminating
+type ErrorNamespaceStuck struct {
+	Namespace string
+	Blockers  *resource.NamespaceBlockers
+}
+
+// Error returns the error message
+func (err ErrorNamespaceStuck) Error() string {
+	msg := fmt.Sprintf("namespace %s is still terminating", err.Namespace)
+
+	if err.Blockers == nil {
+		return msg
+	}
+
+	if len(err.Blockers.Finalizers) > 0 {
+		msg = fmt.Sprintf("%s\n- namespace finalizers: %s", msg, strings.Join(err.Blockers.Finalizers, ", "))
+	}
+
+	for _, cond := range err.Blockers.Conditions {
+		msg = fmt.Sprintf("%s\n- %s: %s", msg, cond.Reason, cond.Message)
 	}
+
+	for _, r := range err.Blockers.Resources {
+		msg = fmt.Sprintf("%s\n- %s %s: finalizers %s", msg, r.Kind, r.Name, strings.Join(r.Finalizers, ", "))
+	}
+
+	return msg
 }
@@ -0,0 +1,67 @@
+package api_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/api/core/v1"
+	kerr "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	k8stesting "k8s.io/client-go/testing"
+
+	"github.com/DanielPickens/Keeper/pkg/api"
+)
+
+func TestGetStatusWarnings(t *testing.T) {
+	event := &v1.Event{
+		ObjectMeta:     metav1.ObjectMeta{Name: "api.1", Namespace: "test"},
+		Type:           "Warning",
+		Reason:         "BackOff",
+		InvolvedObject: v1.ObjectReference{Kind: "Pod", Name: "api-x2k", Namespace: "test"},
+		Message:        "Back-off restarting failed container",
+		Count:          3,
+	}
+
+	a, _, client := newTestApi(t, newManagedNamespace("test"), event)
+
+	status, err := a.GetStatus("test")
+	assert.NoError(t, err)
+	assert.Len(t, status.Warnings, 1)
+	assert.Equal(t, "BackOff", status.Warnings[0].Reason)
+
+	// the warnings are left out when the events cannot be listed
+	client.PrependReactor("list", "events", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		return true, nil, kerr.NewForbidden(schema.GroupResource{Resource: "events"}, "", nil)
+	})
+
+	status, err = a.GetStatus("test")
+	assert.NoError(t, err)
+	assert.Empty(t, status.Warnings)
+
+	status, err = a.Namespaces().GetStatus("test")
+	assert.NoError(t, err)
+	assert.Empty(t, status.Warnings)
+}
+
+func TestDeleteWaitDeadline(t *testing.T) {
+	a, _, client := newTestApi(t, newManagedNamespace("test"))
+
+	// the namespace stays terminating
+	client.PrependReactor("delete", "namespaces", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		return true, nil, nil
+	})
+
+	err := a.Delete(context.Background(), "test", api.DeleteOptions{Wait: true, Timeout: 50 * time.Millisecond})
+	assert.IsType(t, api.ErrorNamespaceStuck{}, err)
+
+	// the deadline of the caller is not a stuck namespace
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	err = a.Delete(ctx, "test", api.DeleteOptions{Wait: true, Timeout: time.Hour, ForceFinalizers: true})
+	assert.Equal(t, context.DeadlineExceeded, err)
+}
@@ -0,0 +1,127 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/DanielPickens/Keeper/pkg/resource"
+)
+
+// PortMapping maps a port of a service to a local port, chosen by the system when it is 0.
+type PortMapping struct {
+	LocalPort int32
+	Port      int32
+}
+
+// PortForwardOptions defines which ports of which services are forwarded to localhost.
+// When Services is empty, all the services of the namespace having a ready pod are forwarded on local ports chosen by
+// the system. Otherwise, the ports of the services are forwarded on the same local ports, unless Ports picks some ports
+// of a single service and their local ports. Address is the local address to listen on, localhost when empty.
+type PortForwardOptions struct {
+	Services []string
+	Ports    []PortMapping
+	Address  string
+}
+
+// ParsePortMappings parses port mappings written as LOCAL:PORT, or as PORT to use the same local port.
+// An empty or 0 local port, such as in :8080, lets the system choose the local port.
+func ParsePortMappings(specs []string) ([]PortMapping, error) {
+	var mappings []PortMapping
+
+	for _, spec := range specs {
+		spec = strings.TrimSpace(spec)
+		if spec == "" {
+			continue
+		}
+
+		local, remote := spec, spec
+		if i := strings.Index(spec, ":"); i >= 0 {
+			local, remote = spec[:i], spec[i+1:]
+		}
+
+		port, err := parsePort(remote)
+		if err != nil || port == 0 {
+			return nil, fmt.Errorf("invalid port mapping %q: expected LOCAL:PORT or PORT", spec)
+		}
+
+		mapping := PortMapping{Port: port}
+		if local != "" {
+			if mapping.LocalPort, err = parsePort(local); err != nil {
+				return nil, fmt.Errorf("invalid port mapping %q: expected LOCAL:PORT or PORT", spec)
+			}
+		}
+
+		mappings = append(mappings, mapping)
+	}
+
+	return mappings, nil
+}
+
+// parsePort parses a TCP port number
+func parsePort(s string) (int32, error) {
+	port, err := strconv.ParseUint(s, 10, 16)
+	if err != nil {
+		return 0, err
+	}
+
+	return int32(port), nil
+}
+
+// PortForward forwards ports of services of the namespace to localhost through one of their ready pods, until the
+// context is done. Once the ports listen, ready is called with the forwarded ports and their local ports.
+func (api *api) PortForward(ctx context.Context, namespace string, opts PortForwardOptions, ready func([]resource.ForwardedPort)) error {
+	if len(opts.Ports) > 0 && len(opts.Services) != 1 {
+		return errors.New("ports can only be picked when forwarding a single service")
+	}
+
+	targets, err := api.services.ForwardTargets(namespace, opts.Services)
+	if err != nil {
+		return err
+	}
+
+	ports, err := mapPorts(targets, opts)
+	if err != nil {
+		return err
+	}
+	if len(ports) == 0 {
+		return fmt.Errorf("no service of %s can be forwarded", namespace)
+	}
+
+	return api.services.Forward(ctx, namespace, opts.Address, ports, ready)
+}
+
+// mapPorts sets the local ports of the ports to forward according to the options
+func mapPorts(targets []resource.ForwardedPort, opts PortForwardOptions) ([]resource.ForwardedPort, error) {
+	if len(opts.Services) == 0 {
+		// the local ports of all the services are chosen by the system, as they may overlap
+		return targets, nil
+	}
+
+	if len(opts.Ports) == 0 {
+		for i := range targets {
+			targets[i].LocalPort = targets[i].Port
+		}
+		return targets, nil
+	}
+
+	ports := make([]resource.ForwardedPort, 0, len(opts.Ports))
+	for _, m := range opts.Ports {
+		found := false
+		for _, t := range targets {
+			if t.Port == m.Port {
+				t.LocalPort = m.LocalPort
+				ports = append(ports, t)
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("the service %s has no port %d", opts.Services[0], m.Port)
+		}
+	}
+
+	return ports, nil
+}
@@ -0,0 +1,41 @@
+package api_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/DanielPickens/Keeper/pkg/api"
+	"github.com/DanielPickens/Keeper/pkg/resource"
+)
+
+func TestParsePortMappings(t *testing.T) {
+	mappings, err := api.ParsePortMappings([]string{"8080:80", "9090", ":443", " 0:53 ", ""})
+
+	assert.NoError(t, err)
+	assert.Equal(t, []api.PortMapping{
+		{LocalPort: 8080, Port: 80},
+		{LocalPort: 9090, Port: 9090},
+		{LocalPort: 0, Port: 443},
+		{LocalPort: 0, Port: 53},
+	}, mappings)
+}
+
+func TestParsePortMappingsInvalid(t *testing.T) {
+	for _, spec := range []string{"http", "8080:", "0", "70000", "a:80", "8080:80:90"} {
+		_, err := api.ParsePortMappings([]string{spec})
+		assert.Error(t, err, spec)
+	}
+}
+
+func TestPortForwardInvalid(t *testing.T) {
+	a, _, _ := newTestApi(t)
+	ready := func([]resource.ForwardedPort) {}
+
+	err := a.PortForward(context.Background(), "test", api.PortForwardOptions{Ports: []api.PortMapping{{Port: 80}}}, ready)
+	assert.Error(t, err)
+
+	err = a.PortForward(context.Background(), "test", api.PortForwardOptions{}, ready)
+	assert.EqualError(t, err, "no service of test can be forwarded")
+}
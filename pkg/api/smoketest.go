@@ -0,0 +1,265 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/DanielPickens/Keeper/pkg/playbook"
+	"github.com/DanielPickens/Keeper/pkg/resource"
+)
+
+const (
+	// Kinds of smoke tests
+	TestKindJob   = "Job"
+	TestKindProbe = "Probe"
+
+	defaultProbeTimeout  = 30 * time.Second
+	defaultProbeStatus   = http.StatusOK
+	probeRetryDuration   = 2 * time.Second
+	probeRequestDuration = 5 * time.Second
+)
+
+// TestOptions defines how smoke tests are run.
+// Timeout bounds each test Job. The logs of the test Jobs are streamed to Output, if set.
+type TestOptions struct {
+	Timeout time.Duration
+	Output  io.Writer
+}
+
+// TestResult represents the result of a smoke test.
+// Output holds the logs of a test Job or the last response of a probe.
+type TestResult struct {
+	Name     string        `json:"name"`
+	Kind     string        `json:"kind"`
+	Passed   bool          `json:"passed"`
+	Duration time.Duration `json:"duration"`
+	Message  string        `json:"message,omitempty"`
+	Output   string        `json:"output,omitempty"`
+}
+
+// TestReport represents the results of the smoke tests of a namespace
+type TestReport struct {
+	Namespace string        `json:"namespace"`
+	Duration  time.Duration `json:"duration"`
+	Results   []TestResult  `json:"results"`
+}
+
+// Failed returns the number of failed tests
+func (r *TestReport) Failed() int {
+	failed := 0
+	for _, result := range r.Results {
+		if !result.Passed {
+			failed++
+		}
+	}
+	return failed
+}
+
+// Passed returns true if all the tests passed
+func (r *TestReport) Passed() bool {
+	return r.Failed() == 0
+}
+
+// RunSmokeTests renders the smoke tests of the playbook with the inventory of the namespace and runs them :
+// test Jobs are run one after another and their logs are streamed, then HTTP probes are sent to the exposed services.
+// A test failing does not stop the next ones. An error is only returned if the tests cannot be run at all.
+func (api *api) RunSmokeTests(ctx context.Context, namespace string, opts TestOptions) (*TestReport, error) {
+	inv, err := api.inventories.Get(namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	tests, err := api.smoketests.Render(inv)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &TestReport{Namespace: namespace, Results: make([]TestResult, 0)}
+	start := time.Now()
+
+	for _, job := range tests.Jobs {
+		report.Results = append(report.Results, api.runTestJob(ctx, namespace, job, opts))
+	}
+
+	if len(tests.Probes) > 0 {
+		services, err := api.ListExposedServices(namespace)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, probe := range tests.Probes {
+			report.Results = append(report.Results, runProbe(ctx, probe, services))
+		}
+	}
+
+	report.Duration = time.Since(start)
+
+	return report, nil
+}
+
+// runTestJob runs a test Job, streams its logs and waits for its completion
+func (api *api) runTestJob(ctx context.Context, namespace string, config playbook.Config, opts TestOptions) (result TestResult) {
+	result = TestResult{Name: config.Name, Kind: TestKindJob}
+	start := time.Now()
+
+	defer func() { result.Duration = time.Since(start) }()
+
+	if opts.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, opts.Timeout)
+		defer cancel()
+	}
+
+	name, err := api.job.Run(ctx, namespace, []byte(config.Values))
+	if err != nil {
+		result.Message = err.Error()
+		return result
+	}
+	result.Name = name
+
+	// the job is deleted once its result is collected, even when the tests timed out or were cancelled
+	defer func() {
+		if err := api.job.Delete(context.Background(), namespace, name); err != nil {
+			logrus.WithFields(logrus.Fields{
+				"namespace": namespace,
+				"job":       name,
+			}).Warnf("unable to delete the test job : %v", err)
+		}
+	}()
+
+	logs := bytes.Buffer{}
+	out := io.Writer(&logs)
+	if opts.Output != nil {
+		out = io.MultiWriter(&logs, opts.Output)
+	}
+
+	if err := api.job.Logs(ctx, namespace, name, out); err != nil {
+		result.Message = err.Error()
+	}
+
+	job, err := api.job.Wait(ctx, namespace, name)
+	result.Output = logs.String()
+
+	switch {
+	case ctx.Err() == context.DeadlineExceeded:
+		result.Message = fmt.Sprintf("job %s did not complete within %s", name, opts.Timeout)
+	case err != nil:
+		result.Message = err.Error()
+	case job.Status == resource.JobFailed:
+		result.Message = fmt.Sprintf("job %s failed", name)
+		for _, cond := range job.Conditions {
+			if cond.Type == "Failed" && cond.Status == "True" {
+				result.Message = fmt.Sprintf("%s: %s: %s", result.Message, cond.Reason, cond.Message)
+			}
+		}
+	default:
+		result.Passed = true
+		result.Message = ""
+	}
+
+	return result
+}
+
+// runProbe sends requests to the service of the probe until it answers as expected or the probe times out
+func runProbe(ctx context.Context, probe playbook.Probe, services []resource.Service) (result TestResult) {
+	result = TestResult{Name: probe.Name, Kind: TestKindProbe}
+	start := time.Now()
+
+	defer func() { result.Duration = time.Since(start) }()
+
+	url, err := probeURL(probe, services)
+	if err != nil {
+		result.Message = err.Error()
+		return result
+	}
+
+	timeout := defaultProbeTimeout
+	if probe.TimeoutSeconds > 0 {
+		timeout = time.Duration(probe.TimeoutSeconds) * time.Second
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	client := &http.Client{Timeout: probeRequestDuration}
+
+	for {
+		var body string
+		body, err = probeOnce(ctx, client, url, probe)
+		result.Output = body
+
+		if err == nil {
+			result.Passed = true
+			return result
+		}
+
+		select {
+		case <-ctx.Done():
+			result.Message = fmt.Sprintf("GET %s: %v", url, err)
+			return result
+		case <-time.After(probeRetryDuration):
+		}
+	}
+}
+
+// probeOnce sends a request to url and checks the response against the probe expectations
+func probeOnce(ctx context.Context, client *http.Client, url string, probe playbook.Probe) (string, error) {
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return "", err
+	}
+
+	resp, err := client.Do(req.WithContext(ctx))
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	data, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return "", err
+	}
+	body := string(data)
+
+	expected := defaultProbeStatus
+	if probe.ExpectedStatus != 0 {
+		expected = probe.ExpectedStatus
+	}
+
+	if resp.StatusCode != expected {
+		return body, fmt.Errorf("expected status %d, got %d", expected, resp.StatusCode)
+	}
+
+	if probe.Contains != "" && !strings.Contains(body, probe.Contains) {
+		return body, fmt.Errorf("response does not contain %q", probe.Contains)
+	}
+
+	return body, nil
+}
+
+// probeURL returns the url of the probe, using the address and the exposed port of its service
+func probeURL(probe playbook.Probe, services []resource.Service) (string, error) {
+	for _, svc := range services {
+		if svc.Name != probe.Service {
+			continue
+		}
+
+		for _, p := range svc.Ports {
+			if probe.Port == 0 || p.Port == probe.Port {
+				return fmt.Sprintf("http://%s:%d/%s", svc.Addr, p.ExposedPort, strings.TrimPrefix(probe.Path, "/")), nil
+			}
+		}
+
+		return "", fmt.Errorf("service %s does not expose port %d", probe.Service, probe.Port)
+	}
+
+	return "", fmt.Errorf("service %s is not exposed", probe.Service)
+}
@@ -0,0 +1,90 @@
+package api_test
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	batchv1 "k8s.io/api/batch/v1"
+	"k8s.io/api/core/v1"
+	kerr "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	k8stesting "k8s.io/client-go/testing"
+
+	"github.com/DanielPickens/Keeper/pkg/api"
+	"github.com/DanielPickens/Keeper/pkg/playbook"
+)
+
+const smokeTestJob = `apiVersion: batch/v1
+kind: Job
+metadata:
+  name: smoke
+spec:
+  template:
+    spec:
+      restartPolicy: Never
+      containers:
+      - name: smoke
+        image: busybox
+`
+
+func TestRunSmokeTestsDeletesJobs(t *testing.T) {
+	a, f, client := newTestApi(t, newManagedNamespace("test"))
+	assert.NoError(t, f.Inventories().Create(playbook.Inventory{Namespace: "test"}))
+
+	tests := filepath.Join(filepath.Dir(f.ConfigPath()), "tests")
+	assert.NoError(t, os.Mkdir(tests, 0755))
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(tests, "smoke.yml.tpl"), []byte(smokeTestJob), 0644))
+
+	// the created jobs complete at once
+	client.PrependReactor("create", "jobs", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		job := action.(k8stesting.CreateAction).GetObject().(*batchv1.Job)
+		job.Status.Conditions = []batchv1.JobCondition{{Type: batchv1.JobComplete, Status: v1.ConditionTrue}}
+		return false, nil, nil
+	})
+
+	report, err := a.RunSmokeTests(context.Background(), "test", api.TestOptions{Timeout: time.Minute})
+	assert.NoError(t, err)
+	assert.Len(t, report.Results, 1)
+	assert.True(t, report.Results[0].Passed)
+
+	_, err = client.BatchV1().Jobs("test").Get(context.Background(), "smoke", metav1.GetOptions{})
+	assert.True(t, kerr.IsNotFound(err))
+}
+
+func TestTestReportWriteJUnit(t *testing.T) {
+	report := api.TestReport{
+		Namespace: "test",
+		Duration:  3 * time.Second,
+		Results: []api.TestResult{
+			{Name: "migrations", Kind: api.TestKindJob, Passed: true, Duration: 2 * time.Second, Output: "ok"},
+			{Name: "api-health", Kind: api.TestKindProbe, Duration: time.Second, Message: "expected status 200, got 503"},
+		},
+	}
+
+	assert.Equal(t, 1, report.Failed())
+	assert.False(t, report.Passed())
+
+	out := bytes.Buffer{}
+	assert.NoError(t, report.WriteJUnit(&out))
+
+	expected := `<?xml version="1.0" encoding="UTF-8"?>
+<testsuites>
+  <testsuite name="test" tests="2" failures="1" time="3.000">
+    <testcase name="migrations" classname="test.Job" time="2.000">
+      <system-out>ok</system-out>
+    </testcase>
+    <testcase name="api-health" classname="test.Probe" time="1.000">
+      <failure message="expected status 200, got 503" type="Probe"></failure>
+    </testcase>
+  </testsuite>
+</testsuites>
+`
+	assert.Equal(t, expected, out.String())
+}
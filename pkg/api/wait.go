@@ -0,0 +1,295 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/DanielPickens/Keeper/pkg/resource"
+)
+
+const (
+	// resyncDuration is the period at which the status is evaluated again, even if no watch event has been received
+	resyncDuration = 10 * time.Second
+)
+
+// workloadKinds maps the kinds (and their usual short names) accepted in a workload reference to the workload kinds.
+var workloadKinds = map[string]string{
+	"deployment":            resource.KindDeployment,
+	"deploy":                resource.KindDeployment,
+	"statefulset":           resource.KindStatefulset,
+	"sts":                   resource.KindStatefulset,
+	"daemonset":             resource.KindDaemonset,
+	"ds":                    resource.KindDaemonset,
+	"job":                   resource.KindJob,
+	"cronjob":               resource.KindCronJob,
+	"cj":                    resource.KindCronJob,
+	"persistentvolumeclaim": resource.KindPersistentVolumeClaim,
+	"pvc":                   resource.KindPersistentVolumeClaim,
+	"ingress":               resource.KindIngress,
+	"ing":                   resource.KindIngress,
+}
+
+type progress interface {
+	Set(int) error
+}
+
+// WorkloadRef references a workload of a namespace by its kind and its name.
+type WorkloadRef struct {
+	Kind string `json:"kind"`
+	Name string `json:"name"`
+}
+
+// String returns the reference written as kind/name
+func (ref WorkloadRef) String() string {
+	return fmt.Sprintf("%s/%s", strings.ToLower(ref.Kind), ref.Name)
+}
+
+// ParseWorkloadRefs parses workload references written as kind/name, such as deployment/api or job/migrate.
+// Kinds are case insensitive and may use their short names (deploy, sts, ds, cj, pvc, ing).
+// Kinds that are not built in (custom resources) are kept as is.
+func ParseWorkloadRefs(refs []string) ([]WorkloadRef, error) {
+	var workloads []WorkloadRef
+
+	for _, ref := range refs {
+		ref = strings.TrimSpace(ref)
+		if ref == "" {
+			continue
+		}
+
+		parts := strings.SplitN(ref, "/", 2)
+		if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+			return nil, fmt.Errorf("invalid workload %q: expected kind/name", ref)
+		}
+
+		kind, ok := workloadKinds[strings.ToLower(parts[0])]
+		if !ok {
+			kind = parts[0]
+		}
+
+		workloads = append(workloads, WorkloadRef{Kind: kind, Name: parts[1]})
+	}
+
+	return workloads, nil
+}
+
+// WaitOptions defines how to wait for a namespace to be ready.
+// When FailFast is true, the wait stops as soon as a terminal failure is detected : a failed job,
+// a pod that cannot pull its image or a container that restarted at least MaxRestarts times in a crash loop.
+// When Workloads is not empty, only these workloads are waited for instead of the whole namespace.
+// A zero Timeout waits until the context is done.
+type WaitOptions struct {
+	Timeout     time.Duration
+	FailFast    bool
+	MaxRestarts int32
+	Workloads   []WorkloadRef
+}
+
+// WaitResult describes what was and was not ready when the wait ended.
+type WaitResult struct {
+	Namespace        string                    `json:"namespace"`
+	Ready            bool                      `json:"ready"`
+	TimedOut         bool                      `json:"timedOut"`
+	Status           int                       `json:"status"`
+	ReadyWorkloads   []resource.WorkloadStatus `json:"readyWorkloads"`
+	PendingWorkloads []resource.WorkloadStatus `json:"pendingWorkloads"`
+	Missing          []WorkloadRef             `json:"missing,omitempty"`
+	Failures         []resource.Failure        `json:"failures,omitempty"`
+}
+
+// Pending returns the workloads that were not ready, written as kind/name, including the missing ones
+func (r *WaitResult) Pending() []string {
+	var pending []string
+
+	for _, w := range r.PendingWorkloads {
+		pending = append(pending, WorkloadRef{Kind: w.Kind, Name: w.Name}.String())
+	}
+
+	for _, ref := range r.Missing {
+		pending = append(pending, fmt.Sprintf("%s (not found)", ref.String()))
+	}
+
+	return pending
+}
+
+// ErrorNamespaceFailed represents an error due to terminal failures detected while waiting for a namespace to be ready
+type ErrorNamespaceFailed struct {
+	Namespace string
+	Failures  []resource.Failure
+}
+
+// Error returns the error message
+func (err ErrorNamespaceFailed) Error() string {
+	msg := fmt.Sprintf("namespace %s will not be ready :", err.Namespace)
+
+	for _, f := range err.Failures {
+		msg = fmt.Sprintf("%s\n- %s", msg, f.String())
+	}
+
+	return msg
+}
+
+// ErrorWaitTimeout represents an error due to a namespace not being ready before the deadline
+type ErrorWaitTimeout struct {
+	Namespace string
+	Pending   []string
+}
+
+// Error returns the error message
+func (err ErrorWaitTimeout) Error() string {
+	if len(err.Pending) == 0 {
+		return fmt.Sprintf("time out : namespace %s is not yet ready", err.Namespace)
+	}
+	return fmt.Sprintf("time out : namespace %s is not yet ready, still waiting for %s", err.Namespace, strings.Join(err.Pending, ", "))
+}
+
+// WaitForNamespaceReady waits until all workloads in the specified namespace, or only opts.Workloads if set, are ready.
+// The status is evaluated again each time a workload or a pod of the namespace changes.
+// It always returns the result of the last evaluation. An ErrorWaitTimeout is returned if the timeout is reached,
+// and the context error if the context is cancelled.
+// If fail fast is enabled, an ErrorNamespaceFailed is returned as soon as a terminal failure is detected.
+// bar may be nil.
+func (api *api) WaitForNamespaceReady(ctx context.Context, namespace string, opts WaitOptions, bar progress) (*WaitResult, error) {
+	var cancel context.CancelFunc
+	if opts.Timeout > 0 {
+		ctx, cancel = context.WithTimeout(ctx, opts.Timeout)
+	} else {
+		ctx, cancel = context.WithCancel(ctx)
+	}
+	defer cancel()
+
+	changes := make(chan struct{}, 1)
+	watchErrCh := make(chan error, 1)
+	watch := func() {
+		watchErrCh <- api.namespaces.WatchWorkloads(ctx, namespace, changes)
+	}
+	go watch()
+
+	ticker := time.NewTicker(resyncDuration)
+	defer ticker.Stop()
+
+	result := &WaitResult{Namespace: namespace}
+
+	for {
+		status, err := api.namespaces.GetStatus(namespace)
+		if err != nil {
+			logrus.WithFields(logrus.Fields{
+				"namespace": namespace,
+			}).Warnf("unable to get namespace status: %v", err)
+		} else {
+			result = evaluate(namespace, status, opts)
+
+			if bar != nil {
+				bar.Set(result.Status)
+			}
+
+			if result.Ready {
+				return result, nil
+			}
+
+			if opts.FailFast && len(result.Failures) > 0 {
+				return result, ErrorNamespaceFailed{Namespace: namespace, Failures: result.Failures}
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			if ctx.Err() == context.DeadlineExceeded {
+				result.TimedOut = true
+				return result, ErrorWaitTimeout{Namespace: namespace, Pending: result.Pending()}
+			}
+			return result, ctx.Err()
+		case <-changes:
+		case <-ticker.C:
+		case err := <-watchErrCh:
+			if err != nil {
+				// keep on waiting, relying only on the periodic resync
+				logrus.WithFields(logrus.Fields{
+					"namespace": namespace,
+				}).Warnf("unable to watch namespace workloads: %v", err)
+				watchErrCh = nil
+				continue
+			}
+			// the api server closed a watch, start watching again
+			if ctx.Err() == nil {
+				go watch()
+			}
+		}
+	}
+}
+
+// evaluate computes the wait result from a namespace status.
+// When workloads are specified, the result only takes them, and their pods, into account.
+func evaluate(namespace string, status *resource.NamespaceStatus, opts WaitOptions) *WaitResult {
+	result := &WaitResult{
+		Namespace:        namespace,
+		ReadyWorkloads:   make([]resource.WorkloadStatus, 0),
+		PendingWorkloads: make([]resource.WorkloadStatus, 0),
+	}
+
+	failures := status.TerminalFailures(opts.MaxRestarts)
+
+	if len(opts.Workloads) == 0 {
+		for _, w := range status.Workloads {
+			if w.IsReady {
+				result.ReadyWorkloads = append(result.ReadyWorkloads, w)
+			} else {
+				result.PendingWorkloads = append(result.PendingWorkloads, w)
+			}
+		}
+		result.Status = status.Status
+		result.Ready = status.Status == 100
+		result.Failures = failures
+		return result
+	}
+
+	for _, ref := range opts.Workloads {
+		w, ok := findWorkload(status.Workloads, ref)
+		switch {
+		case !ok:
+			result.Missing = append(result.Missing, ref)
+		case w.IsReady:
+			result.ReadyWorkloads = append(result.ReadyWorkloads, w)
+		default:
+			result.PendingWorkloads = append(result.PendingWorkloads, w)
+		}
+	}
+
+	result.Status = len(result.ReadyWorkloads) * 100 / len(opts.Workloads)
+	result.Ready = len(result.ReadyWorkloads) == len(opts.Workloads)
+
+	for _, f := range failures {
+		if concerns(f, opts.Workloads) {
+			result.Failures = append(result.Failures, f)
+		}
+	}
+
+	return result
+}
+
+// findWorkload returns the status of the referenced workload
+func findWorkload(workloads []resource.WorkloadStatus, ref WorkloadRef) (resource.WorkloadStatus, bool) {
+	for _, w := range workloads {
+		if strings.EqualFold(w.Kind, ref.Kind) && w.Name == ref.Name {
+			return w, true
+		}
+	}
+	return resource.WorkloadStatus{}, false
+}
+
+// concerns returns true if the failure is about one of the workloads or one of their pods.
+// Pods are matched by name, as their names are prefixed by the name of the workload that owns them.
+func concerns(f resource.Failure, workloads []WorkloadRef) bool {
+	for _, ref := range workloads {
+		if strings.EqualFold(f.Kind, ref.Kind) && f.Name == ref.Name {
+			return true
+		}
+		if f.Kind == "Pod" && strings.HasPrefix(f.Name, ref.Name+"-") {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,39 @@
+package api_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/DanielPickens/Keeper/pkg/api"
+	"github.com/DanielPickens/Keeper/pkg/resource"
+)
+
+func TestParseWorkloadRefs(t *testing.T) {
+	refs, err := api.ParseWorkloadRefs([]string{"deployment/api", "Job/migrate", "sts/db", " cj/backup ", "", "Certificate/tls"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, []api.WorkloadRef{
+		{Kind: resource.KindDeployment, Name: "api"},
+		{Kind: resource.KindJob, Name: "migrate"},
+		{Kind: resource.KindStatefulset, Name: "db"},
+		{Kind: resource.KindCronJob, Name: "backup"},
+		{Kind: "Certificate", Name: "tls"},
+	}, refs)
+}
+
+func TestParseWorkloadRefsInvalid(t *testing.T) {
+	for _, ref := range []string{"api", "deployment/", "/api"} {
+		_, err := api.ParseWorkloadRefs([]string{ref})
+		assert.Error(t, err, ref)
+	}
+}
+
+func TestWaitResultPending(t *testing.T) {
+	result := api.WaitResult{
+		PendingWorkloads: []resource.WorkloadStatus{{Kind: resource.KindDeployment, Name: "api"}},
+		Missing:          []api.WorkloadRef{{Kind: resource.KindJob, Name: "migrate"}},
+	}
+
+	assert.Equal(t, []string{"deployment/api", "job/migrate (not found)"}, result.Pending())
+}
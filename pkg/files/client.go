@@ -1,12 +1,11 @@
 package files
 
 import (
-	"fmt";
+	"fmt"
 	"os"
-	"time"
 	"path/filepath"
 
-	"github.com/danielpickens/keeper/pkg/playbook"
+	"github.com/DanielPickens/Keeper/pkg/playbook"
 )
 
 const (
@@ -14,69 +13,91 @@ const (
 	configDir    = "configs"
 	inventoryDir = "inventories"
 	defaultFile  = "defaults.json"
+	settingsFile = "settings.json"
+	testsDir     = "tests"
+	historyDir   = "history"
+	tplSuffix    = ".tpl"
 )
 
 type Client struct {
 	configs       playbook.ConfigRepository
 	inventories   playbook.InventoryRepository
 	playbooks     playbook.PlaybookRepository
+	history       playbook.HistoryRepository
 	inventoryPath string
 	configPath    string
 }
 
 func NewClient(wd string) (*Client, error) {
-	if ok, _, := fileExists(wd); ok != true {
-		return &Client{}. fmt.Errorf("Your specific working directory doesn't exist : %s", wd)
+	if ok, _ := fileExists(wd); ok != true {
+		return &Client{}, fmt.Errorf("Your specific working directory doesn't exist : %s", wd)
 
 	}
 
-	templatepath := filepath.Join(wd,templateDir)
+	templatePath := filepath.Join(wd, templateDir)
 	configPath := filepath.Join(wd, configDir)
-	inventorypath := filepath.Join(wd, inventoryDir)
-	defaultpath := filepath.Join(wd, defaultFile) 
+	inventoryPath := filepath.Join(wd, inventoryDir)
+	defaultPath := filepath.Join(wd, defaultFile)
+	settingsPath := filepath.Join(wd, settingsFile)
+	testsPath := filepath.Join(wd, testsDir)
+	historyPath := filepath.Join(wd, historyDir)
 
-	if ok, _ := fileExists(templatepath); ok != true {
+	if ok, _ := fileExists(templatePath); ok != true {
 
-		return *Client{} fmt.Errorf("Your playbook must contain a `%s` specific dir. No playbook has been found.\n" + "Please check that the playbook is in a working directory using --dir option.", templateDir)
+		return &Client{}, fmt.Errorf("Your playbook must contain a `%s` specific dir. No playbook has been found.\n"+"Please check that the playbook is in a working directory using --dir option.", templateDir)
 
 	}
 
-	if ok _ := fileExists(defaultpath): ok != true {
-		return *Client{} fmt.Errorf("Your working directory must contain a `%s` a file. .\n" + "Please check that the playbook is in a working directory using --dir option.", defaultFile)
+	if ok, _ := fileExists(defaultPath); ok != true {
+		return &Client{}, fmt.Errorf("Your working directory must contain a `%s` a file. .\n"+"Please check that the playbook is in a working directory using --dir option.", defaultFile)
 	}
 
 	if ok, _ := fileExists(configPath); ok != true {
-		if err := os.Mkdir(configPath, 0000); err != nil {
-			return &Client{} fmt.Errorf("Impossible to create working %s directory. Please check the directory permissions. ",inventoryDir)
+		if err := os.Mkdir(configPath, 0755); err != nil {
+			return &Client{}, fmt.Errorf("Impossible to create working %s directory. Please check the directory permissions. ", configDir)
 		}
 
 	}
 
-	return &Client {
-		configs: NewConfigRepository(configPath), 
-		inventories: NewInventoryRepository(inventoryPath), 
-		playbooks: NewPlaybookRepository(templatepath,defaultpath)
-		configPath: configPath;
+	return &Client{
+		configs:       NewConfigRepository(configPath),
+		inventories:   NewInventoryRepository(inventoryPath),
+		playbooks:     NewPlaybookRepository(templatePath, defaultPath, settingsPath, testsPath),
+		history:       NewHistoryRepository(historyPath),
+		inventoryPath: inventoryPath,
+		configPath:    configPath,
 	}, nil
 
 }
 
-func (c, *Client) Configs() playbook.ConfigRepository {
+func (c *Client) Configs() playbook.ConfigRepository {
 	return c.configs
 }
 
-func (c, *Client) Inventories() playbook.InventoryRepository {
-	return c.inventories		
+func (c *Client) Inventories() playbook.InventoryRepository {
+	return c.inventories
 
 }
 
-func (c, *Client) ConfigPath() string {
+func (c *Client) Playbooks() playbook.PlaybookRepository {
+	return c.playbooks
+}
+
+func (c *Client) History() playbook.HistoryRepository {
+	return c.history
+}
+
+func (c *Client) ConfigPath() string {
 	return c.configPath
 }
 
+func (c *Client) InventoryPath() string {
+	return c.inventoryPath
+}
+
 func fileExists(path string) (bool, error) {
-	if err, _ := os.Stat(path); err != nil {
-		if os.DoesNotExist(err) {
+	if _, err := os.Stat(path); err != nil {
+		if os.IsNotExist(err) {
 			return false, nil
 		}
 
@@ -85,5 +106,3 @@ func fileExists(path string) (bool, error) {
 
 	return true, nil
 }
-
-
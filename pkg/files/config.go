@@ -0,0 +1,104 @@
+package files
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/DanielPickens/Keeper/pkg/playbook"
+)
+
+type configs struct {
+	configPath string
+}
+
+// NewConfigRepository returns a new ConfigRepository storing the configs of each namespace
+// in a directory named after the namespace in the configs directory of the playbook.
+func NewConfigRepository(configPath string) playbook.ConfigRepository {
+	return &configs{
+		configPath,
+	}
+}
+
+// Save replaces the configs of the given namespace
+func (c *configs) Save(namespace string, cfgs []playbook.Config) error {
+	dir := filepath.Join(c.configPath, namespace)
+
+	// configs rendered from templates removed from the playbook must not be applied anymore
+	if err := os.RemoveAll(dir); err != nil {
+		return fmt.Errorf("unable to delete previous configs of %s: %v", namespace, err)
+	}
+
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("unable to create configs directory of %s: %v", namespace, err)
+	}
+
+	for _, cfg := range cfgs {
+		if err := ioutil.WriteFile(filepath.Join(dir, cfg.Name), []byte(cfg.Values), 0644); err != nil {
+			return fmt.Errorf("unable to write config %s of %s: %v", cfg.Name, namespace, err)
+		}
+	}
+
+	return nil
+}
+
+// Get reads the configs of the given namespace. No config is returned if the namespace has no configs directory.
+func (c *configs) Get(namespace string) ([]playbook.Config, error) {
+	dir := filepath.Join(c.configPath, namespace)
+
+	entries, err := ioutil.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("unable to read configs of %s: %v", namespace, err)
+	}
+
+	var cfgs []playbook.Config
+
+	for _, e := range entries {
+		if e.IsDir() {
+			continue
+		}
+
+		values, err := ioutil.ReadFile(filepath.Join(dir, e.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("unable to read config %s of %s: %v", e.Name(), namespace, err)
+		}
+
+		cfgs = append(cfgs, playbook.Config{Name: e.Name(), Values: string(values)})
+	}
+
+	return cfgs, nil
+}
+
+// Delete deletes the configs directory of the given namespace
+func (c *configs) Delete(namespace string) error {
+	if err := os.RemoveAll(filepath.Join(c.configPath, namespace)); err != nil {
+		return fmt.Errorf("unable to delete configs of %s: %v", namespace, err)
+	}
+
+	return nil
+}
+
+// List returns the namespaces having rendered configs
+func (c *configs) List() ([]string, error) {
+	entries, err := ioutil.ReadDir(c.configPath)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("unable to list configs: %v", err)
+	}
+
+	var namespaces []string
+
+	for _, e := range entries {
+		if e.IsDir() {
+			namespaces = append(namespaces, e.Name())
+		}
+	}
+
+	return namespaces, nil
+}
@@ -0,0 +1,107 @@
+package files
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"github.com/DanielPickens/Keeper/pkg/playbook"
+)
+
+const releaseSuffix = ".json"
+
+type history struct {
+	historyPath string
+}
+
+// NewHistoryRepository returns a new HistoryRepository storing each release of a namespace as a json file
+// named after its revision, in a directory named after the namespace in the history directory of the playbook.
+func NewHistoryRepository(historyPath string) playbook.HistoryRepository {
+	return &history{
+		historyPath,
+	}
+}
+
+// List reads all the releases of the given namespace
+func (h *history) List(namespace string) ([]playbook.ReleaseRecord, error) {
+	files, err := filepath.Glob(filepath.Join(h.historyPath, namespace, "*"+releaseSuffix))
+	if err != nil {
+		return nil, err
+	}
+
+	records := make([]playbook.ReleaseRecord, 0, len(files))
+
+	for _, f := range files {
+		record, err := h.read(f)
+		if err != nil {
+			return nil, err
+		}
+
+		records = append(records, record)
+	}
+
+	return records, nil
+}
+
+// Get reads the given release of a namespace
+func (h *history) Get(namespace string, revision int) (playbook.ReleaseRecord, error) {
+	record, err := h.read(h.path(namespace, revision))
+	if os.IsNotExist(err) {
+		return playbook.ReleaseRecord{}, playbook.NewErrorReleaseNotFound(namespace, revision)
+	}
+
+	return record, err
+}
+
+// Save writes a release of a namespace, replacing the release with the same revision if any
+func (h *history) Save(record playbook.ReleaseRecord) error {
+	if err := os.MkdirAll(filepath.Join(h.historyPath, record.Namespace), 0755); err != nil {
+		return fmt.Errorf("unable to create history directory of %s: %v", record.Namespace, err)
+	}
+
+	data, err := json.MarshalIndent(record, "", "  ")
+	if err != nil {
+		return fmt.Errorf("unable to write release %d of %s: %v", record.Revision, record.Namespace, err)
+	}
+
+	if err := ioutil.WriteFile(h.path(record.Namespace, record.Revision), data, 0644); err != nil {
+		return fmt.Errorf("unable to write release %d of %s: %v", record.Revision, record.Namespace, err)
+	}
+
+	return nil
+}
+
+// Delete deletes the history directory of the given namespace
+func (h *history) Delete(namespace string) error {
+	if err := os.RemoveAll(filepath.Join(h.historyPath, namespace)); err != nil {
+		return fmt.Errorf("unable to delete history of %s: %v", namespace, err)
+	}
+
+	return nil
+}
+
+// read reads a release file. The error is returned as is when the file does not exist.
+func (h *history) read(path string) (playbook.ReleaseRecord, error) {
+	data, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return playbook.ReleaseRecord{}, err
+	}
+	if err != nil {
+		return playbook.ReleaseRecord{}, fmt.Errorf("unable to read release %s: %v", path, err)
+	}
+
+	var record playbook.ReleaseRecord
+
+	if err := json.Unmarshal(data, &record); err != nil {
+		return playbook.ReleaseRecord{}, fmt.Errorf("unable to read release %s: %v", path, err)
+	}
+
+	return record, nil
+}
+
+func (h *history) path(namespace string, revision int) string {
+	return filepath.Join(h.historyPath, namespace, strconv.Itoa(revision)+releaseSuffix)
+}
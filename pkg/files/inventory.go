@@ -0,0 +1,118 @@
+package files
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/DanielPickens/Keeper/pkg/playbook"
+)
+
+const inventorySuffix = ".json"
+
+type inventories struct {
+	inventoryPath string
+}
+
+// NewInventoryRepository returns a new InventoryRepository storing each inventory as a json file
+// named after its namespace in the inventories directory of the playbook.
+func NewInventoryRepository(inventoryPath string) playbook.InventoryRepository {
+	return &inventories{
+		inventoryPath,
+	}
+}
+
+// Get reads the inventory of the given namespace
+func (i *inventories) Get(namespace string) (playbook.Inventory, error) {
+	data, err := ioutil.ReadFile(i.path(namespace))
+	if os.IsNotExist(err) {
+		return playbook.Inventory{}, playbook.NewErrorInventoryNotFound(namespace)
+	}
+	if err != nil {
+		return playbook.Inventory{}, fmt.Errorf("unable to read inventory %s: %v", namespace, err)
+	}
+
+	var inv playbook.Inventory
+
+	if err := json.Unmarshal(data, &inv); err != nil {
+		return playbook.Inventory{}, fmt.Errorf("unable to read inventory %s: %v", namespace, err)
+	}
+
+	return inv, nil
+}
+
+// Exists returns true if an inventory file exists for the given namespace
+func (i *inventories) Exists(namespace string) bool {
+	ok, _ := fileExists(i.path(namespace))
+	return ok
+}
+
+// Create writes a new inventory file. An error is returned if the inventory already exists.
+func (i *inventories) Create(inv playbook.Inventory) error {
+	if i.Exists(inv.Namespace) {
+		return playbook.NewErrorInventoryAlreadyExist(inv.Namespace)
+	}
+
+	return i.write(inv)
+}
+
+// Delete deletes the inventory file of the given namespace
+func (i *inventories) Delete(namespace string) error {
+	if err := os.Remove(i.path(namespace)); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("unable to delete inventory %s: %v", namespace, err)
+	}
+
+	return nil
+}
+
+// Update replaces the inventory file of the given namespace
+func (i *inventories) Update(namespace string, inv playbook.Inventory) error {
+	inv.Namespace = namespace
+
+	return i.write(inv)
+}
+
+// List reads all the inventory files
+func (i *inventories) List() ([]playbook.Inventory, error) {
+	files, err := filepath.Glob(filepath.Join(i.inventoryPath, "*"+inventorySuffix))
+	if err != nil {
+		return nil, err
+	}
+
+	invs := make([]playbook.Inventory, 0, len(files))
+
+	for _, f := range files {
+		inv, err := i.Get(strings.TrimSuffix(filepath.Base(f), inventorySuffix))
+		if err != nil {
+			return nil, err
+		}
+
+		invs = append(invs, inv)
+	}
+
+	return invs, nil
+}
+
+func (i *inventories) write(inv playbook.Inventory) error {
+	if err := os.MkdirAll(i.inventoryPath, 0755); err != nil {
+		return fmt.Errorf("unable to create inventories directory: %v", err)
+	}
+
+	data, err := json.MarshalIndent(inv, "", "  ")
+	if err != nil {
+		return fmt.Errorf("unable to write inventory %s: %v", inv.Namespace, err)
+	}
+
+	if err := ioutil.WriteFile(i.path(inv.Namespace), data, 0644); err != nil {
+		return fmt.Errorf("unable to write inventory %s: %v", inv.Namespace, err)
+	}
+
+	return nil
+}
+
+func (i *inventories) path(namespace string) string {
+	return filepath.Join(i.inventoryPath, namespace+inventorySuffix)
+}
@@ -16,12 +16,16 @@ import (
 type playbooks struct {
 	templatePath string
 	defaultsPath string
+	settingsPath string
+	testsPath    string
 }
 
-func NewPlaybookRepository(templatePath, defaultsPath string) playbook.PlaybookRepository {
+func NewPlaybookRepository(templatePath, defaultsPath, settingsPath, testsPath string) playbook.PlaybookRepository {
 	return &playbooks{
 		templatePath,
 		defaultsPath,
+		settingsPath,
+		testsPath,
 	}
 }
 
@@ -35,6 +39,20 @@ func (p *playbooks) GetTemplate() ([]playbook.ConfigTemplate, error) {
 		return nil, fmt.Errorf("no template files found in directory %s", p.templatePath)
 	}
 
+	return p.parseTemplates(templates)
+}
+
+// GetSmokeTests returns the smoke tests templates from the playbook tests directory.
+// The tests directory is optional : no template is returned if it does not exist.
+func (p *playbooks) GetSmokeTests() ([]playbook.ConfigTemplate, error) {
+	templates, _ := filepath.Glob(fmt.Sprintf("%s/*%s", p.testsPath, tplSuffix))
+
+	return p.parseTemplates(templates)
+}
+
+// parseTemplates parses the given template files.
+// Each config template is named after its file, without the .tpl extension.
+func (p *playbooks) parseTemplates(templates []string) ([]playbook.ConfigTemplate, error) {
 	var cfgTpl []playbook.ConfigTemplate
 
 	for _, templ := range templates {
@@ -80,6 +98,27 @@ func (p *playbooks) GetDefault() (playbook.Inventory, error) {
 	return inventory, nil
 }
 
+// GetSettings reads the playbook settings file.
+// The settings file is optional : empty settings are returned if it does not exist.
+func (p *playbooks) GetSettings() (playbook.Settings, error) {
+	var settings playbook.Settings
+
+	if ok, _ := fileExists(p.settingsPath); !ok {
+		return settings, nil
+	}
+
+	data, err := ioutil.ReadFile(p.settingsPath)
+	if err != nil {
+		return settings, playbook.NewErrorReadingSettingsFile(err)
+	}
+
+	if err := json.Unmarshal(data, &settings); err != nil {
+		return playbook.Settings{}, playbook.NewErrorReadingSettingsFile(err)
+	}
+
+	return settings, nil
+}
+
 func (p *playbooks) initFuncMap(t *template.Template) {
 	z := sprig.TxtFuncMap()
 	delete(z, "env")
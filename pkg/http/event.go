@@ -0,0 +1,79 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/DanielPickens/Keeper/pkg/api"
+	"github.com/DanielPickens/Keeper/pkg/resource"
+)
+
+// ListEvents returns the events of the namespace associated to an inventory, sorted by the last time they occurred.
+// Query parameters are type (e.g. Warning), object (e.g. deployment/api or pod/api-7d9f-x2k) and watch.
+// With watch, the events occurring from then on are streamed instead, one JSON object per line, until the client
+// goes away. An error stopping the watch is streamed as a last {"error": ...} line.
+func (v *Handler) ListEvents(c *gin.Context) {
+	namespace := c.Params.ByName("namespace")
+	opts := resource.EventOptions{Type: c.Query("type")}
+
+	if o := c.Query("object"); o != "" {
+		objects, err := api.ParseWorkloadRefs([]string{o})
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		opts.Kind, opts.Name = objects[0].Kind, objects[0].Name
+	}
+
+	watch := false
+	if w := c.Query("watch"); w != "" {
+		var err error
+		if watch, err = strconv.ParseBool(w); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	if !watch {
+		events, err := v.api.Events().List(namespace, opts)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		c.JSON(http.StatusOK, events)
+		return
+	}
+
+	events := make(chan resource.Event)
+	errs := make(chan error, 1)
+
+	go func() {
+		errs <- v.api.Events().Watch(c.Request.Context(), namespace, opts, events)
+		close(events)
+	}()
+
+	// the response is started right away, a quiet namespace may have no event for a long time
+	c.Header("Content-Type", "application/x-ndjson")
+	c.Status(http.StatusOK)
+	c.Writer.WriteHeaderNow()
+	c.Writer.Flush()
+
+	enc := json.NewEncoder(c.Writer)
+
+	for event := range events {
+		// once the client went away, the events are drained until the watch stops with the request context
+		if err := enc.Encode(event); err == nil {
+			c.Writer.Flush()
+		}
+	}
+
+	// the status is already sent : an error stopping the watch is streamed as the last line
+	if err := <-errs; err != nil && c.Request.Context().Err() == nil {
+		enc.Encode(gin.H{"error": err.Error()})
+		c.Writer.Flush()
+	}
+}
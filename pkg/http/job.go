@@ -0,0 +1,66 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/DanielPickens/Keeper/pkg/api"
+	"github.com/DanielPickens/Keeper/pkg/resource"
+)
+
+// RerunJob deletes a job of the namespace and creates it again from the rendered configs of the namespace.
+// Query parameters are wait, to wait until the job is completed or failed, and timeout (e.g. 2m).
+// It responds 202 with the job when it is not waited for. Otherwise, it responds 200 with the job and its logs once
+// completed, 422 when it failed and 408 when the timeout is reached. It responds 404 when the job is not part of the
+// configs of the namespace.
+func (v *Handler) RerunJob(c *gin.Context) {
+	opts := api.RerunOptions{Timeout: defaultWaitTimeout}
+
+	var err error
+
+	if w := c.Query("wait"); w != "" {
+		if opts.Wait, err = strconv.ParseBool(w); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	if t := c.Query("timeout"); t != "" {
+		if opts.Timeout, err = time.ParseDuration(t); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	logs := bytes.Buffer{}
+	opts.Output = &logs
+
+	job, err := v.api.RerunJob(c.Request.Context(), c.Params.ByName("namespace"), v.configPath, c.Params.ByName("resource"), opts)
+	if err != nil {
+		_, notInConfigs := err.(resource.ErrorJobNotInConfigs)
+
+		switch {
+		case notInConfigs:
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		case err == context.DeadlineExceeded:
+			c.JSON(http.StatusRequestTimeout, gin.H{"error": err.Error(), "logs": logs.String()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	switch {
+	case !opts.Wait:
+		c.JSON(http.StatusAccepted, gin.H{"job": job})
+	case job.Status == resource.JobFailed:
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"job": job, "logs": logs.String()})
+	default:
+		c.JSON(http.StatusOK, gin.H{"job": job, "logs": logs.String()})
+	}
+}
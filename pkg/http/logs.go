@@ -0,0 +1,90 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/DanielPickens/Keeper/pkg/api"
+	"github.com/DanielPickens/Keeper/pkg/resource"
+)
+
+// Logs streams the logs of the pods of the namespace associated to an inventory, one JSON object per line
+// holding the pod, the container, the time and the message of a log line.
+// Query parameters are selector (e.g. app=api), workload (e.g. deployment/api), since (e.g. 10m), previous and
+// follow. Logs are followed by default, until the client goes away.
+func (v *Handler) Logs(c *gin.Context) {
+	opts := api.LogsOptions{
+		Selector: c.Query("selector"),
+		Follow:   true,
+	}
+
+	var err error
+
+	if w := c.Query("workload"); w != "" {
+		workloads, err := api.ParseWorkloadRefs([]string{w})
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		opts.Workload = &workloads[0]
+	}
+
+	if s := c.Query("since"); s != "" {
+		if opts.Since, err = time.ParseDuration(s); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	if p := c.Query("previous"); p != "" {
+		if opts.Previous, err = strconv.ParseBool(p); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	if f := c.Query("follow"); f != "" {
+		if opts.Follow, err = strconv.ParseBool(f); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	lines := make(chan resource.LogLine)
+	errs := make(chan error, 1)
+
+	go func() {
+		errs <- v.api.Logs(c.Request.Context(), c.Params.ByName("namespace"), opts, lines)
+		close(lines)
+	}()
+
+	started := false
+	enc := json.NewEncoder(c.Writer)
+
+	for line := range lines {
+		if !started {
+			c.Header("Content-Type", "application/x-ndjson")
+			c.Status(http.StatusOK)
+			started = true
+		}
+
+		// once the client went away, the lines are drained until the streams stop with the request context
+		if err := enc.Encode(line); err == nil {
+			c.Writer.Flush()
+		}
+	}
+
+	if err := <-errs; err != nil && !started {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	if !started {
+		c.Header("Content-Type", "application/x-ndjson")
+		c.Status(http.StatusOK)
+	}
+}
@@ -0,0 +1,121 @@
+package http
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/DanielPickens/Keeper/pkg/api"
+	"github.com/DanielPickens/Keeper/pkg/playbook"
+	"github.com/DanielPickens/Keeper/pkg/resource"
+)
+
+const (
+	defaultWaitTimeout     = 5 * time.Minute
+	defaultWaitMaxRestarts = 3
+)
+
+// GetStatus returns the status of the namespace associated to an inventory.
+// The response contains the readiness percentage, the status of each workload, the pod failures and the latest warning events.
+func (v *Handler) GetStatus(c *gin.Context) {
+	status, err := v.api.GetStatus(c.Params.ByName("namespace"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, status)
+}
+
+// Wait waits until the namespace associated to an inventory is ready and returns the wait result.
+// Query parameters are timeout (e.g. 2m), for (e.g. deployment/api,job/migrate), failFast and maxRestarts.
+// It responds 200 when ready, 408 when the timeout is reached and 422 when a terminal failure is detected.
+// The wait stops if the client goes away.
+func (v *Handler) Wait(c *gin.Context) {
+	opts := api.WaitOptions{
+		Timeout:     defaultWaitTimeout,
+		FailFast:    true,
+		MaxRestarts: defaultWaitMaxRestarts,
+	}
+
+	var err error
+
+	if t := c.Query("timeout"); t != "" {
+		if opts.Timeout, err = time.ParseDuration(t); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	if f := c.Query("failFast"); f != "" {
+		if opts.FailFast, err = strconv.ParseBool(f); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	if m := c.Query("maxRestarts"); m != "" {
+		restarts, err := strconv.ParseInt(m, 10, 32)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		opts.MaxRestarts = int32(restarts)
+	}
+
+	if w := c.Query("for"); w != "" {
+		if opts.Workloads, err = api.ParseWorkloadRefs(strings.Split(w, ",")); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	result, err := v.api.WaitForNamespaceReady(c.Request.Context(), c.Params.ByName("namespace"), opts, nil)
+	if err != nil {
+		switch err.(type) {
+		case api.ErrorWaitTimeout:
+			c.JSON(http.StatusRequestTimeout, result)
+		case api.ErrorNamespaceFailed:
+			c.JSON(http.StatusUnprocessableEntity, result)
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// Diff returns what applying the inventory of the namespace would change : for each object of the configs,
+// whether it would be created, configured or left unchanged, and the unified diff with the live object.
+// The objects that would be pruned are included, unless the prune query parameter is false.
+// It responds 422 with the results when some objects would fail to apply.
+func (v *Handler) Diff(c *gin.Context) {
+	opts := api.ApplyOptions{Prune: true}
+
+	if p := c.Query("prune"); p != "" {
+		var err error
+		if opts.Prune, err = strconv.ParseBool(p); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	results, err := v.api.Diff(c.Params.ByName("namespace"), opts)
+	if err != nil {
+		switch err.(type) {
+		case playbook.ErrorInventoryNotFound:
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		case resource.ErrorApplyFailed:
+			c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error(), "results": results})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, results)
+}
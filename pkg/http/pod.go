@@ -0,0 +1,19 @@
+package http
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ListPods returns the pods of the namespace associated to an inventory, with their containers, their restarts,
+// their node and the workload owning them.
+func (v *Handler) ListPods(c *gin.Context) {
+	pods, err := v.api.Pods().List(c.Params.ByName("namespace"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, pods)
+}
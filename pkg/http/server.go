@@ -2,6 +2,7 @@ package http
 
 import (
 	"fmt"
+	"net/http"
 
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
@@ -14,6 +15,7 @@ import (
 // It use a router to map uri to HandlerFunc
 type Handler struct {
 	api        api.Api
+	clusters   *api.Clusters
 	configPath string
 
 	engine *gin.Engine
@@ -22,9 +24,12 @@ type Handler struct {
 // NewHandler creates a Handler using defined routes.
 // It takes a client parameter as an argument in order to pass to the handler and be accessible to the HandlerFunc
 // Typically in a CRUD API, the client manages it's own connections to a storage system.
-func NewHandler(api api.Api, configPath string, corsEnable bool) *Handler {
+// The routes of a namespace are handled by the api of the cluster targeted by its inventory, the other ones by the
+// api of the current context.
+func NewHandler(api api.Api, clusters *api.Clusters, configPath string, corsEnable bool) *Handler {
 	v := &Handler{
 		api:        api,
+		clusters:   clusters,
 		configPath: configPath,
 	}
 
@@ -42,21 +47,43 @@ func NewHandler(api api.Api, configPath string, corsEnable bool) *Handler {
 	v.engine.GET("/ready", v.HealthCheck)
 	v.engine.GET("/alive", v.HealthCheck)
 	v.engine.POST("/inventories", v.Create)
-	v.engine.GET("/inventories/:namespace", v.Get)
-	v.engine.GET("/inventories/:namespace/status", v.GetStatus)
-	v.engine.POST("/inventories/:namespace/reset", v.Reset)
-	v.engine.GET("/inventories/:namespace/services", v.ListServices)
+	v.engine.GET("/inventories/:namespace", v.namespaced((*Handler).Get))
+	v.engine.GET("/inventories/:namespace/status", v.namespaced((*Handler).GetStatus))
+	v.engine.GET("/inventories/:namespace/wait", v.namespaced((*Handler).Wait))
+	v.engine.GET("/inventories/:namespace/diff", v.namespaced((*Handler).Diff))
+	v.engine.GET("/inventories/:namespace/logs", v.namespaced((*Handler).Logs))
+	v.engine.GET("/inventories/:namespace/events", v.namespaced((*Handler).ListEvents))
+	v.engine.GET("/inventories/:namespace/pods", v.namespaced((*Handler).ListPods))
+	v.engine.POST("/inventories/:namespace/reset", v.namespaced((*Handler).Reset))
+	v.engine.GET("/inventories/:namespace/services", v.namespaced((*Handler).ListServices))
 	v.engine.GET("/inventories", v.List)
 	//v.engine.GET("/inventories/status", v.GetStatuses)
 	v.engine.GET("/defaults", v.GetDefaults)
-	v.engine.PUT("/inventories/:namespace", v.Update)
-	v.engine.DELETE("/inventories/:namespace", v.Delete)
-	v.engine.DELETE("/resources/:namespace/jobs/:resource", v.DeleteResource)
+	v.engine.PUT("/inventories/:namespace", v.namespaced((*Handler).Update))
+	v.engine.DELETE("/inventories/:namespace", v.namespaced((*Handler).Delete))
+	v.engine.DELETE("/resources/:namespace/jobs/:resource", v.namespaced((*Handler).DeleteResource))
+	v.engine.POST("/resources/:namespace/jobs/:resource/rerun", v.namespaced((*Handler).RerunJob))
 	v.engine.GET("/version", v.Version)
 
 	return v
 }
 
+// namespaced returns a HandlerFunc handling the request with the api of the cluster targeted by the inventory of
+// the namespace of the route, as the namespace may live on another cluster than the current context.
+func (v *Handler) namespaced(handle func(*Handler, *gin.Context)) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		a, err := v.clusters.ForNamespace(c.Params.ByName("namespace"))
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		h := *v
+		h.api = a
+		handle(&h, c)
+	}
+}
+
 // Engine returns the defined router for the Handler
 func (v *Handler) Engine() *gin.Engine { return v.engine }
 
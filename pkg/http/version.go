@@ -0,0 +1,19 @@
+package http
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Version returns the versions of keeper, of its kubernetes client and of the kubernetes api server,
+// and the api group versions served by the api server, such as "batch/v1" or "networking.k8s.io/v1".
+func (v *Handler) Version(c *gin.Context) {
+	version, err := v.api.GetVersion()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, version)
+}
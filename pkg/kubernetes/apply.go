@@ -0,0 +1,450 @@
+package kubernetes
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/pmezard/go-difflib/difflib"
+	kerr "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	k8syaml "k8s.io/apimachinery/pkg/util/yaml"
+	"k8s.io/client-go/dynamic"
+	"sigs.k8s.io/yaml"
+
+	"github.com/DanielPickens/Keeper/pkg/resource"
+)
+
+const (
+	fieldManager = "keeper"
+
+	// labels set on every applied object, used to find the objects to prune. The revision of the release is recorded
+	// in the history of the namespace rather than on the objects, which would otherwise all change on every apply.
+	labelManagedBy = "app.kubernetes.io/managed-by"
+	labelNamespace = "keeper.io/namespace"
+)
+
+// readObjects parses the objects of the configs found in a directory. Files are read in lexical order,
+// each one may contain several YAML or JSON documents, and lists are flattened.
+func readObjects(dir string) ([]*unstructured.Unstructured, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "*"))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(files)
+
+	var objects []*unstructured.Unstructured
+
+	for _, f := range files {
+		ext := filepath.Ext(f)
+		if ext != ".yml" && ext != ".yaml" && ext != ".json" {
+			continue
+		}
+
+		content, err := ioutil.ReadFile(f)
+		if err != nil {
+			return nil, fmt.Errorf("unable to read config %s: %v", f, err)
+		}
+
+		decoder := k8syaml.NewYAMLOrJSONDecoder(bytes.NewReader(content), 4096)
+		for {
+			obj := &unstructured.Unstructured{}
+			err := decoder.Decode(&obj.Object)
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				return nil, fmt.Errorf("unable to parse config %s: %v", f, err)
+			}
+			if len(obj.Object) == 0 {
+				continue
+			}
+
+			if !obj.IsList() {
+				objects = append(objects, obj)
+				continue
+			}
+
+			err = obj.EachListItem(func(item runtime.Object) error {
+				objects = append(objects, item.(*unstructured.Unstructured))
+				return nil
+			})
+			if err != nil {
+				return nil, fmt.Errorf("unable to parse config %s: %v", f, err)
+			}
+		}
+	}
+
+	return objects, nil
+}
+
+// applyPhases gives the phase in which the kinds of objects are applied : namespaced infrastructure first,
+// then custom resource definitions, then custom resources, then workloads and the objects exposing them.
+var applyPhases = map[string]int{
+	"Namespace":                0,
+	"ResourceQuota":            0,
+	"LimitRange":               0,
+	"ServiceAccount":           0,
+	"Secret":                   0,
+	"ConfigMap":                0,
+	"PersistentVolumeClaim":    0,
+	"Role":                     0,
+	"RoleBinding":              0,
+	"NetworkPolicy":            0,
+	"CustomResourceDefinition": 1,
+}
+
+const (
+	// phaseCustomResources is the phase of the kinds that are not built in kubernetes
+	phaseCustomResources = 2
+	// phaseWorkloads is the phase of the other built-in kinds, such as deployments, services or ingresses
+	phaseWorkloads = 3
+)
+
+// applyPhase returns the phase in which an object is applied
+func applyPhase(obj *unstructured.Unstructured) int {
+	if phase, ok := applyPhases[obj.GetKind()]; ok {
+		return phase
+	}
+
+	// built-in groups are either unqualified, such as apps or batch, or end with k8s.io
+	group := obj.GroupVersionKind().Group
+	if strings.Contains(group, ".") && !strings.HasSuffix(group, ".k8s.io") {
+		return phaseCustomResources
+	}
+
+	return phaseWorkloads
+}
+
+// sortByPhase sorts the objects by apply phase, keeping the order of the configs within a phase
+func sortByPhase(objects []*unstructured.Unstructured) {
+	sort.SliceStable(objects, func(i, j int) bool {
+		return applyPhase(objects[i]) < applyPhase(objects[j])
+	})
+}
+
+// applier applies objects to a namespace using server-side apply, and runs their hook jobs.
+// With dryRun, nothing is persisted and each result holds the unified diff between the live and the applied object.
+type applier struct {
+	dynamic   dynamic.Interface
+	mapper    meta.RESTMapper
+	jobs      resource.JobRepository
+	namespace string
+	dryRun    bool
+}
+
+// applyConfig runs the pre-apply hooks, applies the objects ordered by phase, runs the post-apply hooks and,
+// if requested, prunes the objects previously applied that are not part of the objects anymore.
+// Each step is only performed if the previous ones succeeded. It returns the result of each hook, apply and pruning,
+// and an ErrorApplyFailed if one of them failed.
+func (a applier) applyConfig(objects []*unstructured.Unstructured, opts resource.ApplyOptions) ([]resource.ApplyResult, error) {
+	hooks, others, err := splitHooks(objects)
+	if err != nil {
+		return nil, err
+	}
+	sortByPhase(others)
+
+	results := a.runHooks(context.Background(), hooks[resource.HookPreApply], resource.HookPreApply)
+
+	if !hasFailed(results) {
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		results = append(results, a.apply(ctx, others)...)
+		cancel()
+	}
+
+	if !hasFailed(results) {
+		results = append(results, a.runHooks(context.Background(), hooks[resource.HookPostApply], resource.HookPostApply)...)
+	}
+
+	if opts.Prune && !hasFailed(results) {
+		// hook jobs are part of the configs even though they are not applied like the other objects
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		results = append(results, a.prune(ctx, objects, opts.PruneKinds)...)
+		cancel()
+	}
+
+	if hasFailed(results) {
+		return results, resource.ErrorApplyFailed{Namespace: a.namespace, Results: results}
+	}
+
+	return results, nil
+}
+
+// apply applies the objects and returns the result of each apply.
+// Namespaced objects without namespace are applied to the namespace of the applier.
+func (a applier) apply(ctx context.Context, objects []*unstructured.Unstructured) []resource.ApplyResult {
+	results := make([]resource.ApplyResult, 0, len(objects))
+
+	for _, obj := range objects {
+		results = append(results, a.applyObject(ctx, obj))
+	}
+
+	return results
+}
+
+// applyObject applies an object and tells whether it has been created, configured or left unchanged.
+// The object is labeled with the namespace it is applied to, so that it can be pruned later.
+func (a applier) applyObject(ctx context.Context, obj *unstructured.Unstructured) resource.ApplyResult {
+	result := resource.ApplyResult{
+		Kind:   obj.GetKind(),
+		Name:   obj.GetName(),
+		Action: resource.ApplyFailed,
+	}
+
+	resources, err := a.resourceInterface(obj)
+	result.Namespace = obj.GetNamespace()
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+
+	live, err := resources.Get(ctx, obj.GetName(), metav1.GetOptions{})
+	if kerr.IsNotFound(err) {
+		live, err = nil, nil
+	}
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+
+	a.label(obj)
+
+	data, err := obj.MarshalJSON()
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+
+	force := true
+	opts := metav1.PatchOptions{
+		FieldManager: fieldManager,
+		Force:        &force,
+	}
+	if a.dryRun {
+		opts.DryRun = []string{metav1.DryRunAll}
+	}
+
+	applied, err := resources.Patch(ctx, obj.GetName(), types.ApplyPatchType, data, opts)
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+
+	switch {
+	case live == nil:
+		result.Action = resource.ApplyCreated
+	case sameObject(live, applied):
+		result.Action = resource.ApplyUnchanged
+	default:
+		result.Action = resource.ApplyConfigured
+	}
+
+	if a.dryRun && result.Action != resource.ApplyUnchanged {
+		if result.Diff, err = diffObjects(live, applied); err != nil {
+			result.Action = resource.ApplyFailed
+			result.Error = err.Error()
+		}
+	}
+
+	return result
+}
+
+// label labels an object as managed by keeper in the namespace of the applier
+func (a applier) label(obj *unstructured.Unstructured) {
+	labels := obj.GetLabels()
+	if labels == nil {
+		labels = make(map[string]string)
+	}
+	labels[labelManagedBy] = fieldManager
+	labels[labelNamespace] = a.namespace
+	obj.SetLabels(labels)
+}
+
+// prune deletes the objects of the given kinds labeled with the namespace of the applier that are not part of the
+// applied objects. Kinds unknown by the cluster and cluster-scoped kinds are ignored.
+func (a applier) prune(ctx context.Context, applied []*unstructured.Unstructured, kinds []resource.PruneKind) []resource.ApplyResult {
+	keep := make(map[schema.GroupKind]map[string]bool)
+	for _, obj := range applied {
+		gk := obj.GroupVersionKind().GroupKind()
+		if keep[gk] == nil {
+			keep[gk] = make(map[string]bool)
+		}
+		keep[gk][obj.GetName()] = true
+	}
+
+	var results []resource.ApplyResult
+	pruned := make(map[schema.GroupKind]bool)
+
+	for _, k := range kinds {
+		gk := schema.GroupKind{Group: k.Group, Kind: k.Kind}
+		if pruned[gk] {
+			continue
+		}
+		pruned[gk] = true
+
+		mapping, err := a.mapper.RESTMapping(gk, k.Version)
+		if meta.IsNoMatchError(err) {
+			continue
+		}
+		if err != nil {
+			results = append(results, resource.ApplyResult{Kind: k.Kind, Action: resource.ApplyFailed, Error: err.Error()})
+			continue
+		}
+		if mapping.Scope.Name() != meta.RESTScopeNameNamespace {
+			continue
+		}
+
+		resources := a.dynamic.Resource(mapping.Resource).Namespace(a.namespace)
+
+		list, err := resources.List(ctx, metav1.ListOptions{LabelSelector: fmt.Sprintf("%s=%s", labelNamespace, a.namespace)})
+		if err != nil {
+			results = append(results, resource.ApplyResult{Kind: k.Kind, Action: resource.ApplyFailed, Error: err.Error()})
+			continue
+		}
+
+		for i := range list.Items {
+			obj := &list.Items[i]
+			if keep[gk][obj.GetName()] {
+				continue
+			}
+
+			results = append(results, a.pruneObject(ctx, resources, obj))
+		}
+	}
+
+	return results
+}
+
+// pruneObject deletes an object that is not part of the configs anymore
+func (a applier) pruneObject(ctx context.Context, resources dynamic.ResourceInterface, obj *unstructured.Unstructured) resource.ApplyResult {
+	result := resource.ApplyResult{
+		Kind:      obj.GetKind(),
+		Name:      obj.GetName(),
+		Namespace: obj.GetNamespace(),
+		Action:    resource.ApplyPruned,
+	}
+
+	var err error
+	if a.dryRun {
+		result.Diff, err = diffObjects(obj, nil)
+	} else {
+		propagation := metav1.DeletePropagationBackground
+		err = resources.Delete(ctx, obj.GetName(), metav1.DeleteOptions{PropagationPolicy: &propagation})
+		if kerr.IsNotFound(err) {
+			err = nil
+		}
+	}
+
+	if err != nil {
+		result.Action = resource.ApplyFailed
+		result.Error = err.Error()
+	}
+
+	return result
+}
+
+// resourceInterface returns the dynamic client of the resource of an object.
+// The namespace of namespaced objects is set to the namespace of the applier if it is empty.
+func (a applier) resourceInterface(obj *unstructured.Unstructured) (dynamic.ResourceInterface, error) {
+	gvk := obj.GroupVersionKind()
+
+	mapping, err := a.mapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+	if meta.IsNoMatchError(err) {
+		// the kind may be a custom resource whose definition has just been applied
+		if m, ok := a.mapper.(meta.ResettableRESTMapper); ok {
+			m.Reset()
+			mapping, err = a.mapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+		}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("unable to find the resource of kind %s: %v", gvk, err)
+	}
+
+	if mapping.Scope.Name() != meta.RESTScopeNameNamespace {
+		return a.dynamic.Resource(mapping.Resource), nil
+	}
+
+	if obj.GetNamespace() == "" {
+		obj.SetNamespace(a.namespace)
+	}
+	if obj.GetNamespace() != a.namespace {
+		return nil, fmt.Errorf("the object belongs to namespace %s instead of %s", obj.GetNamespace(), a.namespace)
+	}
+
+	return a.dynamic.Resource(mapping.Resource).Namespace(a.namespace), nil
+}
+
+// hasFailed returns true if the apply or the pruning of an object failed
+func hasFailed(results []resource.ApplyResult) bool {
+	for _, r := range results {
+		if r.Action == resource.ApplyFailed {
+			return true
+		}
+	}
+	return false
+}
+
+// sameObject returns true if two versions of an object only differ by their server-side metadata
+func sameObject(a, b *unstructured.Unstructured) bool {
+	return reflect.DeepEqual(withoutServerMetadata(a).Object, withoutServerMetadata(b).Object)
+}
+
+// diffObjects returns the unified diff between the YAML of the live and the applied versions of an object.
+// The live object is nil when the object does not exist yet, and the applied one is nil when the object is pruned.
+func diffObjects(live, applied *unstructured.Unstructured) (string, error) {
+	obj := applied
+	if obj == nil {
+		obj = live
+	}
+	name := fmt.Sprintf("%s/%s", strings.ToLower(obj.GetKind()), obj.GetName())
+
+	from, err := objectYAML(live)
+	if err != nil {
+		return "", err
+	}
+
+	to, err := objectYAML(applied)
+	if err != nil {
+		return "", err
+	}
+
+	return difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        difflib.SplitLines(string(from)),
+		B:        difflib.SplitLines(string(to)),
+		FromFile: "live/" + name,
+		ToFile:   "applied/" + name,
+		Context:  3,
+	})
+}
+
+// objectYAML returns the YAML of an object without its server-side metadata, nothing if the object is nil
+func objectYAML(obj *unstructured.Unstructured) ([]byte, error) {
+	if obj == nil {
+		return nil, nil
+	}
+	return yaml.Marshal(withoutServerMetadata(obj).Object)
+}
+
+// withoutServerMetadata returns a copy of an object without the metadata set by the api server
+func withoutServerMetadata(obj *unstructured.Unstructured) *unstructured.Unstructured {
+	obj = obj.DeepCopy()
+
+	obj.SetResourceVersion("")
+	obj.SetManagedFields(nil)
+	obj.SetGeneration(0)
+	obj.SetUID("")
+	obj.SetCreationTimestamp(metav1.Time{})
+
+	return obj
+}
@@ -0,0 +1,361 @@
+package kubernetes_test
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	batchv1 "k8s.io/api/batch/v1"
+	"k8s.io/api/core/v1"
+	kerr "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta/testrestmapper"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/kubernetes/scheme"
+	k8stesting "k8s.io/client-go/testing"
+
+	"github.com/DanielPickens/Keeper/pkg/kubernetes"
+	"github.com/DanielPickens/Keeper/pkg/resource"
+)
+
+const applyConfigs = `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: settings
+data:
+  level: debug
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: api
+spec:
+  replicas: 2
+`
+
+// newApplyDynamicClient returns a fake dynamic client handling server-side apply by replacing the whole object,
+// as the fake object tracker is unable to apply unstructured objects.
+func newApplyDynamicClient() *dynamicfake.FakeDynamicClient {
+	dynamic := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(
+		runtime.NewScheme(),
+		map[schema.GroupVersionResource]string{
+			{Version: "v1", Resource: "configmaps"}:                 "ConfigMapList",
+			{Group: "apps", Version: "v1", Resource: "deployments"}: "DeploymentList",
+		},
+	)
+
+	dynamic.PrependReactor("patch", "*", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		patch := action.(k8stesting.PatchAction)
+		if patch.GetPatchType() != types.ApplyPatchType {
+			return false, nil, nil
+		}
+
+		obj := &unstructured.Unstructured{}
+		if err := json.Unmarshal(patch.GetPatch(), &obj.Object); err != nil {
+			return true, nil, err
+		}
+
+		tracker := dynamic.Tracker()
+		_, err := tracker.Get(patch.GetResource(), patch.GetNamespace(), patch.GetName())
+		switch {
+		case kerr.IsNotFound(err):
+			err = tracker.Create(patch.GetResource(), obj, patch.GetNamespace())
+		case err == nil:
+			err = tracker.Update(patch.GetResource(), obj, patch.GetNamespace())
+		}
+		if err != nil {
+			return true, nil, err
+		}
+
+		applied, err := tracker.Get(patch.GetResource(), patch.GetNamespace(), patch.GetName())
+		return true, applied, err
+	})
+
+	return dynamic
+}
+
+func TestApplyConfig(t *testing.T) {
+	dir, err := ioutil.TempDir("", "keeper")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	configs := filepath.Join(dir, "test")
+	assert.NoError(t, os.Mkdir(configs, 0755))
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(configs, "app.yml"), []byte(applyConfigs), 0644))
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(configs, "README.md"), []byte("not a config"), 0644))
+
+	dynamic := newApplyDynamicClient()
+	repository := kubernetes.NewNamespaceRepository(fake.NewSimpleClientset(), dynamic, testrestmapper.TestOnlyStaticRESTMapper(scheme.Scheme))
+
+	results, err := repository.ApplyConfig("test", dir, resource.ApplyOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, []resource.ApplyResult{
+		{Kind: "ConfigMap", Name: "settings", Namespace: "test", Action: resource.ApplyCreated},
+		{Kind: "Deployment", Name: "api", Namespace: "test", Action: resource.ApplyCreated},
+	}, results)
+
+	changed := strings.Replace(applyConfigs, "level: debug", "level: info", 1) + `---
+apiVersion: example.com/v1
+kind: Unknown
+metadata:
+  name: broken
+`
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(configs, "app.yml"), []byte(changed), 0644))
+
+	results, err = repository.ApplyConfig("test", dir, resource.ApplyOptions{})
+	assert.IsType(t, resource.ErrorApplyFailed{}, err)
+	assert.Len(t, results, 3)
+	assert.Equal(t, resource.ApplyConfigured, results[0].Action)
+	// custom resources are applied before the workloads
+	assert.Equal(t, resource.ApplyFailed, results[1].Action)
+	assert.Equal(t, resource.ApplyUnchanged, results[2].Action)
+	assert.Contains(t, err.Error(), "Unknown broken")
+}
+
+func TestDiffConfig(t *testing.T) {
+	dir, err := ioutil.TempDir("", "keeper")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	configs := filepath.Join(dir, "test")
+	assert.NoError(t, os.Mkdir(configs, 0755))
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(configs, "app.yml"), []byte(applyConfigs), 0644))
+
+	dynamic := newApplyDynamicClient()
+	repository := kubernetes.NewNamespaceRepository(fake.NewSimpleClientset(), dynamic, testrestmapper.TestOnlyStaticRESTMapper(scheme.Scheme))
+
+	_, err = repository.ApplyConfig("test", dir, resource.ApplyOptions{})
+	assert.NoError(t, err)
+
+	// the fake client does not forward the patch options : dry-runs are simulated by not storing the applied objects
+	dynamic.PrependReactor("patch", "*", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		obj := &unstructured.Unstructured{}
+		err := json.Unmarshal(action.(k8stesting.PatchAction).GetPatch(), &obj.Object)
+		return true, obj, err
+	})
+
+	changed := strings.Replace(applyConfigs, "level: debug", "level: info", 1)
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(configs, "app.yml"), []byte(changed), 0644))
+
+	results, err := repository.DiffConfig("test", dir, resource.ApplyOptions{})
+	assert.NoError(t, err)
+	assert.Len(t, results, 2)
+
+	assert.Equal(t, resource.ApplyConfigured, results[0].Action)
+	assert.Contains(t, results[0].Diff, "--- live/configmap/settings\n+++ applied/configmap/settings\n")
+	assert.Contains(t, results[0].Diff, "-  level: debug\n+  level: info\n")
+
+	assert.Equal(t, resource.ApplyUnchanged, results[1].Action)
+	assert.Empty(t, results[1].Diff)
+}
+
+func TestApplyConfigPrune(t *testing.T) {
+	dir, err := ioutil.TempDir("", "keeper")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	configs := filepath.Join(dir, "test")
+	assert.NoError(t, os.Mkdir(configs, 0755))
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(configs, "app.yml"), []byte(applyConfigs), 0644))
+
+	dynamic := newApplyDynamicClient()
+	repository := kubernetes.NewNamespaceRepository(fake.NewSimpleClientset(), dynamic, testrestmapper.TestOnlyStaticRESTMapper(scheme.Scheme))
+	kinds := []resource.PruneKind{{Version: "v1", Kind: "ConfigMap"}, {Group: "apps", Version: "v1", Kind: "Deployment"}}
+	opts := resource.ApplyOptions{Prune: true, PruneKinds: kinds}
+
+	_, err = repository.ApplyConfig("test", dir, opts)
+	assert.NoError(t, err)
+
+	deployments := dynamic.Resource(schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "deployments"}).Namespace("test")
+
+	api, err := deployments.Get(context.Background(), "api", metav1.GetOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, "test", api.GetLabels()["keeper.io/namespace"])
+
+	// a deployment not applied by keeper is never pruned
+	other := &unstructured.Unstructured{}
+	other.SetAPIVersion("apps/v1")
+	other.SetKind("Deployment")
+	other.SetName("other")
+	_, err = deployments.Create(context.Background(), other, metav1.CreateOptions{})
+	assert.NoError(t, err)
+
+	// the deployment is removed from the configs
+	configMapOnly := applyConfigs[:strings.Index(applyConfigs, "---")]
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(configs, "app.yml"), []byte(configMapOnly), 0644))
+
+	results, err := repository.ApplyConfig("test", dir, resource.ApplyOptions{PruneKinds: kinds})
+	assert.NoError(t, err)
+	assert.Len(t, results, 1)
+
+	results, err = repository.DiffConfig("test", dir, opts)
+	assert.NoError(t, err)
+	assert.Len(t, results, 2)
+	assert.Equal(t, resource.ApplyPruned, results[1].Action)
+	assert.Contains(t, results[1].Diff, "-  name: api\n")
+
+	_, err = deployments.Get(context.Background(), "api", metav1.GetOptions{})
+	assert.NoError(t, err, "a dry-run should not prune anything")
+
+	results, err = repository.ApplyConfig("test", dir, opts)
+	assert.NoError(t, err)
+	assert.Equal(t, []resource.ApplyResult{
+		{Kind: "ConfigMap", Name: "settings", Namespace: "test", Action: resource.ApplyUnchanged},
+		{Kind: "Deployment", Name: "api", Namespace: "test", Action: resource.ApplyPruned},
+	}, results)
+
+	_, err = deployments.Get(context.Background(), "api", metav1.GetOptions{})
+	assert.True(t, kerr.IsNotFound(err))
+
+	_, err = deployments.Get(context.Background(), "other", metav1.GetOptions{})
+	assert.NoError(t, err)
+}
+
+const hookConfigs = `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: api
+---
+apiVersion: batch/v1
+kind: Job
+metadata:
+  name: migrate
+  annotations:
+    keeper.io/hook: pre-apply
+spec:
+  template:
+    spec:
+      restartPolicy: Never
+      containers:
+      - name: migrate
+        image: busybox
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: settings
+`
+
+// newHookClientset returns a fake clientset where the created jobs are at once finished with the given condition
+func newHookClientset(condition batchv1.JobConditionType, created *[]string) *fake.Clientset {
+	client := fake.NewSimpleClientset()
+	client.PrependReactor("create", "jobs", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		job := action.(k8stesting.CreateAction).GetObject().(*batchv1.Job)
+		job.Status.Conditions = []batchv1.JobCondition{{Type: condition, Status: v1.ConditionTrue, Reason: "BackoffLimitExceeded"}}
+		*created = append(*created, job.Name)
+		return false, nil, nil
+	})
+	return client
+}
+
+func TestApplyConfigHooks(t *testing.T) {
+	dir, err := ioutil.TempDir("", "keeper")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	configs := filepath.Join(dir, "test")
+	assert.NoError(t, os.Mkdir(configs, 0755))
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(configs, "app.yml"), []byte(hookConfigs), 0644))
+
+	var jobs []string
+	client := newHookClientset(batchv1.JobComplete, &jobs)
+	repository := kubernetes.NewNamespaceRepository(client, newApplyDynamicClient(), testrestmapper.TestOnlyStaticRESTMapper(scheme.Scheme))
+
+	results, err := repository.ApplyConfig("test", dir, resource.ApplyOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, []resource.ApplyResult{
+		{Kind: "Job", Name: "migrate", Namespace: "test", Action: resource.ApplyCreated, Hook: resource.HookPreApply},
+		{Kind: "ConfigMap", Name: "settings", Namespace: "test", Action: resource.ApplyCreated},
+		{Kind: "Deployment", Name: "api", Namespace: "test", Action: resource.ApplyCreated},
+	}, results)
+	assert.Equal(t, []string{"migrate"}, jobs)
+
+	job, err := client.BatchV1().Jobs("test").Get(context.Background(), "migrate", metav1.GetOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, "test", job.Labels["keeper.io/namespace"])
+
+	// pre-delete hooks are left out of the apply
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(configs, "app.yml"), []byte(strings.Replace(hookConfigs, "pre-apply", "pre-delete", 1)), 0644))
+	jobs = nil
+
+	results, err = repository.ApplyConfig("test", dir, resource.ApplyOptions{})
+	assert.NoError(t, err)
+	assert.Len(t, results, 2)
+	assert.Empty(t, jobs)
+
+	results, err = repository.RunHooks("test", dir, resource.HookPreDelete)
+	assert.NoError(t, err)
+	assert.Len(t, results, 1)
+	assert.Equal(t, []string{"migrate"}, jobs)
+}
+
+func TestApplyConfigFailedHook(t *testing.T) {
+	dir, err := ioutil.TempDir("", "keeper")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	configs := filepath.Join(dir, "test")
+	assert.NoError(t, os.Mkdir(configs, 0755))
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(configs, "app.yml"), []byte(hookConfigs), 0644))
+
+	var jobs []string
+	dynamic := newApplyDynamicClient()
+	repository := kubernetes.NewNamespaceRepository(newHookClientset(batchv1.JobFailed, &jobs), dynamic, testrestmapper.TestOnlyStaticRESTMapper(scheme.Scheme))
+
+	results, err := repository.ApplyConfig("test", dir, resource.ApplyOptions{Prune: true, PruneKinds: []resource.PruneKind{{Version: "v1", Kind: "ConfigMap"}}})
+	assert.IsType(t, resource.ErrorApplyFailed{}, err)
+	assert.Contains(t, err.Error(), "Job migrate (pre-apply hook): the job failed: BackoffLimitExceeded")
+	assert.Len(t, results, 1)
+
+	// nothing is applied once a pre-apply hook failed
+	_, err = dynamic.Resource(schema.GroupVersionResource{Version: "v1", Resource: "configmaps"}).Namespace("test").Get(context.Background(), "settings", metav1.GetOptions{})
+	assert.True(t, kerr.IsNotFound(err))
+
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(configs, "app.yml"), []byte(strings.Replace(hookConfigs, "pre-apply", "sometimes", 1)), 0644))
+
+	_, err = repository.ApplyConfig("test", dir, resource.ApplyOptions{})
+	assert.EqualError(t, err, `unknown hook "sometimes" on Job migrate, expected one of pre-apply, post-apply or pre-delete`)
+}
+
+func TestRerunJob(t *testing.T) {
+	dir, err := ioutil.TempDir("", "keeper")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	configs := filepath.Join(dir, "test")
+	assert.NoError(t, os.Mkdir(configs, 0755))
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(configs, "app.yml"), []byte(hookConfigs), 0644))
+
+	var jobs []string
+	client := newHookClientset(batchv1.JobComplete, &jobs)
+	_, err = client.BatchV1().Jobs("test").Create(context.Background(), &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{Name: "migrate", Namespace: "test", Labels: map[string]string{"run": "first"}},
+	}, metav1.CreateOptions{})
+	assert.NoError(t, err)
+	jobs = nil
+
+	repository := kubernetes.NewNamespaceRepository(client, newApplyDynamicClient(), testrestmapper.TestOnlyStaticRESTMapper(scheme.Scheme))
+
+	name, err := repository.RerunJob(context.Background(), "test", dir, "migrate")
+	assert.NoError(t, err)
+	assert.Equal(t, "migrate", name)
+	assert.Equal(t, []string{"migrate"}, jobs)
+
+	job, err := client.BatchV1().Jobs("test").Get(context.Background(), "migrate", metav1.GetOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, "test", job.Labels["keeper.io/namespace"])
+	assert.Empty(t, job.Labels["run"])
+
+	_, err = repository.RerunJob(context.Background(), "test", dir, "api")
+	assert.Equal(t, resource.ErrorJobNotInConfigs{Namespace: "test", Name: "api"}, err)
+}
@@ -0,0 +1,411 @@
+package kubernetes
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	"k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	k8syaml "k8s.io/apimachinery/pkg/util/yaml"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/yaml"
+
+	"github.com/DanielPickens/Keeper/pkg/resource"
+)
+
+// annotationLastApplied may hold a copy of the data of a secret applied with kubectl
+const annotationLastApplied = "kubectl.kubernetes.io/last-applied-configuration"
+
+type bundleRepository struct {
+	kubernetes kubernetes.Interface
+}
+
+// NewBundleRepository returns a new BundleRepository.
+// The parameter is a go-client kubernetes client.
+func NewBundleRepository(kubernetes kubernetes.Interface) resource.BundleRepository {
+	return &bundleRepository{
+		kubernetes: kubernetes,
+	}
+}
+
+// bundle gathers the files of a support bundle, and the errors met while collecting them
+type bundle struct {
+	files  []resource.BundleFile
+	errors []string
+}
+
+// addObject adds the YAML of an object, without its managed fields
+func (b *bundle) addObject(path string, obj runtime.Object) {
+	obj = obj.DeepCopyObject()
+
+	if gvks, _, err := scheme.Scheme.ObjectKinds(obj); err == nil && len(gvks) > 0 {
+		obj.GetObjectKind().SetGroupVersionKind(gvks[0])
+	}
+	if accessor, err := meta.Accessor(obj); err == nil {
+		accessor.SetManagedFields(nil)
+	}
+
+	content, err := yaml.Marshal(obj)
+	if err != nil {
+		b.addError(fmt.Errorf("unable to write %s: %v", path, err))
+		return
+	}
+
+	b.files = append(b.files, resource.BundleFile{Path: path, Content: content})
+}
+
+// addError records an error met while collecting the bundle
+func (b *bundle) addError(err error) {
+	b.errors = append(b.errors, err.Error())
+}
+
+// Collect returns the namespace, its events, the objects of its workloads with their secrets and their sensitive
+// environment variables redacted, and the description and the last log lines of each container of its pods, including
+// the previous instance of the restarted containers. Only an error getting the namespace is returned : the other ones
+// are written in errors.txt.
+func (br *bundleRepository) Collect(ctx context.Context, namespace string, opts resource.BundleOptions) ([]resource.BundleFile, error) {
+	ns, err := br.kubernetes.CoreV1().Namespaces().Get(ctx, namespace, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("unable to get namespace %s: %v", namespace, err)
+	}
+
+	b := &bundle{}
+	b.addObject("namespace.yaml", ns)
+
+	if events, err := br.kubernetes.CoreV1().Events(namespace).List(ctx, metav1.ListOptions{}); err != nil {
+		b.addError(fmt.Errorf("unable to list events: %v", err))
+	} else {
+		sort.SliceStable(events.Items, func(i, j int) bool {
+			return eventTime(events.Items[i]).Before(eventTime(events.Items[j]))
+		})
+		b.addObject("events.yaml", events)
+	}
+
+	br.collectObjects(ctx, namespace, b)
+	br.collectPods(ctx, namespace, opts, b)
+
+	if len(b.errors) > 0 {
+		b.files = append(b.files, resource.BundleFile{Path: "errors.txt", Content: []byte(strings.Join(b.errors, "\n") + "\n")})
+	}
+
+	return b.files, nil
+}
+
+// eventTime returns the last time an event occurred
+func eventTime(e v1.Event) time.Time {
+	switch {
+	case !e.LastTimestamp.IsZero():
+		return e.LastTimestamp.Time
+	case e.EventTime.Time.IsZero():
+		return e.CreationTimestamp.Time
+	default:
+		return e.EventTime.Time
+	}
+}
+
+// collectObjects adds the YAML of the workloads of the namespace and of the objects they use.
+// The values of the secrets and of the sensitive environment variables of the pod templates are redacted.
+func (br *bundleRepository) collectObjects(ctx context.Context, namespace string, b *bundle) {
+	lists := []struct {
+		kind string
+		list func() (runtime.Object, error)
+	}{
+		{"deployment", func() (runtime.Object, error) {
+			return br.kubernetes.AppsV1().Deployments(namespace).List(ctx, metav1.ListOptions{})
+		}},
+		{"statefulset", func() (runtime.Object, error) {
+			return br.kubernetes.AppsV1().StatefulSets(namespace).List(ctx, metav1.ListOptions{})
+		}},
+		{"daemonset", func() (runtime.Object, error) {
+			return br.kubernetes.AppsV1().DaemonSets(namespace).List(ctx, metav1.ListOptions{})
+		}},
+		{"job", func() (runtime.Object, error) {
+			return br.kubernetes.BatchV1().Jobs(namespace).List(ctx, metav1.ListOptions{})
+		}},
+		{"cronjob", func() (runtime.Object, error) {
+			return br.kubernetes.BatchV1().CronJobs(namespace).List(ctx, metav1.ListOptions{})
+		}},
+		{"service", func() (runtime.Object, error) {
+			return br.kubernetes.CoreV1().Services(namespace).List(ctx, metav1.ListOptions{})
+		}},
+		{"ingress", func() (runtime.Object, error) {
+			return br.kubernetes.NetworkingV1().Ingresses(namespace).List(ctx, metav1.ListOptions{})
+		}},
+		{"persistentvolumeclaim", func() (runtime.Object, error) {
+			return br.kubernetes.CoreV1().PersistentVolumeClaims(namespace).List(ctx, metav1.ListOptions{})
+		}},
+		{"configmap", func() (runtime.Object, error) {
+			return br.kubernetes.CoreV1().ConfigMaps(namespace).List(ctx, metav1.ListOptions{})
+		}},
+		{"secret", func() (runtime.Object, error) {
+			secrets, err := br.kubernetes.CoreV1().Secrets(namespace).List(ctx, metav1.ListOptions{})
+			if err != nil {
+				return nil, err
+			}
+			for i := range secrets.Items {
+				redactSecret(&secrets.Items[i])
+			}
+			return secrets, nil
+		}},
+	}
+
+	for _, l := range lists {
+		list, err := l.list()
+		if err != nil {
+			b.addError(fmt.Errorf("unable to list %ss: %v", l.kind, err))
+			continue
+		}
+
+		items, err := meta.ExtractList(list)
+		if err != nil {
+			b.addError(fmt.Errorf("unable to list %ss: %v", l.kind, err))
+			continue
+		}
+
+		for _, item := range items {
+			accessor, err := meta.Accessor(item)
+			if err != nil {
+				continue
+			}
+			redactWorkload(item)
+			b.addObject(path.Join("objects", l.kind, accessor.GetName()+".yaml"), item)
+		}
+	}
+}
+
+// redactSecret replaces the values of a secret, keeping its keys
+func redactSecret(secret *v1.Secret) {
+	stringData := make(map[string]string, len(secret.Data)+len(secret.StringData))
+	for k := range secret.Data {
+		stringData[k] = resource.Redacted
+	}
+	for k := range secret.StringData {
+		stringData[k] = resource.Redacted
+	}
+
+	secret.Data = nil
+	secret.StringData = stringData
+
+	if _, ok := secret.Annotations[annotationLastApplied]; ok {
+		secret.Annotations[annotationLastApplied] = resource.Redacted
+	}
+}
+
+// redactWorkload redacts the sensitive environment variables of the pod template of a workload.
+// The last applied configuration of the workload, holding a copy of its environment variables, is redacted as well.
+func redactWorkload(obj runtime.Object) {
+	var spec *v1.PodSpec
+	switch o := obj.(type) {
+	case *appsv1.Deployment:
+		spec = &o.Spec.Template.Spec
+	case *appsv1.StatefulSet:
+		spec = &o.Spec.Template.Spec
+	case *appsv1.DaemonSet:
+		spec = &o.Spec.Template.Spec
+	case *batchv1.Job:
+		spec = &o.Spec.Template.Spec
+	case *batchv1.CronJob:
+		spec = &o.Spec.JobTemplate.Spec.Template.Spec
+	case *v1.Pod:
+		spec = &o.Spec
+	default:
+		return
+	}
+
+	if !redactPodSpec(spec) {
+		return
+	}
+
+	if accessor, err := meta.Accessor(obj); err == nil {
+		if annotations := accessor.GetAnnotations(); annotations[annotationLastApplied] != "" {
+			annotations[annotationLastApplied] = resource.Redacted
+			accessor.SetAnnotations(annotations)
+		}
+	}
+}
+
+// redactPodSpec replaces the values of the environment variables of the containers whose names look like credentials.
+// It returns true if a value is redacted.
+func redactPodSpec(spec *v1.PodSpec) bool {
+	redacted := false
+
+	for _, containers := range [][]v1.Container{spec.InitContainers, spec.Containers} {
+		for i := range containers {
+			for j, env := range containers[i].Env {
+				if env.Value != "" && resource.SensitiveKey.MatchString(env.Name) {
+					containers[i].Env[j].Value = resource.Redacted
+					redacted = true
+				}
+			}
+		}
+	}
+
+	return redacted
+}
+
+// collectPods adds the description of each pod of the namespace, and the last log lines of each of its containers
+func (br *bundleRepository) collectPods(ctx context.Context, namespace string, opts resource.BundleOptions, b *bundle) {
+	pods, err := br.kubernetes.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		b.addError(fmt.Errorf("unable to list pods: %v", err))
+		return
+	}
+
+	for _, pod := range pods.Items {
+		dir := path.Join("pods", pod.Name)
+		redactWorkload(&pod)
+		b.addObject(path.Join(dir, "pod.yaml"), &pod)
+
+		statuses := make([]v1.ContainerStatus, 0, len(pod.Status.InitContainerStatuses)+len(pod.Status.ContainerStatuses))
+		statuses = append(statuses, pod.Status.InitContainerStatuses...)
+		statuses = append(statuses, pod.Status.ContainerStatuses...)
+
+		for _, cs := range statuses {
+			if cs.State.Running != nil || cs.State.Terminated != nil {
+				br.collectLogs(ctx, namespace, pod.Name, cs.Name, false, opts, path.Join(dir, cs.Name+".log"), b)
+			}
+			if cs.LastTerminationState.Terminated != nil {
+				br.collectLogs(ctx, namespace, pod.Name, cs.Name, true, opts, path.Join(dir, cs.Name+".previous.log"), b)
+			}
+		}
+	}
+}
+
+// collectLogs adds the last log lines of a container
+func (br *bundleRepository) collectLogs(ctx context.Context, namespace, pod, container string, previous bool, opts resource.BundleOptions, file string, b *bundle) {
+	logOpts := &v1.PodLogOptions{
+		Container:  container,
+		Previous:   previous,
+		Timestamps: true,
+	}
+	if opts.TailLines > 0 {
+		logOpts.TailLines = &opts.TailLines
+	}
+
+	logs, err := br.kubernetes.CoreV1().Pods(namespace).GetLogs(pod, logOpts).DoRaw(ctx)
+	if err != nil {
+		b.addError(fmt.Errorf("unable to get logs of container %s of pod %s: %v", container, pod, err))
+		return
+	}
+
+	b.files = append(b.files, resource.BundleFile{Path: file, Content: logs})
+}
+
+// RedactConfig parses the YAML or JSON documents of a config and returns them as YAML documents,
+// the values of the secrets and of the sensitive environment variables of the containers being redacted,
+// including the ones of the objects of lists such as a SecretList.
+func (br *bundleRepository) RedactConfig(content []byte) ([]byte, error) {
+	var docs [][]byte
+
+	decoder := k8syaml.NewYAMLOrJSONDecoder(bytes.NewReader(content), 4096)
+	for {
+		obj := &unstructured.Unstructured{}
+		err := decoder.Decode(&obj.Object)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("unable to parse config: %v", err)
+		}
+		if len(obj.Object) == 0 {
+			continue
+		}
+
+		if obj.IsList() {
+			err = obj.EachListItem(func(item runtime.Object) error {
+				redactUnstructured(item.(*unstructured.Unstructured))
+				return nil
+			})
+			if err != nil {
+				return nil, fmt.Errorf("unable to parse config: %v", err)
+			}
+		} else {
+			redactUnstructured(obj)
+		}
+
+		doc, err := yaml.Marshal(obj.Object)
+		if err != nil {
+			return nil, fmt.Errorf("unable to write config: %v", err)
+		}
+		docs = append(docs, doc)
+	}
+
+	return bytes.Join(docs, []byte("---\n")), nil
+}
+
+// podSpecPaths are the paths of the pod specs of the objects running containers, by kind
+var podSpecPaths = map[string][]string{
+	"Pod":         {"spec"},
+	"Deployment":  {"spec", "template", "spec"},
+	"StatefulSet": {"spec", "template", "spec"},
+	"DaemonSet":   {"spec", "template", "spec"},
+	"ReplicaSet":  {"spec", "template", "spec"},
+	"Job":         {"spec", "template", "spec"},
+	"CronJob":     {"spec", "jobTemplate", "spec", "template", "spec"},
+}
+
+// redactUnstructured redacts the values of a secret, or the sensitive environment variables of the containers of an object
+func redactUnstructured(obj *unstructured.Unstructured) {
+	if obj.GetKind() == "Secret" {
+		redactUnstructuredSecret(obj)
+		return
+	}
+
+	fields, ok := podSpecPaths[obj.GetKind()]
+	if !ok {
+		return
+	}
+
+	spec, ok, _ := unstructured.NestedMap(obj.Object, fields...)
+	if !ok {
+		return
+	}
+
+	for _, field := range []string{"initContainers", "containers"} {
+		containers, _ := spec[field].([]interface{})
+		for _, c := range containers {
+			container, _ := c.(map[string]interface{})
+			env, _ := container["env"].([]interface{})
+			for _, e := range env {
+				variable, _ := e.(map[string]interface{})
+				name, _ := variable["name"].(string)
+				if _, ok := variable["value"]; ok && resource.SensitiveKey.MatchString(name) {
+					variable["value"] = resource.Redacted
+				}
+			}
+		}
+	}
+
+	// the nested map is a copy of the spec
+	unstructured.SetNestedMap(obj.Object, spec, fields...)
+}
+
+// redactUnstructuredSecret replaces the values of a secret, keeping its keys
+func redactUnstructuredSecret(obj *unstructured.Unstructured) {
+	for _, field := range []string{"data", "stringData"} {
+		values, ok := obj.Object[field].(map[string]interface{})
+		if !ok {
+			continue
+		}
+		for k := range values {
+			values[k] = resource.Redacted
+		}
+	}
+
+	if annotations := obj.GetAnnotations(); annotations[annotationLastApplied] != "" {
+		annotations[annotationLastApplied] = resource.Redacted
+		obj.SetAnnotations(annotations)
+	}
+}
@@ -0,0 +1,121 @@
+package kubernetes_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	"k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/DanielPickens/Keeper/pkg/kubernetes"
+	"github.com/DanielPickens/Keeper/pkg/resource"
+)
+
+func TestCollectBundle(t *testing.T) {
+	client := fake.NewSimpleClientset(
+		&v1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "test"}},
+		&appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "api", Namespace: "test"}},
+		&v1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        "db",
+				Namespace:   "test",
+				Annotations: map[string]string{"kubectl.kubernetes.io/last-applied-configuration": `{"data":{"password":"czNjcjN0"}}`},
+			},
+			Data: map[string][]byte{"password": []byte("s3cr3t")},
+		},
+		&v1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: "api-1", Namespace: "test"},
+			Status: v1.PodStatus{
+				ContainerStatuses: []v1.ContainerStatus{{
+					Name:                 "api",
+					State:                v1.ContainerState{Running: &v1.ContainerStateRunning{}},
+					LastTerminationState: v1.ContainerState{Terminated: &v1.ContainerStateTerminated{Reason: "Error"}},
+				}},
+			},
+		},
+	)
+
+	files, err := kubernetes.NewBundleRepository(client).Collect(context.Background(), "test", resource.BundleOptions{TailLines: 10})
+	assert.NoError(t, err)
+
+	contents := make(map[string]string)
+	for _, f := range files {
+		contents[f.Path] = string(f.Content)
+	}
+
+	assert.Contains(t, contents, "namespace.yaml")
+	assert.Contains(t, contents, "events.yaml")
+	assert.Contains(t, contents["objects/deployment/api.yaml"], "kind: Deployment")
+	assert.Contains(t, contents["pods/api-1/pod.yaml"], "kind: Pod")
+	assert.Equal(t, "fake logs", contents["pods/api-1/api.log"])
+	assert.Equal(t, "fake logs", contents["pods/api-1/api.previous.log"])
+
+	secret := contents["objects/secret/db.yaml"]
+	assert.Contains(t, secret, "password: REDACTED")
+	assert.NotContains(t, secret, "czNjcjN0")
+
+	_, err = kubernetes.NewBundleRepository(client).Collect(context.Background(), "missing", resource.BundleOptions{})
+	assert.Error(t, err)
+}
+
+func TestRedactConfig(t *testing.T) {
+	config := `apiVersion: v1
+kind: Secret
+metadata:
+  name: db
+stringData:
+  password: s3cr3t
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: settings
+data:
+  level: debug
+`
+
+	redacted, err := kubernetes.NewBundleRepository(fake.NewSimpleClientset()).RedactConfig([]byte(config))
+	assert.NoError(t, err)
+	assert.Contains(t, string(redacted), "password: REDACTED")
+	assert.NotContains(t, string(redacted), "s3cr3t")
+	assert.Contains(t, string(redacted), "level: debug")
+
+	_, err = kubernetes.NewBundleRepository(fake.NewSimpleClientset()).RedactConfig([]byte("kind: [Secret"))
+	assert.Error(t, err)
+}
+
+func TestRedactConfigList(t *testing.T) {
+	config := `apiVersion: v1
+kind: List
+items:
+- apiVersion: v1
+  kind: Secret
+  metadata:
+    name: db
+  data:
+    password: czNjcjN0
+- apiVersion: v1
+  kind: Secret
+  metadata:
+    name: api
+  stringData:
+    token: s3cr3t
+- apiVersion: v1
+  kind: ConfigMap
+  metadata:
+    name: settings
+  data:
+    level: debug
+`
+
+	redacted, err := kubernetes.NewBundleRepository(fake.NewSimpleClientset()).RedactConfig([]byte(config))
+	assert.NoError(t, err)
+	assert.Contains(t, string(redacted), "password: REDACTED")
+	assert.Contains(t, string(redacted), "token: REDACTED")
+	assert.NotContains(t, string(redacted), "czNjcjN0")
+	assert.NotContains(t, string(redacted), "s3cr3t")
+	assert.Contains(t, string(redacted), "level: debug")
+}
@@ -5,14 +5,19 @@ import (
 	"net/url"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 
 	"github.com/sirupsen/logrus"
+	"k8s.io/client-go/discovery/cached/memory"
+	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes"
 	_ "k8s.io/client-go/plugin/pkg/client/auth/oidc"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/restmapper"
 	"k8s.io/client-go/tools/clientcmd"
 
-	"github.com/Danielpickens/Keeper/pkg/resource"
+	"github.com/DanielPickens/Keeper/pkg/resource"
 )
 
 const (
@@ -29,12 +34,21 @@ type Client struct {
 	services     resource.ServiceRepository
 	cluster      resource.ClusterRepository
 	jobs         resource.JobRepository
+	daemonsets   resource.DaemonsetRepository
+	pvcs         resource.PersistentVolumeClaimRepository
+	ingresses    resource.IngressRepository
+	cronjobs     resource.CronJobRepository
+	customs      resource.CustomResourceRepository
+	bundles      resource.BundleRepository
+	events       resource.EventRepository
+	usages       resource.UsageRepository
 }
 
-// NewClient return a new kubernetes client
-func NewClient(configFilePath string) (*Client, error) {
+// NewClient return a new kubernetes client for the given context of the kubeconfig file.
+// The current context of the kubeconfig file is used when the context is empty.
+func NewClient(configFilePath string, context string) (*Client, error) {
 
-	config, err := clientcmd.BuildConfigFromFlags("", configFilePath)
+	config, err := buildConfig(configFilePath, context)
 	if err != nil {
 		return &Client{}, fmt.Errorf("kubernetes client build config : %s", err.Error())
 	}
@@ -47,15 +61,30 @@ func NewClient(configFilePath string) (*Client, error) {
 		return &Client{}, fmt.Errorf("kubernetes new client for config : %s", err.Error())
 	}
 
+	dynamicClient, err := dynamic.NewForConfig(config)
+	if err != nil {
+		return &Client{}, fmt.Errorf("kubernetes new dynamic client for config : %s", err.Error())
+	}
+
+	mapper := restmapper.NewDeferredDiscoveryRESTMapper(memory.NewMemCacheClient(clientSet.Discovery()))
+
 	return &Client{
 		kubernetes:   clientSet,
-		namespaces:   NewNamespaceRepository(clientSet),
+		namespaces:   NewNamespaceRepository(clientSet, dynamicClient, mapper),
 		pods:         NewPodRepository(clientSet),
 		deployments:  NewDeploymentRepository(clientSet),
 		statefulsets: NewStatefulsetRepository(clientSet),
-		services:     NewServiceRepository(clientSet, GetKubernetesHost(configFilePath)),
-		cluster:      NewClusterRepository(),
+		services:     NewServiceRepository(clientSet, dynamicClient, kubernetesHost(config), config),
+		cluster:      NewClusterRepository(clientSet),
 		jobs:         NewJobRepository(clientSet),
+		daemonsets:   NewDaemonsetRepository(clientSet),
+		pvcs:         NewPersistentVolumeClaimRepository(clientSet),
+		ingresses:    NewIngressRepository(clientSet),
+		cronjobs:     NewCronJobRepository(clientSet),
+		customs:      NewCustomResourceRepository(dynamicClient, mapper),
+		bundles:      NewBundleRepository(clientSet),
+		events:       NewEventRepository(clientSet),
+		usages:       NewUsageRepository(clientSet, dynamicClient),
 	}, nil
 }
 
@@ -87,6 +116,38 @@ func (c *Client) Statefulsets() resource.StatefulsetRepository {
 	return c.statefulsets
 }
 
+func (c *Client) Daemonsets() resource.DaemonsetRepository {
+	return c.daemonsets
+}
+
+func (c *Client) PersistentVolumeClaims() resource.PersistentVolumeClaimRepository {
+	return c.pvcs
+}
+
+func (c *Client) Ingresses() resource.IngressRepository {
+	return c.ingresses
+}
+
+func (c *Client) CronJobs() resource.CronJobRepository {
+	return c.cronjobs
+}
+
+func (c *Client) CustomResources() resource.CustomResourceRepository {
+	return c.customs
+}
+
+func (c *Client) Bundles() resource.BundleRepository {
+	return c.bundles
+}
+
+func (c *Client) Events() resource.EventRepository {
+	return c.events
+}
+
+func (c *Client) Usages() resource.UsageRepository {
+	return c.usages
+}
+
 // KubeConfigDefaultPath return the kubernetes default config path
 func KubeConfigDefaultPath() string {
 	return filepath.Join(homeDir(), configDir, configFile)
@@ -99,13 +160,48 @@ func homeDir() string {
 	return os.Getenv("USERPROFILE") // windows
 }
 
-// GetKubernetesHost return the kubernetes cluster domain name used in the ~/.kube/config file
+// buildConfig returns the configuration of a client for the given context of the kubeconfig file
+func buildConfig(configFilePath string, context string) (*rest.Config, error) {
+	if context == "" {
+		return clientcmd.BuildConfigFromFlags("", configFilePath)
+	}
+
+	return clientcmd.NewNonInteractiveDeferredLoadingClientConfig(
+		&clientcmd.ClientConfigLoadingRules{ExplicitPath: configFilePath},
+		&clientcmd.ConfigOverrides{CurrentContext: context},
+	).ClientConfig()
+}
+
+// Contexts returns the names of the contexts defined in the kubeconfig file, sorted, and the current one
+func Contexts(configFilePath string) ([]string, string, error) {
+	config, err := clientcmd.LoadFromFile(configFilePath)
+	if err != nil {
+		return nil, "", fmt.Errorf("unable to load kubeconfig %s: %v", configFilePath, err)
+	}
+
+	contexts := make([]string, 0, len(config.Contexts))
+	for name := range config.Contexts {
+		contexts = append(contexts, name)
+	}
+	sort.Strings(contexts)
+
+	return contexts, config.CurrentContext, nil
+}
+
+// GetKubernetesHost return the kubernetes cluster domain name used in the ~/.kube/config file for the given context
 // The returned host takes the form : mydomainname.com
 // Notice : this is just the host, without any schema or port.
-func GetKubernetesHost(configFilePath string) string {
+func GetKubernetesHost(configFilePath string, context string) string {
 
-	config, _ := clientcmd.BuildConfigFromFlags("", configFilePath)
+	config, err := buildConfig(configFilePath, context)
+	if err != nil {
+		logrus.Fatalf("Impossible to get K8s host : %s", err.Error())
+	}
+
+	return kubernetesHost(config)
+}
 
+func kubernetesHost(config *rest.Config) string {
 	u, err := url.Parse(config.Host)
 	if err != nil {
 		logrus.Fatalf("Impossible to get K8s host : %s", err.Error())
@@ -1,33 +1,90 @@
 package kubernetes
 
 import (
-	"encoding/json"
-	"os/exec"
+	"fmt"
+	"runtime/debug"
+	"sort"
+	"strings"
+
+	"k8s.io/client-go/kubernetes"
 
 	"github.com/DanielPickens/Keeper/pkg/resource"
 )
 
-type ClusterRepository struct{}
+const clientGoModule = "k8s.io/client-go"
 
-func NewClusterRepository() resource.ClusterRepository {
-	return &ClusterRepository{}
+type ClusterRepository struct {
+	kubernetes kubernetes.Interface
 }
 
+// NewClusterRepository returns a new ClusterRepository.
+// The parameter is a go-client Kubernetes client
+func NewClusterRepository(kubernetes kubernetes.Interface) resource.ClusterRepository {
+	return &ClusterRepository{
+		kubernetes: kubernetes,
+	}
+}
+
+// GetVersion returns the version of the api server and the group versions it serves, using the discovery api,
+// and the version of the kubernetes client compiled in keeper
 func (r ClusterRepository) GetVersion() (*resource.Version, error) {
-	cmd := exec.Command("/bin/sh", "-c", "kubectl version --output json")
-	result, err := cmd.Output()
+	server, err := r.kubernetes.Discovery().ServerVersion()
+	if err != nil {
+		return nil, fmt.Errorf("unable to get the server version: %v", err)
+	}
 
+	groups, err := r.kubernetes.Discovery().ServerGroups()
 	if err != nil {
-		return nil, err
+		return nil, fmt.Errorf("unable to list the api groups: %v", err)
 	}
 
-	var v resource.Version
+	v := &resource.Version{
+		ClientVersion: clientVersion(),
+		ServerVersion: resource.VersionInfo{
+			Major:      server.Major,
+			Minor:      strings.TrimSuffix(server.Minor, "+"),
+			GitVersion: server.GitVersion,
+		},
+		APIGroups: []string{},
+	}
 
-	err = json.Unmarshal(result, &v)
+	for _, g := range groups.Groups {
+		for _, gv := range g.Versions {
+			v.APIGroups = append(v.APIGroups, gv.GroupVersion)
+		}
+	}
+	sort.Strings(v.APIGroups)
 
-	if err != nil {
-		return nil, err
+	return v, nil
+}
+
+// clientVersion returns the kubernetes version matching the client-go module keeper is built with.
+// client-go v0.x.y is the client of kubernetes 1.x.y.
+func clientVersion() resource.VersionInfo {
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return resource.VersionInfo{}
+	}
+
+	for _, dep := range info.Deps {
+		if dep.Path != clientGoModule {
+			continue
+		}
+
+		if dep.Replace != nil {
+			dep = dep.Replace
+		}
+
+		v := resource.VersionInfo{GitVersion: dep.Version}
+
+		parts := strings.SplitN(strings.TrimPrefix(dep.Version, "v"), ".", 3)
+		if len(parts) == 3 && parts[0] == "0" {
+			v.Major = "1"
+			v.Minor = parts[1]
+		}
+
+		return v
 	}
 
-	return &v, nil
+	return resource.VersionInfo{}
 }
@@ -0,0 +1,36 @@
+package kubernetes_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/version"
+	fakediscovery "k8s.io/client-go/discovery/fake"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/DanielPickens/Keeper/pkg/kubernetes"
+)
+
+func TestGetVersion(t *testing.T) {
+	client := fake.NewSimpleClientset()
+	client.Resources = []*metav1.APIResourceList{
+		{GroupVersion: "v1"},
+		{GroupVersion: "networking.k8s.io/v1"},
+		{GroupVersion: "batch/v1"},
+		{GroupVersion: "batch/v1beta1"},
+	}
+	client.Discovery().(*fakediscovery.FakeDiscovery).FakedServerVersion = &version.Info{
+		Major:      "1",
+		Minor:      "19+",
+		GitVersion: "v1.19.8-eks-96780e",
+	}
+
+	v, err := kubernetes.NewClusterRepository(client).GetVersion()
+
+	assert.NoError(t, err)
+	assert.Equal(t, "1", v.ServerVersion.Major)
+	assert.Equal(t, "19", v.ServerVersion.Minor)
+	assert.Equal(t, "v1.19.8-eks-96780e", v.ServerVersion.GitVersion)
+	assert.Equal(t, []string{"batch/v1", "batch/v1beta1", "networking.k8s.io/v1", "v1"}, v.APIGroups)
+}
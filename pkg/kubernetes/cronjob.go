@@ -0,0 +1,126 @@
+package kubernetes
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	batchv1 "k8s.io/api/batch/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/DanielPickens/Keeper/pkg/resource"
+)
+
+// annotationInstantiate is the annotation telling how a job of a cronjob was created, set to manual for manual runs
+const annotationInstantiate = "cronjob.kubernetes.io/instantiate"
+
+type cronJobRepository struct {
+	kubernetes kubernetes.Interface
+}
+
+// NewCronJobRepository returns a new CronJobRepository.
+// The parameter is a go-client kubernetes client.
+func NewCronJobRepository(kubernetes kubernetes.Interface) resource.CronJobRepository {
+	return &cronJobRepository{
+		kubernetes: kubernetes,
+	}
+}
+
+// List returns the cronjobs of the given namespace.
+// A cronjob is ready when it has never been scheduled, when a run is active or when its last scheduled run succeeded.
+func (c *cronJobRepository) List(namespace string) (resource.CronJobs, error) {
+	cl, err := c.kubernetes.BatchV1().CronJobs(namespace).List(context.Background(), metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("unable to list cronjobs: %v", err)
+	}
+
+	cronjobs := make(resource.CronJobs, 0)
+
+	for _, cj := range cl.Items {
+		cronjob := resource.CronJob{
+			Name:      cj.Name,
+			Status:    resource.CronJobReady,
+			Schedule:  cj.Spec.Schedule,
+			Suspended: cj.Spec.Suspend != nil && *cj.Spec.Suspend,
+			Active:    len(cj.Status.Active),
+		}
+
+		if t := cj.Status.LastScheduleTime; t != nil {
+			cronjob.LastScheduleTime = &t.Time
+		}
+
+		if t := cj.Status.LastSuccessfulTime; t != nil {
+			cronjob.LastSuccessfulTime = &t.Time
+		}
+
+		if cronjob.LastScheduleTime != nil && cronjob.Active == 0 &&
+			(cronjob.LastSuccessfulTime == nil || cronjob.LastSuccessfulTime.Before(*cronjob.LastScheduleTime)) {
+			cronjob.Status = resource.CronJobNotReady
+		}
+
+		cronjobs = append(cronjobs, cronjob)
+	}
+
+	return cronjobs, nil
+}
+
+// Suspend sets whether the scheduling of a cronjob is suspended. The runs already started are not stopped.
+func (c *cronJobRepository) Suspend(namespace, name string, suspend bool) error {
+	patch := []byte(fmt.Sprintf(`{"spec":{"suspend":%t}}`, suspend))
+
+	_, err := c.kubernetes.BatchV1().CronJobs(namespace).Patch(context.Background(), name, types.MergePatchType, patch, metav1.PatchOptions{})
+	if err != nil {
+		return fmt.Errorf("unable to update cronjob %s: %v", name, err)
+	}
+
+	return nil
+}
+
+// Trigger creates a job from the job template of a cronjob, as the cronjob controller would, such as with
+// kubectl create job --from=cronjob. The job is owned by the cronjob and annotated as a manual run.
+// It returns the name of the created job.
+func (c *cronJobRepository) Trigger(namespace, name string) (string, error) {
+	cj, err := c.kubernetes.BatchV1().CronJobs(namespace).Get(context.Background(), name, metav1.GetOptions{})
+	if err != nil {
+		return "", fmt.Errorf("unable to get cronjob %s: %v", name, err)
+	}
+
+	annotations := map[string]string{annotationInstantiate: "manual"}
+	for k, v := range cj.Spec.JobTemplate.Annotations {
+		annotations[k] = v
+	}
+
+	job := &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            manualJobName(cj.Name, time.Now()),
+			Namespace:       namespace,
+			Labels:          cj.Spec.JobTemplate.Labels,
+			Annotations:     annotations,
+			OwnerReferences: []metav1.OwnerReference{*metav1.NewControllerRef(cj, batchv1.SchemeGroupVersion.WithKind("CronJob"))},
+		},
+		Spec: cj.Spec.JobTemplate.Spec,
+	}
+
+	created, err := c.kubernetes.BatchV1().Jobs(namespace).Create(context.Background(), job, metav1.CreateOptions{})
+	if err != nil {
+		return "", fmt.Errorf("unable to create a job from cronjob %s: %v", name, err)
+	}
+
+	return created.Name, nil
+}
+
+// manualJobName returns the name of a job run manually from a cronjob : the name of the cronjob followed by the time
+// of the run, the name of the cronjob being shortened so that the job name is a valid label value
+func manualJobName(cronjob string, t time.Time) string {
+	suffix := fmt.Sprintf("-manual-%d", t.Unix())
+
+	if max := validation.LabelValueMaxLength - len(suffix); len(cronjob) > max {
+		cronjob = strings.TrimRight(cronjob[:max], "-.")
+	}
+
+	return cronjob + suffix
+}
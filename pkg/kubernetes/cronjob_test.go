@@ -0,0 +1,111 @@
+package kubernetes_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	batchv1 "k8s.io/api/batch/v1"
+	"k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/DanielPickens/Keeper/pkg/kubernetes"
+	"github.com/DanielPickens/Keeper/pkg/resource"
+)
+
+func newCronJob(name string, lastSchedule, lastSuccess *metav1.Time, active int) *batchv1.CronJob {
+	cj := &batchv1.CronJob{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "test"},
+		Spec:       batchv1.CronJobSpec{Schedule: "0 2 * * *"},
+		Status: batchv1.CronJobStatus{
+			LastScheduleTime:   lastSchedule,
+			LastSuccessfulTime: lastSuccess,
+		},
+	}
+
+	for i := 0; i < active; i++ {
+		cj.Status.Active = append(cj.Status.Active, v1.ObjectReference{Name: name})
+	}
+
+	return cj
+}
+
+func TestListCronJobsStatus(t *testing.T) {
+	yesterday := metav1.NewTime(time.Now().Add(-24 * time.Hour))
+	today := metav1.NewTime(time.Now().Add(-1 * time.Hour))
+
+	client := fake.NewSimpleClientset(
+		newCronJob("never-scheduled", nil, nil, 0),
+		newCronJob("succeeded", &today, &today, 0),
+		newCronJob("running", &today, &yesterday, 1),
+		newCronJob("failed", &today, &yesterday, 0),
+		newCronJob("never-succeeded", &today, nil, 0),
+	)
+
+	cronjobs, err := kubernetes.NewCronJobRepository(client).List("test")
+
+	assert.Nil(t, err)
+
+	statuses := make(map[string]resource.CronJobStatus)
+	for _, cj := range cronjobs {
+		statuses[cj.Name] = cj.Status
+	}
+
+	assert.Equal(t, map[string]resource.CronJobStatus{
+		"never-scheduled": resource.CronJobReady,
+		"succeeded":       resource.CronJobReady,
+		"running":         resource.CronJobReady,
+		"failed":          resource.CronJobNotReady,
+		"never-succeeded": resource.CronJobNotReady,
+	}, statuses)
+}
+
+func TestSuspendCronJob(t *testing.T) {
+	client := fake.NewSimpleClientset(newCronJob("refresh", nil, nil, 0))
+	cronjobs := kubernetes.NewCronJobRepository(client)
+
+	assert.NoError(t, cronjobs.Suspend("test", "refresh", true))
+
+	list, err := cronjobs.List("test")
+	assert.NoError(t, err)
+	assert.True(t, list[0].Suspended)
+
+	assert.NoError(t, cronjobs.Suspend("test", "refresh", false))
+
+	list, err = cronjobs.List("test")
+	assert.NoError(t, err)
+	assert.False(t, list[0].Suspended)
+
+	assert.Error(t, cronjobs.Suspend("test", "missing", true))
+}
+
+func TestTriggerCronJob(t *testing.T) {
+	cj := newCronJob(strings.Repeat("refresh", 10), nil, nil, 0)
+	cj.Spec.JobTemplate = batchv1.JobTemplateSpec{
+		ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"app": "refresh"}},
+		Spec: batchv1.JobSpec{Template: v1.PodTemplateSpec{Spec: v1.PodSpec{
+			RestartPolicy: v1.RestartPolicyNever,
+			Containers:    []v1.Container{{Name: "refresh", Image: "busybox"}},
+		}}},
+	}
+	client := fake.NewSimpleClientset(cj)
+
+	name, err := kubernetes.NewCronJobRepository(client).Trigger("test", cj.Name)
+	assert.NoError(t, err)
+	assert.LessOrEqual(t, len(name), 63)
+	assert.Contains(t, name, "-manual-")
+
+	job, err := client.BatchV1().Jobs("test").Get(context.Background(), name, metav1.GetOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, "refresh", job.Labels["app"])
+	assert.Equal(t, "manual", job.Annotations["cronjob.kubernetes.io/instantiate"])
+	assert.Equal(t, cj.Name, job.OwnerReferences[0].Name)
+	assert.Equal(t, "CronJob", job.OwnerReferences[0].Kind)
+	assert.Equal(t, "busybox", job.Spec.Template.Spec.Containers[0].Image)
+
+	_, err = kubernetes.NewCronJobRepository(client).Trigger("test", "missing")
+	assert.Error(t, err)
+}
@@ -0,0 +1,67 @@
+package kubernetes
+
+import (
+	"context"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+
+	"github.com/DanielPickens/Keeper/pkg/resource"
+)
+
+type customResourceRepository struct {
+	dynamic dynamic.Interface
+	mapper  meta.RESTMapper
+}
+
+// NewCustomResourceRepository returns a new CustomResourceRepository.
+// The parameters are a go-client dynamic client and a RESTMapper used to find the resource of a kind.
+func NewCustomResourceRepository(dynamic dynamic.Interface, mapper meta.RESTMapper) resource.CustomResourceRepository {
+	return &customResourceRepository{
+		dynamic: dynamic,
+		mapper:  mapper,
+	}
+}
+
+// List returns the objects of the namespace targeted by the readiness rule, with their readiness.
+// If the kind of the rule is not known by the cluster, an empty list is returned.
+func (c *customResourceRepository) List(namespace string, rule resource.ReadinessRule) (resource.CustomResources, error) {
+	mapping, err := c.mapper.RESTMapping(schema.GroupKind{Group: rule.Group, Kind: rule.Kind}, rule.Version)
+	if err != nil {
+		if meta.IsNoMatchError(err) {
+			return resource.CustomResources{}, nil
+		}
+		return nil, fmt.Errorf("unable to find resource for kind %s: %v", rule.Kind, err)
+	}
+
+	ol, err := c.dynamic.Resource(mapping.Resource).Namespace(namespace).List(context.Background(), metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("unable to list %s: %v", mapping.Resource.Resource, err)
+	}
+
+	crs := make(resource.CustomResources, 0)
+
+	for _, obj := range ol.Items {
+		ready, err := isReady(obj.Object, rule)
+		if err != nil {
+			return nil, fmt.Errorf("unable to evaluate readiness of %s %s: %v", rule.Kind, obj.GetName(), err)
+		}
+
+		status := resource.CustomResourceNotReady
+		if ready {
+			status = resource.CustomResourceReady
+		}
+
+		crs = append(crs, resource.CustomResource{
+			Kind:       rule.Kind,
+			Name:       obj.GetName(),
+			Status:     status,
+			Conditions: objectConditions(obj.Object),
+		})
+	}
+
+	return crs, nil
+}
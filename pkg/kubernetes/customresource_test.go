@@ -0,0 +1,118 @@
+package kubernetes_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+
+	"github.com/DanielPickens/Keeper/pkg/kubernetes"
+	"github.com/DanielPickens/Keeper/pkg/resource"
+)
+
+var (
+	rolloutGVK  = schema.GroupVersionKind{Group: "argoproj.io", Version: "v1alpha1", Kind: "Rollout"}
+	postgresGVK = schema.GroupVersionKind{Group: "acid.zalan.do", Version: "v1", Kind: "postgresql"}
+)
+
+func newCustomResource(gvk schema.GroupVersionKind, name string, spec, status map[string]interface{}) *unstructured.Unstructured {
+	obj := &unstructured.Unstructured{Object: map[string]interface{}{
+		"spec":   spec,
+		"status": status,
+	}}
+	obj.SetGroupVersionKind(gvk)
+	obj.SetNamespace("test")
+	obj.SetName(name)
+
+	return obj
+}
+
+func newCustomResourceRepository() resource.CustomResourceRepository {
+	mapper := meta.NewDefaultRESTMapper(nil)
+	mapper.Add(rolloutGVK, meta.RESTScopeNamespace)
+	mapper.Add(postgresGVK, meta.RESTScopeNamespace)
+
+	client := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(
+		runtime.NewScheme(),
+		map[schema.GroupVersionResource]string{
+			rolloutGVK.GroupVersion().WithResource("rollouts"):     "RolloutList",
+			postgresGVK.GroupVersion().WithResource("postgresqls"): "postgresqlList",
+		},
+		newCustomResource(rolloutGVK, "api", nil, map[string]interface{}{
+			"conditions": []interface{}{
+				map[string]interface{}{"type": "Available", "status": "True"},
+			},
+		}),
+		newCustomResource(rolloutGVK, "front", nil, map[string]interface{}{
+			"conditions": []interface{}{
+				map[string]interface{}{"type": "Available", "status": "False", "reason": "MinimumReplicasUnavailable"},
+			},
+		}),
+		newCustomResource(postgresGVK, "db", map[string]interface{}{"numberOfInstances": int64(2)}, map[string]interface{}{"readyReplicas": int64(1)}),
+	)
+
+	return kubernetes.NewCustomResourceRepository(client, mapper)
+}
+
+func TestListCustomResourcesWithCondition(t *testing.T) {
+	crs, err := newCustomResourceRepository().List("test", resource.ReadinessRule{
+		Group:         "argoproj.io",
+		Version:       "v1alpha1",
+		Kind:          "Rollout",
+		ConditionType: "Available",
+	})
+
+	assert.Nil(t, err)
+
+	statuses := make(map[string]resource.CustomResourceStatus)
+	for _, cr := range crs {
+		statuses[cr.Name] = cr.Status
+	}
+
+	assert.Equal(t, map[string]resource.CustomResourceStatus{
+		"api":   resource.CustomResourceReady,
+		"front": resource.CustomResourceNotReady,
+	}, statuses)
+}
+
+func TestListCustomResourcesWithJSONPath(t *testing.T) {
+	repository := newCustomResourceRepository()
+	rule := resource.ReadinessRule{
+		Group:     "acid.zalan.do",
+		Version:   "v1",
+		Kind:      "postgresql",
+		JSONPath:  "{.status.readyReplicas}",
+		Operator:  ">=",
+		ValuePath: ".spec.numberOfInstances",
+	}
+
+	crs, err := repository.List("test", rule)
+
+	assert.Nil(t, err)
+	assert.Len(t, crs, 1)
+	assert.Equal(t, resource.CustomResourceNotReady, crs[0].Status)
+
+	rule.ValuePath = ""
+	rule.Value = "1"
+
+	crs, err = repository.List("test", rule)
+
+	assert.Nil(t, err)
+	assert.Equal(t, resource.CustomResourceReady, crs[0].Status)
+}
+
+func TestListCustomResourcesUnknownKind(t *testing.T) {
+	crs, err := newCustomResourceRepository().List("test", resource.ReadinessRule{
+		Group:         "kafka.strimzi.io",
+		Version:       "v1beta2",
+		Kind:          "KafkaTopic",
+		ConditionType: "Ready",
+	})
+
+	assert.Nil(t, err)
+	assert.Empty(t, crs)
+}
@@ -0,0 +1,64 @@
+package kubernetes
+
+import (
+	"context"
+	"fmt"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/DanielPickens/Keeper/pkg/resource"
+)
+
+type daemonsetRepository struct {
+	kubernetes kubernetes.Interface
+}
+
+// NewDaemonsetRepository returns a new DaemonsetRepository.
+// The parameter is a go-client kubernetes client.
+func NewDaemonsetRepository(kubernetes kubernetes.Interface) resource.DaemonsetRepository {
+	return &daemonsetRepository{
+		kubernetes: kubernetes,
+	}
+}
+
+// List returns the daemonsets of the given namespace.
+// A daemonset is ready when the daemon pod is ready and up to date on every node it should be scheduled on.
+func (c *daemonsetRepository) List(namespace string) (resource.Daemonsets, error) {
+	dl, err := c.kubernetes.AppsV1().DaemonSets(namespace).List(context.Background(), metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("unable to list daemonsets: %v", err)
+	}
+
+	daemonsets := make(resource.Daemonsets, 0)
+
+	for _, ds := range dl.Items {
+		status := resource.DaemonsetNotReady
+
+		if ds.Status.NumberReady == ds.Status.DesiredNumberScheduled &&
+			ds.Status.UpdatedNumberScheduled == ds.Status.DesiredNumberScheduled {
+			status = resource.DaemonsetReady
+		}
+
+		conditions := make([]resource.Condition, 0, len(ds.Status.Conditions))
+		for _, cond := range ds.Status.Conditions {
+			conditions = append(conditions, resource.Condition{
+				Type:    string(cond.Type),
+				Status:  string(cond.Status),
+				Reason:  cond.Reason,
+				Message: cond.Message,
+			})
+		}
+
+		daemonsets = append(daemonsets, resource.Daemonset{
+			Name:       ds.Name,
+			Status:     status,
+			Ready:      ds.Status.NumberReady,
+			Desired:    ds.Status.DesiredNumberScheduled,
+			Conditions: conditions,
+			Containers: containers(ds.Spec.Template.Spec),
+		})
+	}
+
+	return daemonsets, nil
+}
@@ -0,0 +1,41 @@
+package kubernetes_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/DanielPickens/Keeper/pkg/kubernetes"
+	"github.com/DanielPickens/Keeper/pkg/resource"
+)
+
+func TestListDaemonsetsStatus(t *testing.T) {
+	tests := []struct {
+		name     string
+		status   appsv1.DaemonSetStatus
+		expected resource.DaemonsetStatus
+	}{
+		{"ready", appsv1.DaemonSetStatus{DesiredNumberScheduled: 3, NumberReady: 3, UpdatedNumberScheduled: 3}, resource.DaemonsetReady},
+		{"no-node", appsv1.DaemonSetStatus{}, resource.DaemonsetReady},
+		{"starting", appsv1.DaemonSetStatus{DesiredNumberScheduled: 3, NumberReady: 2, UpdatedNumberScheduled: 3}, resource.DaemonsetNotReady},
+		{"rolling-out", appsv1.DaemonSetStatus{DesiredNumberScheduled: 3, NumberReady: 3, UpdatedNumberScheduled: 1}, resource.DaemonsetNotReady},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := fake.NewSimpleClientset(&appsv1.DaemonSet{
+				ObjectMeta: metav1.ObjectMeta{Name: "agent", Namespace: "test"},
+				Status:     tt.status,
+			})
+
+			daemonsets, err := kubernetes.NewDaemonsetRepository(client).List("test")
+
+			assert.NoError(t, err)
+			assert.Len(t, daemonsets, 1)
+			assert.Equal(t, tt.expected, daemonsets[0].Status)
+		})
+	}
+}
@@ -1,45 +1,71 @@
 package kubernetes
 
 import (
-"fmt"
-"os"
-"time"
-"context"
-"github.com/danielpickens/keeper/pkg/resource"
-"k8s.io/apimachinery/pkg/apis/meta/v1"
-"k8s.io/client-go/kubernetes"
+	"context"
+	"fmt"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/DanielPickens/Keeper/pkg/resource"
 )
 
 type deploymentRepository struct {
-	kubernetes.Interface
+	kubernetes kubernetes.Interface
 }
 
-func newDeploymentRepository(kubernetes kubernetes.Interface) resource.deploymentRepository { 
-	return &deploymentRepository {
-		kubernetes,
+// NewDeploymentRepository returns a new DeploymentRepository.
+// The parameter is a go-client kubernetes client.
+func NewDeploymentRepository(kubernetes kubernetes.Interface) resource.DeploymentRepository {
+	return &deploymentRepository{
+		kubernetes: kubernetes,
 	}
 }
 
-func (x *deploymentRepository) List (namespace string) (resource.Deployments, error) {
-	xl, err := x.AppsV1().Deployments(namespace).List(context.Background()), v1.ListOptions{})
+// List returns the deployments of the given namespace with their number of ready replicas.
+// A deployment is ready once its last change has been observed and all its desired replicas are updated and ready.
+func (c *deploymentRepository) List(namespace string) (resource.Deployments, error) {
+	dl, err := c.kubernetes.AppsV1().Deployments(namespace).List(context.Background(), metav1.ListOptions{})
 	if err != nil {
-		return nil, fmt.Errorf("unable to list deployments: %v, err")
-
+		return nil, fmt.Errorf("unable to list deployments: %v", err)
 	}
-	xps: make(resource.Deployments, 0)
 
-	for _, xp := range xl.Items {
-		status := resource.Deployments
+	deployments := make(resource.Deployments, 0)
+
+	for _, dp := range dl.Items {
+		desired := int32(1)
+		if dp.Spec.Replicas != nil {
+			desired = *dp.Spec.Replicas
+		}
+
+		status := resource.DeploymentNotReady
 
-		if xps.Status.ReadReplicas == xp.Status.Replicas {
+		// a deployment whose last change has not been reconciled yet still runs its previous replicas
+		if dp.Status.ObservedGeneration >= dp.Generation &&
+			dp.Status.UpdatedReplicas == desired &&
+			dp.Status.ReadyReplicas == desired {
 			status = resource.DeploymentReady
 		}
 
-		xps := append(xps, resource.Deployment {
-			Name: xp.Name, 
-			Status: status, 
+		conditions := make([]resource.Condition, 0, len(dp.Status.Conditions))
+		for _, cond := range dp.Status.Conditions {
+			conditions = append(conditions, resource.Condition{
+				Type:    string(cond.Type),
+				Status:  string(cond.Status),
+				Reason:  cond.Reason,
+				Message: cond.Message,
+			})
+		}
+
+		deployments = append(deployments, resource.Deployment{
+			Name:       dp.Name,
+			Status:     status,
+			Ready:      dp.Status.ReadyReplicas,
+			Desired:    desired,
+			Conditions: conditions,
+			Containers: containers(dp.Spec.Template.Spec),
 		})
 	}
-	return xps nil 
-}
 
+	return deployments, nil
+}
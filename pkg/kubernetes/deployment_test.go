@@ -0,0 +1,45 @@
+package kubernetes_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/DanielPickens/Keeper/pkg/kubernetes"
+	"github.com/DanielPickens/Keeper/pkg/resource"
+)
+
+func TestListDeploymentsStatus(t *testing.T) {
+	replicas := int32(2)
+
+	tests := []struct {
+		name     string
+		status   appsv1.DeploymentStatus
+		expected resource.DeploymentStatus
+	}{
+		{"ready", appsv1.DeploymentStatus{ObservedGeneration: 2, Replicas: 2, UpdatedReplicas: 2, ReadyReplicas: 2}, resource.DeploymentReady},
+		{"not-observed", appsv1.DeploymentStatus{ObservedGeneration: 1, Replicas: 2, UpdatedReplicas: 2, ReadyReplicas: 2}, resource.DeploymentNotReady},
+		{"not-reconciled", appsv1.DeploymentStatus{ObservedGeneration: 2}, resource.DeploymentNotReady},
+		{"rolling-out", appsv1.DeploymentStatus{ObservedGeneration: 2, Replicas: 3, UpdatedReplicas: 1, ReadyReplicas: 2}, resource.DeploymentNotReady},
+		{"starting", appsv1.DeploymentStatus{ObservedGeneration: 2, Replicas: 2, UpdatedReplicas: 2, ReadyReplicas: 1}, resource.DeploymentNotReady},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := fake.NewSimpleClientset(&appsv1.Deployment{
+				ObjectMeta: metav1.ObjectMeta{Name: "api", Namespace: "test", Generation: 2},
+				Spec:       appsv1.DeploymentSpec{Replicas: &replicas},
+				Status:     tt.status,
+			})
+
+			deployments, err := kubernetes.NewDeploymentRepository(client).List("test")
+
+			assert.NoError(t, err)
+			assert.Len(t, deployments, 1)
+			assert.Equal(t, tt.expected, deployments[0].Status)
+		})
+	}
+}
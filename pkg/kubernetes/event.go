@@ -0,0 +1,191 @@
+package kubernetes
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/DanielPickens/Keeper/pkg/resource"
+)
+
+type eventRepository struct {
+	kubernetes kubernetes.Interface
+}
+
+// NewEventRepository returns a new EventRepository.
+// The parameter is a go-client kubernetes client.
+func NewEventRepository(kubernetes kubernetes.Interface) resource.EventRepository {
+	return &eventRepository{
+		kubernetes: kubernetes,
+	}
+}
+
+// List returns the events of the given namespace matching the options, sorted by the last time they occurred.
+// The events having the same type, reason, object and message, such as the ones recorded by several instances of
+// a controller, are merged into one event : their counts are added up.
+func (er *eventRepository) List(namespace string, opts resource.EventOptions) (resource.Events, error) {
+	el, err := er.kubernetes.CoreV1().Events(namespace).List(context.Background(), metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("unable to list events: %v", err)
+	}
+
+	events := make(resource.Events, 0, len(el.Items))
+	index := make(map[string]int)
+
+	for _, e := range el.Items {
+		if !matchEvent(e, opts) {
+			continue
+		}
+
+		event := toEvent(e)
+		key := strings.Join([]string{event.Type, event.Reason, event.Kind, event.Name, event.Message}, "/")
+
+		i, ok := index[key]
+		if !ok {
+			index[key] = len(events)
+			events = append(events, event)
+			continue
+		}
+
+		events[i].Count += event.Count
+		if event.FirstSeen.Before(events[i].FirstSeen) {
+			events[i].FirstSeen = event.FirstSeen
+		}
+		if event.LastSeen.After(events[i].LastSeen) {
+			events[i].LastSeen = event.LastSeen
+		}
+	}
+
+	sort.SliceStable(events, func(i, j int) bool { return events[i].LastSeen.Before(events[j].LastSeen) })
+
+	return events, nil
+}
+
+// Watch sends the events of the given namespace matching the options as they are recorded or occur again, until the
+// context is done. The events recorded before the call are not sent. The watch is started again when it is closed
+// by the api server.
+func (er *eventRepository) Watch(ctx context.Context, namespace string, opts resource.EventOptions, events chan<- resource.Event) error {
+	resourceVersion := ""
+
+	for ctx.Err() == nil {
+		if resourceVersion == "" {
+			// the watch starts after the events already recorded
+			el, err := er.kubernetes.CoreV1().Events(namespace).List(ctx, metav1.ListOptions{Limit: 1})
+			if err != nil {
+				if ctx.Err() != nil {
+					return nil
+				}
+				return fmt.Errorf("unable to list events: %v", err)
+			}
+			resourceVersion = el.ResourceVersion
+		}
+
+		watcher, err := er.kubernetes.CoreV1().Events(namespace).Watch(ctx, metav1.ListOptions{ResourceVersion: resourceVersion})
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return fmt.Errorf("unable to watch events: %v", err)
+		}
+
+		resourceVersion, err = sendEvents(ctx, watcher, opts, events, resourceVersion)
+		watcher.Stop()
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// sendEvents sends the events of a watch matching the options until the watch is closed or the context is done.
+// It returns the resource version of the last event received, to watch again from it, or an empty one when it
+// expired.
+func sendEvents(ctx context.Context, watcher watch.Interface, opts resource.EventOptions, events chan<- resource.Event, resourceVersion string) (string, error) {
+	for {
+		select {
+		case <-ctx.Done():
+			return resourceVersion, nil
+		case we, ok := <-watcher.ResultChan():
+			if !ok {
+				return resourceVersion, nil
+			}
+
+			if we.Type == watch.Error {
+				err := apierrors.FromObject(we.Object)
+				if apierrors.IsResourceExpired(err) || apierrors.IsGone(err) {
+					return "", nil
+				}
+				return resourceVersion, fmt.Errorf("unable to watch events: %v", err)
+			}
+
+			e, ok := we.Object.(*v1.Event)
+			if !ok {
+				continue
+			}
+			resourceVersion = e.ResourceVersion
+
+			if (we.Type != watch.Added && we.Type != watch.Modified) || !matchEvent(*e, opts) {
+				continue
+			}
+
+			select {
+			case events <- toEvent(*e):
+			case <-ctx.Done():
+				return resourceVersion, nil
+			}
+		}
+	}
+}
+
+// matchEvent returns true if an event matches the type and the object of the options
+func matchEvent(e v1.Event, opts resource.EventOptions) bool {
+	if opts.Type != "" && !strings.EqualFold(e.Type, opts.Type) {
+		return false
+	}
+	if opts.Kind != "" && !strings.EqualFold(e.InvolvedObject.Kind, opts.Kind) {
+		return false
+	}
+
+	return opts.Name == "" || e.InvolvedObject.Name == opts.Name
+}
+
+// toEvent converts a kubernetes event, whose count comes from its series when it has been recorded by the
+// events.k8s.io api
+func toEvent(e v1.Event) resource.Event {
+	count := e.Count
+	if e.Series != nil && e.Series.Count > count {
+		count = e.Series.Count
+	}
+	if count == 0 {
+		count = 1
+	}
+
+	lastSeen := eventTime(e)
+	if e.Series != nil && e.Series.LastObservedTime.After(lastSeen) {
+		lastSeen = e.Series.LastObservedTime.Time
+	}
+
+	firstSeen := e.FirstTimestamp.Time
+	if firstSeen.IsZero() || firstSeen.After(lastSeen) {
+		firstSeen = lastSeen
+	}
+
+	return resource.Event{
+		Type:      e.Type,
+		Reason:    e.Reason,
+		Kind:      e.InvolvedObject.Kind,
+		Name:      e.InvolvedObject.Name,
+		Message:   strings.TrimSpace(e.Message),
+		Count:     count,
+		FirstSeen: firstSeen,
+		LastSeen:  lastSeen,
+	}
+}
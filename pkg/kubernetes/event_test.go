@@ -0,0 +1,111 @@
+package kubernetes_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+
+	"github.com/DanielPickens/Keeper/pkg/kubernetes"
+	"github.com/DanielPickens/Keeper/pkg/resource"
+)
+
+func newEvent(name, typ, reason, kind, object, message string, count int32, first, last time.Time) *v1.Event {
+	return &v1.Event{
+		ObjectMeta:     metav1.ObjectMeta{Name: name, Namespace: "test"},
+		Type:           typ,
+		Reason:         reason,
+		InvolvedObject: v1.ObjectReference{Kind: kind, Name: object, Namespace: "test"},
+		Message:        message,
+		Count:          count,
+		FirstTimestamp: metav1.NewTime(first),
+		LastTimestamp:  metav1.NewTime(last),
+	}
+}
+
+func TestListEvents(t *testing.T) {
+	t0 := time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)
+
+	client := fake.NewSimpleClientset(
+		newEvent("api.1", "Warning", "BackOff", "Pod", "api-x2k", "Back-off restarting failed container", 3, t0.Add(time.Minute), t0.Add(5*time.Minute)),
+		newEvent("api.2", "Warning", "BackOff", "Pod", "api-x2k", "Back-off restarting failed container", 2, t0, t0.Add(10*time.Minute)),
+		newEvent("api.3", "Normal", "Pulled", "Pod", "api-x2k", "Successfully pulled image", 1, t0, t0),
+		newEvent("db.1", "Warning", "FailedScheduling", "Pod", "db-0", "0/3 nodes are available", 0, time.Time{}, t0.Add(2*time.Minute)),
+		newEvent("deploy.1", "Normal", "ScalingReplicaSet", "Deployment", "api", "Scaled up replica set api-7d9f to 1", 1, t0.Add(time.Minute), t0.Add(time.Minute)),
+	)
+
+	repository := kubernetes.NewEventRepository(client)
+
+	events, err := repository.List("test", resource.EventOptions{})
+
+	assert.Nil(t, err)
+	assert.Equal(t, resource.Events{
+		{Type: "Normal", Reason: "Pulled", Kind: "Pod", Name: "api-x2k", Message: "Successfully pulled image", Count: 1, FirstSeen: t0, LastSeen: t0},
+		{Type: "Normal", Reason: "ScalingReplicaSet", Kind: "Deployment", Name: "api", Message: "Scaled up replica set api-7d9f to 1", Count: 1, FirstSeen: t0.Add(time.Minute), LastSeen: t0.Add(time.Minute)},
+		{Type: "Warning", Reason: "FailedScheduling", Kind: "Pod", Name: "db-0", Message: "0/3 nodes are available", Count: 1, FirstSeen: t0.Add(2 * time.Minute), LastSeen: t0.Add(2 * time.Minute)},
+		{Type: "Warning", Reason: "BackOff", Kind: "Pod", Name: "api-x2k", Message: "Back-off restarting failed container", Count: 5, FirstSeen: t0, LastSeen: t0.Add(10 * time.Minute)},
+	}, utcEvents(events))
+
+	warnings, err := repository.List("test", resource.EventOptions{Type: resource.EventWarning})
+
+	assert.Nil(t, err)
+	assert.Len(t, warnings, 2)
+	assert.Equal(t, "FailedScheduling", warnings[0].Reason)
+	assert.Equal(t, "BackOff", warnings[1].Reason)
+
+	deployment, err := repository.List("test", resource.EventOptions{Kind: "deployment", Name: "api"})
+
+	assert.Nil(t, err)
+	assert.Len(t, deployment, 1)
+	assert.Equal(t, "ScalingReplicaSet", deployment[0].Reason)
+}
+
+func TestWatchEvents(t *testing.T) {
+	client := fake.NewSimpleClientset()
+	watcher := watch.NewFake()
+	client.PrependWatchReactor("events", k8stesting.DefaultWatchReactor(watcher, nil))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	events := make(chan resource.Event)
+	errs := make(chan error, 1)
+
+	go func() {
+		errs <- kubernetes.NewEventRepository(client).Watch(ctx, "test", resource.EventOptions{Type: resource.EventWarning}, events)
+	}()
+
+	now := time.Now()
+	go func() {
+		watcher.Add(newEvent("api.1", "Normal", "Pulled", "Pod", "api-x2k", "Successfully pulled image", 1, now, now))
+		watcher.Add(newEvent("api.2", "Warning", "BackOff", "Pod", "api-x2k", "Back-off restarting failed container", 1, now, now))
+		watcher.Modify(newEvent("api.2", "Warning", "BackOff", "Pod", "api-x2k", "Back-off restarting failed container", 2, now, now))
+	}()
+
+	first := <-events
+	assert.Equal(t, "BackOff", first.Reason)
+	assert.Equal(t, int32(1), first.Count)
+
+	again := <-events
+	assert.Equal(t, "BackOff", again.Reason)
+	assert.Equal(t, int32(2), again.Count)
+
+	cancel()
+	assert.Nil(t, <-errs)
+}
+
+// utcEvents returns the events with their times in UTC, to be compared
+func utcEvents(events resource.Events) resource.Events {
+	for i := range events {
+		events[i].FirstSeen = events[i].FirstSeen.UTC()
+		events[i].LastSeen = events[i].LastSeen.UTC()
+	}
+
+	return events
+}
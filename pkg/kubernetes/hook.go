@@ -0,0 +1,119 @@
+package kubernetes
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/DanielPickens/Keeper/pkg/resource"
+)
+
+const (
+	// annotationHook is the annotation turning a job of the configs into a hook, such as keeper.io/hook: pre-apply
+	annotationHook = "keeper.io/hook"
+
+	// hookTimeout is the maximum time a hook job is waited for
+	hookTimeout = 10 * time.Minute
+)
+
+// splitHooks separates the hook jobs of the configs from the other objects.
+// An error is returned if an object has an unknown hook annotation.
+func splitHooks(objects []*unstructured.Unstructured) (map[resource.Hook][]*unstructured.Unstructured, []*unstructured.Unstructured, error) {
+	hooks := make(map[resource.Hook][]*unstructured.Unstructured)
+	others := make([]*unstructured.Unstructured, 0, len(objects))
+
+	for _, obj := range objects {
+		value, ok := obj.GetAnnotations()[annotationHook]
+		if !ok {
+			others = append(others, obj)
+			continue
+		}
+
+		hook := resource.Hook(value)
+		switch hook {
+		case resource.HookPreApply, resource.HookPostApply, resource.HookPreDelete:
+			hooks[hook] = append(hooks[hook], obj)
+		default:
+			return nil, nil, fmt.Errorf("unknown hook %q on %s %s, expected one of %s, %s or %s", value, obj.GetKind(), obj.GetName(),
+				resource.HookPreApply, resource.HookPostApply, resource.HookPreDelete)
+		}
+	}
+
+	return hooks, others, nil
+}
+
+// runHooks runs the hook jobs one after another, each one being waited for until completion.
+// It stops at the first failed hook. With dryRun, the hooks are only applied as a dry-run and not waited for.
+func (a applier) runHooks(ctx context.Context, hooks []*unstructured.Unstructured, hook resource.Hook) []resource.ApplyResult {
+	results := make([]resource.ApplyResult, 0, len(hooks))
+
+	for _, obj := range hooks {
+		var result resource.ApplyResult
+		if a.dryRun {
+			result = a.applyObject(ctx, obj)
+		} else {
+			result = a.runHook(ctx, obj)
+		}
+		result.Hook = hook
+
+		results = append(results, result)
+		if result.Action == resource.ApplyFailed {
+			break
+		}
+	}
+
+	return results
+}
+
+// runHook runs a hook job, replacing the job of a previous run, and waits until it is completed or failed
+func (a applier) runHook(ctx context.Context, obj *unstructured.Unstructured) resource.ApplyResult {
+	result := resource.ApplyResult{
+		Kind:      obj.GetKind(),
+		Name:      obj.GetName(),
+		Namespace: a.namespace,
+		Action:    resource.ApplyFailed,
+	}
+
+	gvk := obj.GroupVersionKind()
+	if gvk.Group != "batch" || gvk.Kind != "Job" {
+		result.Error = "hooks must be jobs"
+		return result
+	}
+	if obj.GetNamespace() != "" && obj.GetNamespace() != a.namespace {
+		result.Error = fmt.Sprintf("the object belongs to namespace %s instead of %s", obj.GetNamespace(), a.namespace)
+		return result
+	}
+
+	a.label(obj)
+
+	manifest, err := obj.MarshalJSON()
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, hookTimeout)
+	defer cancel()
+
+	name, err := a.jobs.Run(ctx, a.namespace, manifest)
+	if err != nil {
+		result.Error = fmt.Sprintf("unable to run the job: %v", err)
+		return result
+	}
+
+	job, err := a.jobs.Wait(ctx, a.namespace, name)
+	if err != nil {
+		result.Error = fmt.Sprintf("unable to wait for the job: %v", err)
+		return result
+	}
+
+	if job.Status == resource.JobFailed {
+		result.Error = fmt.Sprintf("the job failed: %s", job.Failure())
+		return result
+	}
+
+	result.Action = resource.ApplyCreated
+	return result
+}
@@ -0,0 +1,60 @@
+package kubernetes
+
+import (
+	"context"
+	"fmt"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/DanielPickens/Keeper/pkg/resource"
+)
+
+type ingressRepository struct {
+	kubernetes kubernetes.Interface
+}
+
+// NewIngressRepository returns a new IngressRepository.
+// The parameter is a go-client kubernetes client.
+func NewIngressRepository(kubernetes kubernetes.Interface) resource.IngressRepository {
+	return &ingressRepository{
+		kubernetes: kubernetes,
+	}
+}
+
+// List returns the ingresses of the given namespace.
+// An ingress is ready once the ingress controller assigned it an IP or a hostname.
+func (c *ingressRepository) List(namespace string) (resource.Ingresses, error) {
+	il, err := c.kubernetes.NetworkingV1().Ingresses(namespace).List(context.Background(), metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("unable to list ingresses: %v", err)
+	}
+
+	ingresses := make(resource.Ingresses, 0)
+
+	for _, ing := range il.Items {
+		var addresses []string
+
+		for _, lb := range ing.Status.LoadBalancer.Ingress {
+			if lb.Hostname != "" {
+				addresses = append(addresses, lb.Hostname)
+			} else if lb.IP != "" {
+				addresses = append(addresses, lb.IP)
+			}
+		}
+
+		status := resource.IngressNotReady
+
+		if len(addresses) > 0 {
+			status = resource.IngressReady
+		}
+
+		ingresses = append(ingresses, resource.Ingress{
+			Name:      ing.Name,
+			Status:    status,
+			Addresses: addresses,
+		})
+	}
+
+	return ingresses, nil
+}
@@ -0,0 +1,45 @@
+package kubernetes_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/DanielPickens/Keeper/pkg/kubernetes"
+	"github.com/DanielPickens/Keeper/pkg/resource"
+)
+
+func TestListIngressesStatus(t *testing.T) {
+	tests := []struct {
+		name      string
+		ingresses []networkingv1.IngressLoadBalancerIngress
+		expected  resource.IngressStatus
+		addresses []string
+	}{
+		{"no-address", nil, resource.IngressNotReady, nil},
+		{"ip", []networkingv1.IngressLoadBalancerIngress{{IP: "10.0.0.1"}}, resource.IngressReady, []string{"10.0.0.1"}},
+		{"hostname", []networkingv1.IngressLoadBalancerIngress{{IP: "10.0.0.1", Hostname: "lb.example.com"}}, resource.IngressReady, []string{"lb.example.com"}},
+		{"empty", []networkingv1.IngressLoadBalancerIngress{{}}, resource.IngressNotReady, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := fake.NewSimpleClientset(&networkingv1.Ingress{
+				ObjectMeta: metav1.ObjectMeta{Name: "front", Namespace: "test"},
+				Status: networkingv1.IngressStatus{
+					LoadBalancer: networkingv1.IngressLoadBalancerStatus{Ingress: tt.ingresses},
+				},
+			})
+
+			ingresses, err := kubernetes.NewIngressRepository(client).List("test")
+
+			assert.NoError(t, err)
+			assert.Len(t, ingresses, 1)
+			assert.Equal(t, tt.expected, ingresses[0].Status)
+			assert.Equal(t, tt.addresses, ingresses[0].Addresses)
+		})
+	}
+}
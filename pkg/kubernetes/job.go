@@ -3,13 +3,26 @@ package kubernetes
 import (
 	"context"
 	"fmt"
+	"io"
+	"sort"
+	"time"
 
 	"k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
 
-	"github.com/danielpickens/keeper/pkg/resource"
+	"github.com/DanielPickens/Keeper/pkg/resource"
 
+	kerr "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/watch"
 	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/scheme"
+)
+
+const (
+	// jobPollDuration is the period at which the pods of a job are looked for while streaming its logs
+	jobPollDuration = time.Second
 )
 
 type jobRepository struct {
@@ -24,6 +37,7 @@ func NewJobRepository(kubernetes kubernetes.Interface) resource.JobRepository {
 	}
 }
 
+// List returns the jobs of the given namespace : completed, failed, or not ready while they are running.
 func (c *jobRepository) List(namespace string) (resource.Jobs, error) {
 	jl, err := c.kubernetes.BatchV1().Jobs(namespace).List(context.Background(), metav1.ListOptions{})
 
@@ -31,32 +45,228 @@ func (c *jobRepository) List(namespace string) (resource.Jobs, error) {
 		return nil, fmt.Errorf("unable to list jobs: %v", err)
 	}
 
-	jobs := make(resource.Jobs, 0)
+	jobs := make(resource.Jobs, 0, len(jl.Items))
 
+	// a running job has no conditions yet : it is not ready
 	for _, job := range jl.Items {
-		status := resource.JobNotReady
+		jobs = append(jobs, newJob(job))
+	}
+
+	return jobs, nil
+}
+
+// newJob converts a kubernetes job into a Job
+func newJob(job v1.Job) resource.Job {
+	status := resource.JobNotReady
 
-		if len(job.Status.Conditions) == 0 {
+	for _, cond := range job.Status.Conditions {
+		if cond.Status != corev1.ConditionTrue {
 			continue
 		}
 
-		if job.Status.Conditions[len(job.Status.Conditions)-1].Type == v1.JobComplete {
-			status = resource.JobReady
+		switch cond.Type {
+		case v1.JobComplete:
+			if status != resource.JobFailed {
+				status = resource.JobReady
+			}
+		case v1.JobFailed:
+			// a job is failed once it reached its backoff limit or its active deadline
+			status = resource.JobFailed
 		}
+	}
+
+	desired := int32(1)
+	if job.Spec.Completions != nil {
+		desired = *job.Spec.Completions
+	}
 
-		jobs = append(jobs, resource.Job{
-			Name:   job.Name,
-			Status: status,
+	conditions := make([]resource.Condition, 0, len(job.Status.Conditions))
+	for _, cond := range job.Status.Conditions {
+		conditions = append(conditions, resource.Condition{
+			Type:    string(cond.Type),
+			Status:  string(cond.Status),
+			Reason:  cond.Reason,
+			Message: cond.Message,
 		})
 	}
 
-	return jobs, nil
+	return resource.Job{
+		Name:       job.Name,
+		Status:     status,
+		Ready:      job.Status.Succeeded,
+		Desired:    desired,
+		Conditions: conditions,
+	}
 }
 
-func (c *jobRepository) Delete(namespace, resourceName string) error {
+// Delete deletes the job, its pods being deleted in the background
+func (c *jobRepository) Delete(ctx context.Context, namespace, resourceName string) error {
 	pp := metav1.DeletePropagationBackground
-	if err := c.kubernetes.BatchV1().Jobs(namespace).Delete(context.Background(), resourceName, metav1.DeleteOptions{PropagationPolicy: &pp}); err != nil {
+	if err := c.kubernetes.BatchV1().Jobs(namespace).Delete(ctx, resourceName, metav1.DeleteOptions{PropagationPolicy: &pp}); err != nil {
 		return err
 	}
 	return nil
 }
+
+// Run creates the job described by a yaml or json manifest in the namespace.
+// As jobs are immutable, a previous job of the same name is deleted first.
+func (c *jobRepository) Run(ctx context.Context, namespace string, manifest []byte) (string, error) {
+	obj, _, err := scheme.Codecs.UniversalDeserializer().Decode(manifest, nil, nil)
+	if err != nil {
+		return "", fmt.Errorf("unable to read job manifest: %v", err)
+	}
+
+	job, ok := obj.(*v1.Job)
+	if !ok {
+		return "", fmt.Errorf("unable to run a %s: only jobs can be run", obj.GetObjectKind().GroupVersionKind().Kind)
+	}
+
+	job.Namespace = namespace
+
+	if job.Name != "" {
+		if err := c.Delete(ctx, namespace, job.Name); err != nil && !kerr.IsNotFound(err) {
+			return "", fmt.Errorf("unable to delete previous job %s: %v", job.Name, err)
+		}
+	}
+
+	for {
+		created, err := c.kubernetes.BatchV1().Jobs(namespace).Create(ctx, job, metav1.CreateOptions{})
+		if err == nil {
+			return created.Name, nil
+		}
+
+		if !kerr.IsAlreadyExists(err) {
+			return "", fmt.Errorf("unable to create job %s: %v", job.Name, err)
+		}
+
+		// the previous job is still being deleted
+		select {
+		case <-ctx.Done():
+			return "", ctx.Err()
+		case <-time.After(jobPollDuration):
+		}
+	}
+}
+
+// Wait watches the given job until it is completed or failed
+func (c *jobRepository) Wait(ctx context.Context, namespace, name string) (*resource.Job, error) {
+	jobs := c.kubernetes.BatchV1().Jobs(namespace)
+
+	for {
+		job, err := jobs.Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("unable to get job %s: %v", name, err)
+		}
+
+		if j := newJob(*job); j.Status != resource.JobNotReady {
+			return &j, nil
+		}
+
+		watcher, err := jobs.Watch(ctx, metav1.ListOptions{
+			FieldSelector:   fields.OneTermEqualSelector("metadata.name", name).String(),
+			ResourceVersion: job.ResourceVersion,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("unable to watch job %s: %v", name, err)
+		}
+
+		j, err := waitJob(ctx, watcher.ResultChan(), name)
+		watcher.Stop()
+
+		if err != nil || j != nil {
+			return j, err
+		}
+		// the watch has been closed by the api server, start over
+	}
+}
+
+// waitJob reads job events until the given job is finished, the events channel is closed or the context is done
+func waitJob(ctx context.Context, events <-chan watch.Event, name string) (*resource.Job, error) {
+	for {
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case e, ok := <-events:
+			if !ok {
+				return nil, nil
+			}
+
+			job, ok := e.Object.(*v1.Job)
+			if !ok || job.Name != name {
+				continue
+			}
+
+			if j := newJob(*job); j.Status != resource.JobNotReady {
+				return &j, nil
+			}
+		}
+	}
+}
+
+// Logs streams the logs of the containers of each pod of the given job, one pod after another,
+// until the job is finished and the logs of all its started pods have been streamed.
+func (c *jobRepository) Logs(ctx context.Context, namespace, name string, w io.Writer) error {
+	streamed := make(map[string]bool)
+
+	for {
+		job, err := c.kubernetes.BatchV1().Jobs(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return fmt.Errorf("unable to get job %s: %v", name, err)
+		}
+
+		// the job status is read before the pods : once it is finished, the last listed pods are the last ones
+		finished := newJob(*job).Status != resource.JobNotReady
+
+		pods, err := c.kubernetes.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{LabelSelector: "job-name=" + name})
+		if err != nil {
+			return fmt.Errorf("unable to list pods of job %s: %v", name, err)
+		}
+
+		sort.Slice(pods.Items, func(i, j int) bool {
+			return pods.Items[i].CreationTimestamp.Before(&pods.Items[j].CreationTimestamp)
+		})
+
+		for _, pod := range pods.Items {
+			// pods of a previous job of the same name may still be terminating
+			if streamed[pod.Name] || pod.Status.Phase == corev1.PodPending || !metav1.IsControlledBy(&pod, job) {
+				continue
+			}
+
+			streamed[pod.Name] = true
+
+			for _, container := range pod.Spec.Containers {
+				if err := c.streamLogs(ctx, namespace, pod.Name, container.Name, w); err != nil {
+					return err
+				}
+			}
+		}
+
+		if finished {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(jobPollDuration):
+		}
+	}
+}
+
+// streamLogs copies the logs of a container to w until the container is terminated
+func (c *jobRepository) streamLogs(ctx context.Context, namespace, pod, container string, w io.Writer) error {
+	stream, err := c.kubernetes.CoreV1().Pods(namespace).GetLogs(pod, &corev1.PodLogOptions{
+		Container: container,
+		Follow:    true,
+	}).Stream(ctx)
+	if err != nil {
+		return fmt.Errorf("unable to get logs of pod %s: %v", pod, err)
+	}
+	defer stream.Close()
+
+	if _, err := io.Copy(w, stream); err != nil {
+		return fmt.Errorf("unable to read logs of pod %s: %v", pod, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,133 @@
+package kubernetes_test
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	batchv1 "k8s.io/api/batch/v1"
+	"k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/DanielPickens/Keeper/pkg/kubernetes"
+	"github.com/DanielPickens/Keeper/pkg/resource"
+)
+
+const testJobManifest = `
+apiVersion: batch/v1
+kind: Job
+metadata:
+  name: smoke
+  uid: smoke-uid
+spec:
+  template:
+    spec:
+      restartPolicy: Never
+      containers:
+      - name: smoke
+        image: busybox
+`
+
+func TestJobRunReplacesPreviousJob(t *testing.T) {
+	previous := &batchv1.Job{ObjectMeta: metav1.ObjectMeta{Name: "smoke", Namespace: "test", Labels: map[string]string{"run": "previous"}}}
+	client := fake.NewSimpleClientset(previous)
+	jobs := kubernetes.NewJobRepository(client)
+
+	name, err := jobs.Run(context.Background(), "test", []byte(testJobManifest))
+
+	assert.NoError(t, err)
+	assert.Equal(t, "smoke", name)
+
+	job, err := client.BatchV1().Jobs("test").Get(context.Background(), "smoke", metav1.GetOptions{})
+	assert.NoError(t, err)
+	assert.Empty(t, job.Labels)
+	assert.Equal(t, "busybox", job.Spec.Template.Spec.Containers[0].Image)
+}
+
+func TestJobRunRejectsOtherKinds(t *testing.T) {
+	jobs := kubernetes.NewJobRepository(fake.NewSimpleClientset())
+
+	_, err := jobs.Run(context.Background(), "test", []byte("apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: cm\n"))
+
+	assert.Error(t, err)
+}
+
+func TestJobWaitAndLogs(t *testing.T) {
+	client := fake.NewSimpleClientset()
+	jobs := kubernetes.NewJobRepository(client)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	name, err := jobs.Run(ctx, "test", []byte(testJobManifest))
+	assert.NoError(t, err)
+
+	job, err := client.BatchV1().Jobs("test").Get(ctx, name, metav1.GetOptions{})
+	assert.NoError(t, err)
+
+	isController := true
+	pod := &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            "smoke-x7k2p",
+			Namespace:       "test",
+			Labels:          map[string]string{"job-name": name},
+			OwnerReferences: []metav1.OwnerReference{{Kind: "Job", Name: name, UID: job.UID, Controller: &isController}},
+		},
+		Spec:   v1.PodSpec{Containers: []v1.Container{{Name: "smoke"}}},
+		Status: v1.PodStatus{Phase: v1.PodSucceeded},
+	}
+	_, err = client.CoreV1().Pods("test").Create(ctx, pod, metav1.CreateOptions{})
+	assert.NoError(t, err)
+
+	go func() {
+		time.Sleep(100 * time.Millisecond)
+		job.Status.Succeeded = 1
+		job.Status.Conditions = []batchv1.JobCondition{{Type: batchv1.JobComplete, Status: v1.ConditionTrue}}
+		client.BatchV1().Jobs("test").UpdateStatus(context.Background(), job, metav1.UpdateOptions{})
+	}()
+
+	logs := bytes.Buffer{}
+	assert.NoError(t, jobs.Logs(ctx, "test", name, &logs))
+	assert.Equal(t, "fake logs", logs.String())
+
+	finished, err := jobs.Wait(ctx, "test", name)
+	assert.NoError(t, err)
+	assert.Equal(t, resource.JobReady, finished.Status)
+}
+
+func TestListJobsStatus(t *testing.T) {
+	tests := []struct {
+		name       string
+		conditions []batchv1.JobCondition
+		expected   resource.JobStatus
+	}{
+		{"running", nil, resource.JobNotReady},
+		{"complete", []batchv1.JobCondition{
+			{Type: batchv1.JobSuccessCriteriaMet, Status: v1.ConditionTrue},
+			{Type: batchv1.JobComplete, Status: v1.ConditionTrue},
+		}, resource.JobReady},
+		{"complete-false", []batchv1.JobCondition{{Type: batchv1.JobComplete, Status: v1.ConditionFalse}}, resource.JobNotReady},
+		{"complete-not-last", []batchv1.JobCondition{
+			{Type: batchv1.JobComplete, Status: v1.ConditionTrue},
+			{Type: batchv1.JobSuspended, Status: v1.ConditionFalse},
+		}, resource.JobReady},
+		{"failed", []batchv1.JobCondition{{Type: batchv1.JobFailed, Status: v1.ConditionTrue, Reason: "BackoffLimitExceeded"}}, resource.JobFailed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := fake.NewSimpleClientset(&batchv1.Job{
+				ObjectMeta: metav1.ObjectMeta{Name: "migrate", Namespace: "test"},
+				Status:     batchv1.JobStatus{Conditions: tt.conditions},
+			})
+
+			jobs, err := kubernetes.NewJobRepository(client).List("test")
+
+			assert.NoError(t, err)
+			assert.Len(t, jobs, 1)
+			assert.Equal(t, tt.expected, jobs[0].Status)
+		})
+	}
+}
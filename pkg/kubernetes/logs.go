@@ -0,0 +1,190 @@
+package kubernetes
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/DanielPickens/Keeper/pkg/resource"
+)
+
+const (
+	// logPollDuration is the period at which new pods and restarted containers are looked for while following logs
+	logPollDuration = 2 * time.Second
+
+	// maxLogLineSize is the size of the longest log line read, longer lines are split
+	maxLogLineSize = 1024 * 1024
+)
+
+// Logs streams the logs of the containers of the pods of the namespace matching the selector, all at once.
+// Without opts.Follow, it returns once the logs of the started containers have been streamed. With opts.Follow,
+// the pods are polled to stream the containers started afterwards, including restarted ones, until the context is done.
+// A container whose logs cannot be streamed is only logged, as the other ones are still streamed.
+func (pr *podRepository) Logs(ctx context.Context, namespace string, opts resource.LogOptions, lines chan<- resource.LogLine) error {
+	ctx, cancel := context.WithCancel(ctx)
+	var wg sync.WaitGroup
+	defer func() {
+		cancel()
+		wg.Wait()
+	}()
+
+	streamed := make(map[string]bool)
+
+	for {
+		pods, err := pr.kubernetes.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{LabelSelector: opts.Selector})
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return fmt.Errorf("unable to list pods of %s: %v", namespace, err)
+		}
+
+		for _, pod := range pods.Items {
+			for _, container := range loggableContainers(pod, opts.Previous) {
+				// a restarted container is a new instance whose logs are streamed again
+				key := fmt.Sprintf("%s/%s/%d", pod.UID, container.Name, container.RestartCount)
+				if streamed[key] {
+					continue
+				}
+				streamed[key] = true
+
+				wg.Add(1)
+				go func(pod, container string) {
+					defer wg.Done()
+					if err := pr.streamLines(ctx, namespace, pod, container, opts, lines); err != nil && ctx.Err() == nil {
+						logrus.WithFields(logrus.Fields{
+							"pod":       pod,
+							"container": container,
+						}).Warn(err.Error())
+					}
+				}(pod.Name, container.Name)
+			}
+		}
+
+		if !opts.Follow {
+			wg.Wait()
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(logPollDuration):
+		}
+	}
+}
+
+// loggableContainers returns the statuses of the containers of a pod that have logs : the started ones,
+// or the ones that have been restarted when the logs of the previous instances are requested.
+func loggableContainers(pod v1.Pod, previous bool) []v1.ContainerStatus {
+	var containers []v1.ContainerStatus
+
+	statuses := make([]v1.ContainerStatus, 0, len(pod.Status.InitContainerStatuses)+len(pod.Status.ContainerStatuses))
+	statuses = append(statuses, pod.Status.InitContainerStatuses...)
+	statuses = append(statuses, pod.Status.ContainerStatuses...)
+
+	for _, cs := range statuses {
+		if previous && cs.LastTerminationState.Terminated != nil ||
+			!previous && (cs.State.Running != nil || cs.State.Terminated != nil) {
+			containers = append(containers, cs)
+		}
+	}
+
+	return containers
+}
+
+// streamLines sends each line of the logs of a container to lines, until the logs end or the context is done
+func (pr *podRepository) streamLines(ctx context.Context, namespace, pod, container string, opts resource.LogOptions, lines chan<- resource.LogLine) error {
+	logOpts := &v1.PodLogOptions{
+		Container:  container,
+		Follow:     opts.Follow && !opts.Previous,
+		Previous:   opts.Previous,
+		Timestamps: true,
+	}
+	if opts.Since > 0 {
+		seconds := int64(opts.Since.Seconds())
+		logOpts.SinceSeconds = &seconds
+	}
+
+	stream, err := pr.kubernetes.CoreV1().Pods(namespace).GetLogs(pod, logOpts).Stream(ctx)
+	if err != nil {
+		return fmt.Errorf("unable to get logs of container %s of pod %s: %v", container, pod, err)
+	}
+	defer stream.Close()
+
+	scanner := bufio.NewScanner(stream)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxLogLineSize)
+
+	for scanner.Scan() {
+		select {
+		case lines <- newLogLine(pod, container, scanner.Text()):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+
+	return scanner.Err()
+}
+
+// newLogLine creates a LogLine from a line of log prefixed with its timestamp
+func newLogLine(pod, container, text string) resource.LogLine {
+	line := resource.LogLine{Pod: pod, Container: container, Message: text}
+
+	if i := strings.IndexByte(text, ' '); i > 0 {
+		if t, err := time.Parse(time.RFC3339Nano, text[:i]); err == nil {
+			line.Time = t
+			line.Message = text[i+1:]
+		}
+	}
+
+	return line
+}
+
+// WorkloadSelector returns the label selector of the pods of a deployment, a statefulset, a daemonset or a job
+func (pr *podRepository) WorkloadSelector(namespace, kind, name string) (string, error) {
+	var selector *metav1.LabelSelector
+
+	switch kind {
+	case resource.KindDeployment:
+		d, err := pr.kubernetes.AppsV1().Deployments(namespace).Get(context.Background(), name, metav1.GetOptions{})
+		if err != nil {
+			return "", fmt.Errorf("unable to get deployment %s: %v", name, err)
+		}
+		selector = d.Spec.Selector
+	case resource.KindStatefulset:
+		s, err := pr.kubernetes.AppsV1().StatefulSets(namespace).Get(context.Background(), name, metav1.GetOptions{})
+		if err != nil {
+			return "", fmt.Errorf("unable to get statefulset %s: %v", name, err)
+		}
+		selector = s.Spec.Selector
+	case resource.KindDaemonset:
+		d, err := pr.kubernetes.AppsV1().DaemonSets(namespace).Get(context.Background(), name, metav1.GetOptions{})
+		if err != nil {
+			return "", fmt.Errorf("unable to get daemonset %s: %v", name, err)
+		}
+		selector = d.Spec.Selector
+	case resource.KindJob:
+		// the pods of a job are labeled with its name
+		return "job-name=" + name, nil
+	default:
+		return "", fmt.Errorf("unable to find the pods of a %s: only deployments, statefulsets, daemonsets and jobs are supported", kind)
+	}
+
+	if selector == nil {
+		return "", fmt.Errorf("the %s %s has no selector", kind, name)
+	}
+
+	s, err := metav1.LabelSelectorAsSelector(selector)
+	if err != nil {
+		return "", fmt.Errorf("invalid selector of %s %s: %v", kind, name, err)
+	}
+
+	return s.String(), nil
+}
@@ -0,0 +1,107 @@
+package kubernetes_test
+
+import (
+	"context"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	"k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/DanielPickens/Keeper/pkg/kubernetes"
+	"github.com/DanielPickens/Keeper/pkg/resource"
+)
+
+func newLogPod(name, app string, state v1.ContainerState) *v1.Pod {
+	return &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "test", UID: types.UID("uid-" + name), Labels: map[string]string{"app": app}},
+		Status: v1.PodStatus{
+			ContainerStatuses: []v1.ContainerStatus{{Name: app, State: state}},
+		},
+	}
+}
+
+// collectLogs returns the log lines streamed without following them
+func collectLogs(t *testing.T, pods resource.PodRepository, opts resource.LogOptions) []resource.LogLine {
+	lines := make(chan resource.LogLine)
+	errs := make(chan error, 1)
+
+	go func() {
+		errs <- pods.Logs(context.Background(), "test", opts, lines)
+		close(lines)
+	}()
+
+	var collected []resource.LogLine
+	for line := range lines {
+		collected = append(collected, line)
+	}
+	assert.NoError(t, <-errs)
+
+	sort.Slice(collected, func(i, j int) bool { return collected[i].Pod < collected[j].Pod })
+	return collected
+}
+
+func TestPodLogs(t *testing.T) {
+	running := v1.ContainerState{Running: &v1.ContainerStateRunning{}}
+	client := fake.NewSimpleClientset(
+		newLogPod("api-1", "api", running),
+		newLogPod("db-0", "db", running),
+		newLogPod("api-2", "api", v1.ContainerState{Waiting: &v1.ContainerStateWaiting{Reason: "ContainerCreating"}}),
+	)
+	pods := kubernetes.NewPodRepository(client)
+
+	// the fake client answers "fake logs" to every logs request
+	assert.Equal(t, []resource.LogLine{
+		{Pod: "api-1", Container: "api", Message: "fake logs"},
+		{Pod: "db-0", Container: "db", Message: "fake logs"},
+	}, collectLogs(t, pods, resource.LogOptions{}))
+
+	assert.Equal(t, []resource.LogLine{
+		{Pod: "api-1", Container: "api", Message: "fake logs"},
+	}, collectLogs(t, pods, resource.LogOptions{Selector: "app=api"}))
+
+	// no container has been restarted
+	assert.Empty(t, collectLogs(t, pods, resource.LogOptions{Previous: true}))
+}
+
+func TestPodLogsFollowStopsWithContext(t *testing.T) {
+	client := fake.NewSimpleClientset(newLogPod("api-1", "api", v1.ContainerState{Running: &v1.ContainerStateRunning{}}))
+	pods := kubernetes.NewPodRepository(client)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	lines := make(chan resource.LogLine)
+	errs := make(chan error, 1)
+
+	go func() {
+		errs <- pods.Logs(ctx, "test", resource.LogOptions{Follow: true}, lines)
+	}()
+
+	line := <-lines
+	assert.Equal(t, "api-1", line.Pod)
+
+	cancel()
+	assert.NoError(t, <-errs)
+}
+
+func TestWorkloadSelector(t *testing.T) {
+	client := fake.NewSimpleClientset(&appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "api", Namespace: "test"},
+		Spec:       appsv1.DeploymentSpec{Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "api"}}},
+	})
+	pods := kubernetes.NewPodRepository(client)
+
+	selector, err := pods.WorkloadSelector("test", resource.KindDeployment, "api")
+	assert.NoError(t, err)
+	assert.Equal(t, "app=api", selector)
+
+	selector, err = pods.WorkloadSelector("test", resource.KindJob, "migrate")
+	assert.NoError(t, err)
+	assert.Equal(t, "job-name=migrate", selector)
+
+	_, err = pods.WorkloadSelector("test", resource.KindStatefulset, "missing")
+	assert.Error(t, err)
+}
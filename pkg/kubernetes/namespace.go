@@ -1,19 +1,23 @@
 package kubernetes
 
 import (
-	"bufio"
 	"context"
-	"errors"
 	"fmt"
-	"os/exec"
 	"path/filepath"
+	"sync"
 	"time"
 
 	"github.com/sirupsen/logrus"
 	"k8s.io/api/core/v1"
 	kerr "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes"
 
 	"github.com/DanielPickens/Keeper/pkg/resource"
@@ -25,13 +29,18 @@ const (
 
 type namespaceRepository struct {
 	kubernetes kubernetes.Interface
+	dynamic    dynamic.Interface
+	mapper     meta.RESTMapper
 }
 
 // NewNamespaceRepository returns a new NamespaceRepository.
-// The parameter is a go-client Kubernetes client
-func NewNamespaceRepository(kubernetes kubernetes.Interface) resource.NamespaceRepository {
+// The parameters are a go-client Kubernetes client, and a dynamic client and a RESTMapper, used to apply the configs
+// and to look for the resources of any kind blocking the deletion of a namespace.
+func NewNamespaceRepository(kubernetes kubernetes.Interface, dynamic dynamic.Interface, mapper meta.RESTMapper) resource.NamespaceRepository {
 	return &namespaceRepository{
 		kubernetes: kubernetes,
+		dynamic:    dynamic,
+		mapper:     mapper,
 	}
 }
 
@@ -50,6 +59,21 @@ func (ns *namespaceRepository) Create(namespace string) error {
 	return err
 }
 
+// Adopt labels an existing namespace as managed by keeper
+func (ns *namespaceRepository) Adopt(namespace string) error {
+	patch := []byte(`{"metadata":{"labels":{"manager":"keeper"}}}`)
+
+	_, err := ns.kubernetes.CoreV1().Namespaces().Patch(context.Background(), namespace, types.MergePatchType, patch, metav1.PatchOptions{})
+	if kerr.IsNotFound(err) {
+		return fmt.Errorf("namespace %s does not exist", namespace)
+	}
+	if err != nil {
+		return fmt.Errorf("unable to label namespace %s: %v", namespace, err)
+	}
+
+	return nil
+}
+
 // Get namespace with status
 func (ns *namespaceRepository) Get(namespace string) (*resource.Namespace, error) {
 	n, err := ns.kubernetes.CoreV1().Namespaces().Get(context.Background(), namespace, metav1.GetOptions{})
@@ -61,18 +85,198 @@ func (ns *namespaceRepository) Get(namespace string) (*resource.Namespace, error
 	return &resource.Namespace{Name: n.GetName(), Phase: string(n.Status.Phase)}, nil
 }
 
-// Delete deletes a given namespace
+// Delete deletes a given namespace.
+// Deleting a namespace that does not exist is not an error.
 func (ns *namespaceRepository) Delete(namespace string) error {
 	err := ns.kubernetes.CoreV1().Namespaces().Delete(context.Background(), namespace, metav1.DeleteOptions{})
+	if err != nil && !kerr.IsNotFound(err) {
+		return fmt.Errorf("unable to delete namespace %s: %v", namespace, err)
+	}
+
+	return nil
+}
+
+// WaitDeleted watches the given namespace until it is deleted or the context is done
+func (ns *namespaceRepository) WaitDeleted(ctx context.Context, namespace string) error {
+	namespaces := ns.kubernetes.CoreV1().Namespaces()
+
+	for {
+		n, err := namespaces.Get(ctx, namespace, metav1.GetOptions{})
+		if kerr.IsNotFound(err) {
+			return nil
+		}
+		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			return fmt.Errorf("unable to get namespace %s: %v", namespace, err)
+		}
+
+		watcher, err := namespaces.Watch(ctx, metav1.ListOptions{
+			FieldSelector:   fields.OneTermEqualSelector("metadata.name", namespace).String(),
+			ResourceVersion: n.ResourceVersion,
+		})
+		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			return fmt.Errorf("unable to watch namespace %s: %v", namespace, err)
+		}
+
+		deleted, err := waitDeletedEvent(ctx, watcher.ResultChan(), namespace)
+		watcher.Stop()
+
+		if err != nil || deleted {
+			return err
+		}
+		// the watch has been closed by the api server, start over
+	}
+}
+
+// waitDeletedEvent reads namespace events until the namespace is deleted, the events channel is closed or the context is done
+func waitDeletedEvent(ctx context.Context, events <-chan watch.Event, namespace string) (bool, error) {
+	for {
+		select {
+		case <-ctx.Done():
+			return false, ctx.Err()
+		case e, ok := <-events:
+			if !ok {
+				return false, nil
+			}
+
+			if n, ok := e.Object.(*v1.Namespace); ok && n.Name == namespace && e.Type == watch.Deleted {
+				return true, nil
+			}
+		}
+	}
+}
+
+// GetBlockers returns the finalizers and the deletion conditions of the given namespace and the resources of the
+// namespace having finalizers. Resources are looked for in every namespaced API served by the cluster : APIs that
+// cannot be discovered, such as unavailable aggregated APIs, are reported in the namespace conditions.
+func (ns *namespaceRepository) GetBlockers(namespace string) (*resource.NamespaceBlockers, error) {
+	n, err := ns.kubernetes.CoreV1().Namespaces().Get(context.Background(), namespace, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("unable to get namespace %s: %v", namespace, err)
+	}
+
+	blockers := &resource.NamespaceBlockers{
+		Namespace:  namespace,
+		Conditions: make([]resource.Condition, 0),
+		Resources:  make([]resource.BlockingResource, 0),
+	}
+
+	for _, f := range n.Spec.Finalizers {
+		blockers.Finalizers = append(blockers.Finalizers, string(f))
+	}
+
+	for _, cond := range n.Status.Conditions {
+		if cond.Status != v1.ConditionTrue {
+			continue
+		}
+		blockers.Conditions = append(blockers.Conditions, resource.Condition{
+			Type:    string(cond.Type),
+			Status:  string(cond.Status),
+			Reason:  cond.Reason,
+			Message: cond.Message,
+		})
+	}
+
+	// discovery returns the resources it found even if some groups failed
+	lists, err := discovery.ServerPreferredNamespacedResources(ns.kubernetes.Discovery())
+	if err != nil {
+		logrus.Warnf("unable to discover all the resources of namespace %s: %v", namespace, err)
+	}
+
+	for _, list := range lists {
+		gv, err := schema.ParseGroupVersion(list.GroupVersion)
+		if err != nil {
+			continue
+		}
+
+		for _, r := range list.APIResources {
+			if !hasVerbs(r.Verbs, "list", "patch") {
+				continue
+			}
+
+			gvr := gv.WithResource(r.Name)
+
+			objects, err := ns.dynamic.Resource(gvr).Namespace(namespace).List(context.Background(), metav1.ListOptions{})
+			if err != nil {
+				logrus.Warnf("unable to list %s of namespace %s: %v", gvr.String(), namespace, err)
+				continue
+			}
+
+			for _, obj := range objects.Items {
+				if len(obj.GetFinalizers()) == 0 {
+					continue
+				}
+
+				blockers.Resources = append(blockers.Resources, resource.BlockingResource{
+					Group:      gvr.Group,
+					Version:    gvr.Version,
+					Resource:   gvr.Resource,
+					Kind:       r.Kind,
+					Name:       obj.GetName(),
+					Finalizers: obj.GetFinalizers(),
+				})
+			}
+		}
+	}
+
+	return blockers, nil
+}
 
-	switch t := err.(type) {
-	case *kerr.StatusError:
+// ClearFinalizers removes the finalizers of the blocking resources, then the finalizers of the namespace itself.
+// Clearing finalizers skips the cleanup they guard, such as releasing external resources : it must be a last resort.
+func (ns *namespaceRepository) ClearFinalizers(blockers *resource.NamespaceBlockers) error {
+	patch := []byte(`{"metadata":{"finalizers":null}}`)
+
+	for _, r := range blockers.Resources {
+		gvr := schema.GroupVersionResource{Group: r.Group, Version: r.Version, Resource: r.Resource}
+
+		_, err := ns.dynamic.Resource(gvr).Namespace(blockers.Namespace).Patch(context.Background(), r.Name, types.MergePatchType, patch, metav1.PatchOptions{})
+		if err != nil && !kerr.IsNotFound(err) {
+			return fmt.Errorf("unable to clear the finalizers of %s %s: %v", r.Kind, r.Name, err)
+		}
+	}
+
+	if len(blockers.Finalizers) == 0 {
 		return nil
-	case *kerr.UnexpectedObjectError:
+	}
+
+	n, err := ns.kubernetes.CoreV1().Namespaces().Get(context.Background(), blockers.Namespace, metav1.GetOptions{})
+	if kerr.IsNotFound(err) {
 		return nil
-	default:
-		return t
 	}
+	if err != nil {
+		return fmt.Errorf("unable to get namespace %s: %v", blockers.Namespace, err)
+	}
+
+	n.Spec.Finalizers = nil
+
+	if _, err := ns.kubernetes.CoreV1().Namespaces().Finalize(context.Background(), n, metav1.UpdateOptions{}); err != nil && !kerr.IsNotFound(err) {
+		return fmt.Errorf("unable to clear the finalizers of namespace %s: %v", blockers.Namespace, err)
+	}
+
+	return nil
+}
+
+// hasVerbs returns true if all the given verbs are supported
+func hasVerbs(supported metav1.Verbs, verbs ...string) bool {
+	for _, verb := range verbs {
+		found := false
+		for _, s := range supported {
+			if s == verb {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
 }
 
 // List returns a slice of Namespace.
@@ -100,15 +304,90 @@ func (ns *namespaceRepository) List() ([]resource.Namespace, error) {
 	return namespaces, nil
 }
 
-// ApplyConfig loads configuration files into kubernetes
-func (ns *namespaceRepository) ApplyConfig(namespace, configPath string) error {
+// ApplyConfig applies the configs of the namespace using server-side apply, with keeper as field manager,
+// then prunes the objects that are not part of the configs anymore if requested.
+// Objects are applied by phase : infrastructure such as service accounts, config maps and secrets first, then custom
+// resource definitions and custom resources, then workloads. Jobs annotated with keeper.io/hook: pre-apply are run
+// and waited for before anything else is applied, and the ones annotated with keeper.io/hook: post-apply afterwards.
+// It returns the result of the apply of each object, and an ErrorApplyFailed if some of them could not be applied.
+func (ns *namespaceRepository) ApplyConfig(namespace, configPath string, opts resource.ApplyOptions) ([]resource.ApplyResult, error) {
+	objects, err := readObjects(filepath.Join(configPath, namespace))
+	if err != nil {
+		return nil, fmt.Errorf("the namespace could not be configured : %v", err)
+	}
+
+	a := applier{dynamic: ns.dynamic, mapper: ns.mapper, jobs: NewJobRepository(ns.kubernetes), namespace: namespace}
+
+	return a.applyConfig(objects, opts)
+}
 
-	err := execute(fmt.Sprintf("kubectl apply -f %s -n %s", filepath.Join(configPath, namespace), namespace), timeout)
+// DiffConfig applies the configs of the namespace using a server-side dry-run, and returns the result of the apply
+// of each object with the unified diff between its live and its applied version, including the objects that
+// would be pruned. An ErrorApplyFailed is returned if some objects could not be applied.
+func (ns *namespaceRepository) DiffConfig(namespace, configPath string, opts resource.ApplyOptions) ([]resource.ApplyResult, error) {
+	objects, err := readObjects(filepath.Join(configPath, namespace))
 	if err != nil {
-		return fmt.Errorf("the namespace could not be configured : %v", err)
+		return nil, fmt.Errorf("unable to read the configs of the namespace : %v", err)
 	}
 
-	return nil
+	a := applier{dynamic: ns.dynamic, mapper: ns.mapper, jobs: NewJobRepository(ns.kubernetes), namespace: namespace, dryRun: true}
+
+	return a.applyConfig(objects, opts)
+}
+
+// RunHooks runs the jobs of the configs of the namespace annotated with the given hook, one after another,
+// each one being waited for until completion. An ErrorApplyFailed is returned if one of them failed.
+func (ns *namespaceRepository) RunHooks(namespace, configPath string, hook resource.Hook) ([]resource.ApplyResult, error) {
+	objects, err := readObjects(filepath.Join(configPath, namespace))
+	if err != nil {
+		return nil, fmt.Errorf("unable to read the configs of the namespace : %v", err)
+	}
+
+	hooks, _, err := splitHooks(objects)
+	if err != nil {
+		return nil, err
+	}
+
+	a := applier{dynamic: ns.dynamic, mapper: ns.mapper, jobs: NewJobRepository(ns.kubernetes), namespace: namespace}
+
+	results := a.runHooks(context.Background(), hooks[hook], hook)
+	if hasFailed(results) {
+		return results, resource.ErrorApplyFailed{Namespace: namespace, Results: results}
+	}
+
+	return results, nil
+}
+
+// RerunJob deletes the job of the namespace with the given name, with a background propagation, then creates it again
+// from the configs of the namespace, labeled as an applied object. It returns the name of the created job, or an
+// ErrorJobNotInConfigs if the configs have no job of this name.
+func (ns *namespaceRepository) RerunJob(ctx context.Context, namespace, configPath, name string) (string, error) {
+	objects, err := readObjects(filepath.Join(configPath, namespace))
+	if err != nil {
+		return "", fmt.Errorf("unable to read the configs of the namespace : %v", err)
+	}
+
+	for _, obj := range objects {
+		gvk := obj.GroupVersionKind()
+		if gvk.Group != "batch" || gvk.Kind != "Job" || obj.GetName() != name {
+			continue
+		}
+		if obj.GetNamespace() != "" && obj.GetNamespace() != namespace {
+			return "", fmt.Errorf("the job %s belongs to namespace %s instead of %s", name, obj.GetNamespace(), namespace)
+		}
+
+		a := applier{dynamic: ns.dynamic, mapper: ns.mapper, jobs: NewJobRepository(ns.kubernetes), namespace: namespace}
+		a.label(obj)
+
+		manifest, err := obj.MarshalJSON()
+		if err != nil {
+			return "", fmt.Errorf("unable to write the job %s: %v", name, err)
+		}
+
+		return a.jobs.Run(ctx, namespace, manifest)
+	}
+
+	return "", resource.ErrorJobNotInConfigs{Namespace: namespace, Name: name}
 }
 
 // Watch namespace events and send it to events channel
@@ -142,54 +421,61 @@ func (ns *namespaceRepository) Watch(events chan<- resource.NamespaceEvent) erro
 	return nil
 }
 
-func execute(c string, t time.Duration) error {
+// WatchWorkloads watches the workloads and the pods of a namespace and notifies on the changes channel
+// each time one of them changes. Notifications are coalesced : a notification is dropped if the previous
+// one has not been consumed yet. It blocks until the context is done or one of the watches is closed.
+func (ns *namespaceRepository) WatchWorkloads(ctx context.Context, namespace string, changes chan<- struct{}) error {
+	watchers := []func(context.Context, metav1.ListOptions) (watch.Interface, error){
+		ns.kubernetes.AppsV1().Deployments(namespace).Watch,
+		ns.kubernetes.AppsV1().StatefulSets(namespace).Watch,
+		ns.kubernetes.AppsV1().DaemonSets(namespace).Watch,
+		ns.kubernetes.BatchV1().Jobs(namespace).Watch,
+		ns.kubernetes.BatchV1().CronJobs(namespace).Watch,
+		ns.kubernetes.CoreV1().Pods(namespace).Watch,
+		ns.kubernetes.CoreV1().PersistentVolumeClaims(namespace).Watch,
+		ns.kubernetes.NetworkingV1().Ingresses(namespace).Watch,
+	}
 
-	cmd := exec.Command("/bin/sh", "-c", c)
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
 
-	cmdReader, err := cmd.StdoutPipe()
-	if err != nil {
-		logrus.Warn("Error creating StdoutPipe for Cmd")
-		return err
-	}
+	var wg sync.WaitGroup
 
-	scanner := bufio.NewScanner(cmdReader)
-	go func() {
-		for scanner.Scan() {
-			logrus.Info(scanner.Text())
+	for _, w := range watchers {
+		watcher, err := w(ctx, metav1.ListOptions{})
+		if err != nil {
+			cancel()
+			wg.Wait()
+			return fmt.Errorf("unable to watch workloads of namespace %s: %v", namespace, err)
 		}
-	}()
 
-	// Start process. Exit code 127 if process fail to start.
-	if err := cmd.Start(); err != nil {
-		logrus.Warn("Error stating Cmd")
-		return err
-	}
-
-	var timer *time.Timer
-	if t > 0 {
-		timer = time.NewTimer(t)
-		go func(timer *time.Timer, cmd *exec.Cmd) {
-			//TODO: use a chan and select pattern to output the error
-			for range timer.C {
-				e := cmd.Process.Kill()
-				if e != nil {
-					err = errors.New("the command has timeout but the process could not be killed")
-				} else {
-					err = errors.New("the command timed out")
+		wg.Add(1)
+
+		go func(watcher watch.Interface) {
+			defer wg.Done()
+			defer watcher.Stop()
+			// the first closed watch stops all the others
+			defer cancel()
+
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case _, ok := <-watcher.ResultChan():
+					if !ok {
+						return
+					}
+
+					select {
+					case changes <- struct{}{}:
+					default:
+					}
 				}
 			}
-		}(timer, cmd)
-	}
-
-	err = cmd.Wait()
-
-	if t > 0 {
-		timer.Stop()
+		}(watcher)
 	}
 
-	if err != nil {
-		return errors.New("the command did not succeed")
-	}
+	wg.Wait()
 
 	return nil
 }
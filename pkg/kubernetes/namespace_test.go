@@ -0,0 +1,211 @@
+package kubernetes_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	"k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/DanielPickens/Keeper/pkg/kubernetes"
+	"github.com/DanielPickens/Keeper/pkg/resource"
+)
+
+func TestWatchWorkloads(t *testing.T) {
+	client := fake.NewSimpleClientset()
+	repository := kubernetes.NewNamespaceRepository(client, nil, nil)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	changes := make(chan struct{}, 1)
+	done := make(chan error, 1)
+
+	go func() {
+		done <- repository.WatchWorkloads(ctx, "test", changes)
+	}()
+
+	// keep on creating deployments until the watches are started and a change is notified
+	notified := false
+	for i := 0; i < 100 && !notified; i++ {
+		d := &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: fmt.Sprintf("api-%d", i), Namespace: "test"}}
+		_, err := client.AppsV1().Deployments("test").Create(context.Background(), d, metav1.CreateOptions{})
+		assert.NoError(t, err)
+
+		select {
+		case <-changes:
+			notified = true
+		case <-time.After(50 * time.Millisecond):
+		}
+	}
+	assert.True(t, notified, "a change should have been notified")
+
+	cancel()
+
+	select {
+	case err := <-done:
+		assert.NoError(t, err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("WatchWorkloads should return once the context is cancelled")
+	}
+}
+
+func newTerminatingNamespace() *v1.Namespace {
+	return &v1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{Name: "test"},
+		Spec:       v1.NamespaceSpec{Finalizers: []v1.FinalizerName{v1.FinalizerKubernetes}},
+		Status: v1.NamespaceStatus{
+			Phase: v1.NamespaceTerminating,
+			Conditions: []v1.NamespaceCondition{
+				{Type: v1.NamespaceDeletionDiscoveryFailure, Status: v1.ConditionFalse, Reason: "ResourcesDiscovered"},
+				{
+					Type:    v1.NamespaceFinalizersRemaining,
+					Status:  v1.ConditionTrue,
+					Reason:  "SomeFinalizersRemain",
+					Message: "Some content in the namespace has finalizers remaining: argoproj.io/finalizer in 1 resource instances",
+				},
+			},
+		},
+	}
+}
+
+func newBlockingNamespaceRepository() (resource.NamespaceRepository, *fake.Clientset, *dynamicfake.FakeDynamicClient) {
+	client := fake.NewSimpleClientset(newTerminatingNamespace())
+	client.Resources = []*metav1.APIResourceList{
+		{
+			GroupVersion: rolloutGVK.GroupVersion().String(),
+			APIResources: []metav1.APIResource{
+				{Name: "rollouts", Namespaced: true, Kind: "Rollout", Verbs: metav1.Verbs{"get", "list", "patch"}},
+			},
+		},
+	}
+
+	blocked := newCustomResource(rolloutGVK, "api", nil, nil)
+	blocked.SetFinalizers([]string{"argoproj.io/finalizer"})
+
+	dynamic := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(
+		runtime.NewScheme(),
+		map[schema.GroupVersionResource]string{
+			rolloutGVK.GroupVersion().WithResource("rollouts"): "RolloutList",
+		},
+		blocked,
+		newCustomResource(rolloutGVK, "front", nil, nil),
+	)
+
+	return kubernetes.NewNamespaceRepository(client, dynamic, nil), client, dynamic
+}
+
+func TestGetBlockers(t *testing.T) {
+	repository, _, _ := newBlockingNamespaceRepository()
+
+	blockers, err := repository.GetBlockers("test")
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"kubernetes"}, blockers.Finalizers)
+	assert.Len(t, blockers.Conditions, 1)
+	assert.Equal(t, "SomeFinalizersRemain", blockers.Conditions[0].Reason)
+	assert.Equal(t, []resource.BlockingResource{
+		{
+			Group:      "argoproj.io",
+			Version:    "v1alpha1",
+			Resource:   "rollouts",
+			Kind:       "Rollout",
+			Name:       "api",
+			Finalizers: []string{"argoproj.io/finalizer"},
+		},
+	}, blockers.Resources)
+}
+
+func TestClearFinalizers(t *testing.T) {
+	repository, _, dynamic := newBlockingNamespaceRepository()
+
+	blockers, err := repository.GetBlockers("test")
+	assert.NoError(t, err)
+
+	assert.NoError(t, repository.ClearFinalizers(blockers))
+
+	rollout, err := dynamic.Resource(rolloutGVK.GroupVersion().WithResource("rollouts")).Namespace("test").Get(context.Background(), "api", metav1.GetOptions{})
+	assert.NoError(t, err)
+	assert.Empty(t, rollout.GetFinalizers())
+}
+
+func TestWaitDeleted(t *testing.T) {
+	repository, client, _ := newBlockingNamespaceRepository()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	assert.Equal(t, context.DeadlineExceeded, repository.WaitDeleted(ctx, "test"))
+
+	go func() {
+		time.Sleep(100 * time.Millisecond)
+		client.CoreV1().Namespaces().Delete(context.Background(), "test", metav1.DeleteOptions{})
+	}()
+
+	ctx, cancel = context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	assert.NoError(t, repository.WaitDeleted(ctx, "test"))
+}
+
+func TestGetStatusIgnoredKinds(t *testing.T) {
+	replicas := int32(1)
+
+	tests := []struct {
+		name      string
+		ignore    []string
+		status    int
+		workloads int
+	}{
+		{"none", nil, 33, 3},
+		{"pvc", []string{"PersistentVolumeClaim"}, 50, 2},
+		{"case-insensitive", []string{"persistentvolumeclaim", "ingress"}, 100, 1},
+		{"all", []string{"Deployment", "PersistentVolumeClaim", "Ingress"}, 0, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := fake.NewSimpleClientset(
+				&v1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "test"}},
+				&appsv1.Deployment{
+					ObjectMeta: metav1.ObjectMeta{Name: "api", Namespace: "test"},
+					Spec:       appsv1.DeploymentSpec{Replicas: &replicas},
+					Status:     appsv1.DeploymentStatus{Replicas: 1, UpdatedReplicas: 1, ReadyReplicas: 1},
+				},
+				&v1.PersistentVolumeClaim{
+					ObjectMeta: metav1.ObjectMeta{Name: "data", Namespace: "test"},
+					Status:     v1.PersistentVolumeClaimStatus{Phase: v1.ClaimPending},
+				},
+				&networkingv1.Ingress{ObjectMeta: metav1.ObjectMeta{Name: "front", Namespace: "test"}},
+			)
+			dynamic := dynamicfake.NewSimpleDynamicClient(runtime.NewScheme())
+
+			ns := resource.NewNamespaceService(
+				kubernetes.NewNamespaceRepository(client, dynamic, nil),
+				kubernetes.NewPodRepository(client),
+				kubernetes.NewDeploymentRepository(client),
+				kubernetes.NewStatefulsetRepository(client),
+				kubernetes.NewJobRepository(client),
+				kubernetes.NewDaemonsetRepository(client),
+				kubernetes.NewPersistentVolumeClaimRepository(client),
+				kubernetes.NewIngressRepository(client),
+				kubernetes.NewCronJobRepository(client),
+				kubernetes.NewCustomResourceRepository(dynamic, nil),
+				resource.Readiness{Ignore: tt.ignore},
+			)
+
+			status, err := ns.GetStatus("test")
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.status, status.Status)
+			assert.Len(t, status.Workloads, tt.workloads)
+		})
+	}
+}
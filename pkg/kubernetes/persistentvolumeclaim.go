@@ -0,0 +1,68 @@
+package kubernetes
+
+import (
+	"context"
+	"fmt"
+
+	"k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/DanielPickens/Keeper/pkg/resource"
+)
+
+type persistentVolumeClaimRepository struct {
+	kubernetes kubernetes.Interface
+}
+
+// NewPersistentVolumeClaimRepository returns a new PersistentVolumeClaimRepository.
+// The parameter is a go-client kubernetes client.
+func NewPersistentVolumeClaimRepository(kubernetes kubernetes.Interface) resource.PersistentVolumeClaimRepository {
+	return &persistentVolumeClaimRepository{
+		kubernetes: kubernetes,
+	}
+}
+
+// List returns the persistent volume claims of the given namespace.
+// A claim is ready once it is bound to a volume.
+func (c *persistentVolumeClaimRepository) List(namespace string) (resource.PersistentVolumeClaims, error) {
+	pl, err := c.kubernetes.CoreV1().PersistentVolumeClaims(namespace).List(context.Background(), metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("unable to list persistent volume claims: %v", err)
+	}
+
+	pvcs := make(resource.PersistentVolumeClaims, 0)
+
+	for _, pvc := range pl.Items {
+		status := resource.PersistentVolumeClaimNotReady
+		conditions := make([]resource.Condition, 0, len(pvc.Status.Conditions)+1)
+
+		if pvc.Status.Phase == v1.ClaimBound {
+			status = resource.PersistentVolumeClaimReady
+		} else {
+			conditions = append(conditions, resource.Condition{
+				Type:   "Bound",
+				Status: string(v1.ConditionFalse),
+				Reason: string(pvc.Status.Phase),
+			})
+		}
+
+		for _, cond := range pvc.Status.Conditions {
+			conditions = append(conditions, resource.Condition{
+				Type:    string(cond.Type),
+				Status:  string(cond.Status),
+				Reason:  cond.Reason,
+				Message: cond.Message,
+			})
+		}
+
+		pvcs = append(pvcs, resource.PersistentVolumeClaim{
+			Name:       pvc.Name,
+			Status:     status,
+			Phase:      string(pvc.Status.Phase),
+			Conditions: conditions,
+		})
+	}
+
+	return pvcs, nil
+}
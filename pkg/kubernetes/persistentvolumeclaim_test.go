@@ -0,0 +1,43 @@
+package kubernetes_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/DanielPickens/Keeper/pkg/kubernetes"
+	"github.com/DanielPickens/Keeper/pkg/resource"
+)
+
+func TestListPersistentVolumeClaimsStatus(t *testing.T) {
+	tests := []struct {
+		name       string
+		phase      v1.PersistentVolumeClaimPhase
+		expected   resource.PersistentVolumeClaimStatus
+		conditions int
+	}{
+		{"bound", v1.ClaimBound, resource.PersistentVolumeClaimReady, 0},
+		{"pending", v1.ClaimPending, resource.PersistentVolumeClaimNotReady, 1},
+		{"lost", v1.ClaimLost, resource.PersistentVolumeClaimNotReady, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := fake.NewSimpleClientset(&v1.PersistentVolumeClaim{
+				ObjectMeta: metav1.ObjectMeta{Name: "data", Namespace: "test"},
+				Status:     v1.PersistentVolumeClaimStatus{Phase: tt.phase},
+			})
+
+			pvcs, err := kubernetes.NewPersistentVolumeClaimRepository(client).List("test")
+
+			assert.NoError(t, err)
+			assert.Len(t, pvcs, 1)
+			assert.Equal(t, tt.expected, pvcs[0].Status)
+			assert.Equal(t, string(tt.phase), pvcs[0].Phase)
+			assert.Len(t, pvcs[0].Conditions, tt.conditions)
+		})
+	}
+}
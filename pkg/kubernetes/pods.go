@@ -2,42 +2,262 @@ package kubernetes
 
 import (
 	"context"
-	"github.com/DanielPickens/Keeper/pkg/resource"
+	"fmt"
+	"sort"
+	"time"
 
+	"k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes"
+
+	"github.com/DanielPickens/Keeper/pkg/resource"
 )
 
-type podreposity struct {
-	kubernetes kubernetes.Interface
+// waitingFailures are the container waiting reasons considered as a failure
+var waitingFailures = map[string]bool{
+	resource.PodCrashLoopBackOff: true,
+	resource.PodImagePullBackOff: true,
+	resource.PodErrImagePull:     true,
+	"CreateContainerConfigError": true,
+	resource.PodInvalidImageName: true,
 }
 
-//newPodRepository returns a new PodRepository
-func newPodRepository(kubernetes kubernetes.Interface) resource.PodRepository {
-	return &PodRepository
+type podRepository struct {
+	kubernetes kubernetes.Interface
 }
 
+// NewPodRepository returns a new PodRepository.
+// The parameter is a go-client kubernetes client.
+func NewPodRepository(kubernetes kubernetes.Interface) resource.PodRepository {
+	return &podRepository{
+		kubernetes: kubernetes,
+	}
+}
 
-func (pr *PodRepository) List(n string) (resource.Pods, error) {
-	podList, err := pr.kubernetes.Corev1().Pods(n).List(
-		context.Background()
-		metav1.ListOptions{FieldSelector: "status.phase!=Succeeded"}
-	)
+// List returns the pods of the given namespace, sorted by name, with their containers and the workload owning them.
+// Each pod that is not succeeded comes with the failures detected from its status and its containers statuses.
+func (pr *podRepository) List(n string) (resource.Pods, error) {
+	podList, err := pr.kubernetes.CoreV1().Pods(n).List(context.Background(), metav1.ListOptions{})
 	if err != nil {
 		return nil, err
 	}
 
-	var pods resource.Pods
+	owners := podOwners{kubernetes: pr.kubernetes, namespace: n}
+
+	pods := make(resource.Pods, 0, len(podList.Items))
 
 	for _, pod := range podList.Items {
+		p := resource.Pod{
+			Name:      pod.Name,
+			Status:    string(pod.Status.Phase),
+			Total:     int32(len(pod.Spec.Containers)),
+			Node:      pod.Spec.NodeName,
+			IP:        pod.Status.PodIP,
+			CreatedAt: pod.CreationTimestamp.Time,
+			Owner:     owners.owner(pod.ObjectMeta),
+		}
 
-		pods := append(pods,resource.Pod{
-			Name: pod.ObjectMeta.Name
-			Status: pod.Status.Phase, 
-		})
+		p.Containers, p.LastTerminationReason = podContainers(pod)
+		for _, c := range p.Containers {
+			if c.Ready {
+				p.Ready++
+			}
+			p.Restarts += c.Restarts
+		}
 
+		if pod.Status.Phase != v1.PodSucceeded {
+			p.Failures = podFailures(pod)
+		}
+
+		pods = append(pods, p)
 	}
 
+	sort.Slice(pods, func(i, j int) bool { return pods[i].Name < pods[j].Name })
+
 	return pods, nil
 }
 
+// podContainers returns the containers of a pod with their statuses, and the reason why the container that
+// terminated last did so, if any
+func podContainers(pod v1.Pod) ([]resource.PodContainer, string) {
+	statuses := make(map[string]v1.ContainerStatus, len(pod.Status.ContainerStatuses))
+	for _, cs := range pod.Status.ContainerStatuses {
+		statuses[cs.Name] = cs
+	}
+
+	containers := make([]resource.PodContainer, 0, len(pod.Spec.Containers))
+
+	var (
+		lastReason string
+		lastTime   time.Time
+	)
+
+	for _, c := range pod.Spec.Containers {
+		container := resource.PodContainer{Name: c.Name, Image: c.Image, State: resource.ContainerWaiting}
+
+		if cs, ok := statuses[c.Name]; ok {
+			container.Ready = cs.Ready
+			container.Restarts = cs.RestartCount
+
+			switch {
+			case cs.State.Running != nil:
+				container.State = resource.ContainerRunning
+			case cs.State.Terminated != nil:
+				container.State = resource.ContainerTerminated
+				container.Reason = cs.State.Terminated.Reason
+			case cs.State.Waiting != nil:
+				container.Reason = cs.State.Waiting.Reason
+			}
+
+			if last := cs.LastTerminationState.Terminated; last != nil {
+				container.LastTerminationReason = last.Reason
+			}
+
+			for _, terminated := range []*v1.ContainerStateTerminated{cs.LastTerminationState.Terminated, cs.State.Terminated} {
+				if terminated != nil && terminated.Reason != "" && !terminated.FinishedAt.Time.Before(lastTime) {
+					lastReason, lastTime = terminated.Reason, terminated.FinishedAt.Time
+				}
+			}
+		}
+
+		containers = append(containers, container)
+	}
+
+	return containers, lastReason
+}
+
+// podOwners resolves the workloads owning the pods of a namespace, looking up the deployments owning the
+// replicasets and the cronjobs owning the jobs. The replicasets and the jobs are listed once, when first needed.
+type podOwners struct {
+	kubernetes kubernetes.Interface
+	namespace  string
+
+	replicaSets map[string]*resource.PodOwner
+	jobs        map[string]*resource.PodOwner
+}
+
+// owner returns the workload owning a pod, nil when the pod is not managed by a controller
+func (po *podOwners) owner(meta metav1.ObjectMeta) *resource.PodOwner {
+	ref := metav1.GetControllerOf(&meta)
+	if ref == nil {
+		return nil
+	}
+
+	switch ref.Kind {
+	case "ReplicaSet":
+		if po.replicaSets == nil {
+			po.replicaSets = po.listReplicaSetOwners()
+		}
+		if owner, ok := po.replicaSets[ref.Name]; ok {
+			return owner
+		}
+	case resource.KindJob:
+		if po.jobs == nil {
+			po.jobs = po.listJobOwners()
+		}
+		if owner, ok := po.jobs[ref.Name]; ok {
+			return owner
+		}
+	}
+
+	return &resource.PodOwner{Kind: ref.Kind, Name: ref.Name}
+}
+
+// listReplicaSetOwners returns the deployments owning the replicasets of the namespace, by replicaset name.
+// The owners cannot be resolved when the replicasets cannot be listed, which is not an error.
+func (po *podOwners) listReplicaSetOwners() map[string]*resource.PodOwner {
+	owners := make(map[string]*resource.PodOwner)
+
+	rsl, err := po.kubernetes.AppsV1().ReplicaSets(po.namespace).List(context.Background(), metav1.ListOptions{})
+	if err != nil {
+		return owners
+	}
+
+	for _, rs := range rsl.Items {
+		if ref := metav1.GetControllerOf(&rs); ref != nil {
+			owners[rs.Name] = &resource.PodOwner{Kind: ref.Kind, Name: ref.Name}
+		}
+	}
+
+	return owners
+}
+
+// listJobOwners returns the cronjobs owning the jobs of the namespace, by job name.
+// The owners cannot be resolved when the jobs cannot be listed, which is not an error.
+func (po *podOwners) listJobOwners() map[string]*resource.PodOwner {
+	owners := make(map[string]*resource.PodOwner)
+
+	jl, err := po.kubernetes.BatchV1().Jobs(po.namespace).List(context.Background(), metav1.ListOptions{})
+	if err != nil {
+		return owners
+	}
+
+	for _, job := range jl.Items {
+		if ref := metav1.GetControllerOf(&job); ref != nil {
+			owners[job.Name] = &resource.PodOwner{Kind: ref.Kind, Name: ref.Name}
+		}
+	}
+
+	return owners
+}
+
+// podFailures harvests the failure reasons of a pod: unschedulable pending pods,
+// containers waiting in a crash loop or for an image that cannot be pulled, and OOM killed containers.
+func podFailures(pod v1.Pod) []resource.PodFailure {
+	var failures []resource.PodFailure
+
+	if pod.Status.Phase == v1.PodPending {
+		for _, cond := range pod.Status.Conditions {
+			if cond.Type == v1.PodScheduled && cond.Status == v1.ConditionFalse && cond.Reason == v1.PodReasonUnschedulable {
+				failures = append(failures, resource.PodFailure{
+					Pod:     pod.Name,
+					Reason:  resource.PodUnschedulable,
+					Message: cond.Message,
+				})
+			}
+		}
+	}
+
+	statuses := make([]v1.ContainerStatus, 0, len(pod.Status.InitContainerStatuses)+len(pod.Status.ContainerStatuses))
+	statuses = append(statuses, pod.Status.InitContainerStatuses...)
+	statuses = append(statuses, pod.Status.ContainerStatuses...)
+
+	for _, cs := range statuses {
+		failure := resource.PodFailure{
+			Pod:       pod.Name,
+			Container: cs.Name,
+			Restarts:  cs.RestartCount,
+		}
+
+		switch {
+		case cs.State.Waiting != nil && waitingFailures[cs.State.Waiting.Reason]:
+			failure.Reason = cs.State.Waiting.Reason
+			failure.Message = cs.State.Waiting.Message
+		case cs.State.Terminated != nil && cs.State.Terminated.Reason == resource.PodOOMKilled:
+			failure.Reason = resource.PodOOMKilled
+		case cs.LastTerminationState.Terminated != nil && cs.LastTerminationState.Terminated.Reason == resource.PodOOMKilled:
+			failure.Reason = resource.PodOOMKilled
+		default:
+			continue
+		}
+
+		if last := cs.LastTerminationState.Terminated; failure.Message == "" && last != nil {
+			failure.Message = fmt.Sprintf("last terminated with reason %s (exit code %d)", last.Reason, last.ExitCode)
+		}
+
+		failures = append(failures, failure)
+	}
+
+	return failures
+}
+
+// containers returns the containers of a pod template and their images
+func containers(spec v1.PodSpec) []resource.Container {
+	cs := make([]resource.Container, 0, len(spec.Containers))
+
+	for _, c := range spec.Containers {
+		cs = append(cs, resource.Container{Name: c.Name, Image: c.Image})
+	}
+
+	return cs
+}
@@ -0,0 +1,184 @@
+package kubernetes_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	"k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/DanielPickens/Keeper/pkg/kubernetes"
+	"github.com/DanielPickens/Keeper/pkg/resource"
+)
+
+func TestListPodsFailures(t *testing.T) {
+	client := fake.NewSimpleClientset(
+		&v1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: "api", Namespace: "test"},
+			Status: v1.PodStatus{
+				Phase: v1.PodRunning,
+				ContainerStatuses: []v1.ContainerStatus{
+					{
+						Name:         "api",
+						RestartCount: 4,
+						State:        v1.ContainerState{Waiting: &v1.ContainerStateWaiting{Reason: "CrashLoopBackOff"}},
+						LastTerminationState: v1.ContainerState{
+							Terminated: &v1.ContainerStateTerminated{Reason: "OOMKilled", ExitCode: 137},
+						},
+					},
+				},
+			},
+		},
+		&v1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: "front", Namespace: "test"},
+			Status: v1.PodStatus{
+				Phase: v1.PodPending,
+				ContainerStatuses: []v1.ContainerStatus{
+					{
+						Name:  "front",
+						State: v1.ContainerState{Waiting: &v1.ContainerStateWaiting{Reason: "ImagePullBackOff", Message: "tag not found"}},
+					},
+				},
+			},
+		},
+		&v1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: "db", Namespace: "test"},
+			Status: v1.PodStatus{
+				Phase: v1.PodPending,
+				Conditions: []v1.PodCondition{
+					{Type: v1.PodScheduled, Status: v1.ConditionFalse, Reason: "Unschedulable", Message: "0/3 nodes are available"},
+				},
+			},
+		},
+		&v1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: "worker", Namespace: "test"},
+			Status: v1.PodStatus{
+				Phase: v1.PodRunning,
+				ContainerStatuses: []v1.ContainerStatus{
+					{Name: "worker", Ready: true, State: v1.ContainerState{Running: &v1.ContainerStateRunning{}}},
+				},
+			},
+		},
+	)
+
+	pods, err := kubernetes.NewPodRepository(client).List("test")
+
+	assert.Nil(t, err)
+	assert.Len(t, pods, 4)
+	assert.ElementsMatch(t, []resource.PodFailure{
+		{Pod: "api", Container: "api", Reason: "CrashLoopBackOff", Restarts: 4, Message: "last terminated with reason OOMKilled (exit code 137)"},
+		{Pod: "front", Container: "front", Reason: "ImagePullBackOff", Message: "tag not found"},
+		{Pod: "db", Reason: "Unschedulable", Message: "0/3 nodes are available"},
+	}, pods.Failures())
+}
+
+func TestListPods(t *testing.T) {
+	controller := true
+	created := time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)
+	oomKilledAt := metav1.NewTime(created.Add(time.Hour))
+
+	client := fake.NewSimpleClientset(
+		&appsv1.ReplicaSet{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:            "api-7d9f",
+				Namespace:       "test",
+				OwnerReferences: []metav1.OwnerReference{{Kind: "Deployment", Name: "api", Controller: &controller}},
+			},
+		},
+		&batchv1.Job{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:            "backup-123",
+				Namespace:       "test",
+				OwnerReferences: []metav1.OwnerReference{{Kind: "CronJob", Name: "backup", Controller: &controller}},
+			},
+		},
+		&v1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:              "api-7d9f-x2k",
+				Namespace:         "test",
+				CreationTimestamp: metav1.NewTime(created),
+				OwnerReferences:   []metav1.OwnerReference{{Kind: "ReplicaSet", Name: "api-7d9f", Controller: &controller}},
+			},
+			Spec: v1.PodSpec{
+				NodeName:   "node-1",
+				Containers: []v1.Container{{Name: "api", Image: "api:1.2"}, {Name: "proxy", Image: "envoy:1.29"}},
+			},
+			Status: v1.PodStatus{
+				Phase: v1.PodRunning,
+				PodIP: "10.0.0.12",
+				ContainerStatuses: []v1.ContainerStatus{
+					{
+						Name:         "api",
+						RestartCount: 3,
+						State:        v1.ContainerState{Waiting: &v1.ContainerStateWaiting{Reason: "CrashLoopBackOff"}},
+						LastTerminationState: v1.ContainerState{
+							Terminated: &v1.ContainerStateTerminated{Reason: "OOMKilled", ExitCode: 137, FinishedAt: oomKilledAt},
+						},
+					},
+					{Name: "proxy", Ready: true, State: v1.ContainerState{Running: &v1.ContainerStateRunning{}}},
+				},
+			},
+		},
+		&v1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:            "backup-123-abc",
+				Namespace:       "test",
+				OwnerReferences: []metav1.OwnerReference{{Kind: "Job", Name: "backup-123", Controller: &controller}},
+			},
+			Spec: v1.PodSpec{Containers: []v1.Container{{Name: "backup", Image: "backup:latest"}}},
+			Status: v1.PodStatus{
+				Phase: v1.PodSucceeded,
+				ContainerStatuses: []v1.ContainerStatus{
+					{
+						Name:  "backup",
+						State: v1.ContainerState{Terminated: &v1.ContainerStateTerminated{Reason: "Completed"}},
+						LastTerminationState: v1.ContainerState{
+							Terminated: &v1.ContainerStateTerminated{Reason: "OOMKilled", ExitCode: 137},
+						},
+					},
+				},
+			},
+		},
+		&v1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: "debug", Namespace: "test"},
+			Spec:       v1.PodSpec{Containers: []v1.Container{{Name: "debug", Image: "busybox"}}},
+			Status:     v1.PodStatus{Phase: v1.PodPending},
+		},
+	)
+
+	pods, err := kubernetes.NewPodRepository(client).List("test")
+
+	assert.Nil(t, err)
+	assert.Len(t, pods, 3)
+
+	api := pods[0]
+	assert.Equal(t, "api-7d9f-x2k", api.Name)
+	assert.Equal(t, int32(1), api.Ready)
+	assert.Equal(t, int32(2), api.Total)
+	assert.Equal(t, int32(3), api.Restarts)
+	assert.Equal(t, "node-1", api.Node)
+	assert.Equal(t, "10.0.0.12", api.IP)
+	assert.True(t, created.Equal(api.CreatedAt))
+	assert.Equal(t, &resource.PodOwner{Kind: "Deployment", Name: "api"}, api.Owner)
+	assert.Equal(t, "OOMKilled", api.LastTerminationReason)
+	assert.Equal(t, []resource.PodContainer{
+		{Name: "api", Image: "api:1.2", Restarts: 3, State: resource.ContainerWaiting, Reason: "CrashLoopBackOff", LastTerminationReason: "OOMKilled"},
+		{Name: "proxy", Image: "envoy:1.29", Ready: true, State: resource.ContainerRunning},
+	}, api.Containers)
+	assert.Len(t, api.Failures, 1)
+
+	backup := pods[1]
+	assert.Equal(t, "backup-123-abc", backup.Name)
+	assert.Equal(t, &resource.PodOwner{Kind: "CronJob", Name: "backup"}, backup.Owner)
+	assert.Equal(t, resource.ContainerTerminated, backup.Containers[0].State)
+	assert.Equal(t, "Completed", backup.LastTerminationReason)
+	assert.Empty(t, backup.Failures, "a succeeded pod has no failures")
+
+	debug := pods[2]
+	assert.Nil(t, debug.Owner)
+	assert.Equal(t, []resource.PodContainer{{Name: "debug", Image: "busybox", State: resource.ContainerWaiting}}, debug.Containers)
+}
@@ -0,0 +1,45 @@
+package kubernetes
+
+import (
+	"sync"
+)
+
+// ClientPool keeps a kubernetes client per context of a kubeconfig file.
+// Clients are created the first time their context is used.
+type ClientPool struct {
+	configFilePath string
+	mu             sync.Mutex
+	clients        map[string]*Client
+}
+
+// NewClientPool returns a new ClientPool for the given kubeconfig file
+func NewClientPool(configFilePath string) *ClientPool {
+	return &ClientPool{
+		configFilePath: configFilePath,
+		clients:        make(map[string]*Client),
+	}
+}
+
+// Get returns the client of the given context, the current context of the kubeconfig file if the context is empty
+func (p *ClientPool) Get(context string) (*Client, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if c, ok := p.clients[context]; ok {
+		return c, nil
+	}
+
+	c, err := NewClient(p.configFilePath, context)
+	if err != nil {
+		return nil, err
+	}
+
+	p.clients[context] = c
+
+	return c, nil
+}
+
+// Contexts returns the names of the contexts of the kubeconfig file and the current one
+func (p *ClientPool) Contexts() ([]string, string, error) {
+	return Contexts(p.configFilePath)
+}
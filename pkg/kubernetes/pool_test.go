@@ -0,0 +1,70 @@
+package kubernetes_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/DanielPickens/Keeper/pkg/kubernetes"
+)
+
+const kubeconfig = `apiVersion: v1
+kind: Config
+current-context: staging
+clusters:
+- name: staging
+  cluster:
+    server: https://staging.example.com:6443
+- name: perf
+  cluster:
+    server: https://perf.example.com
+contexts:
+- name: staging
+  context:
+    cluster: staging
+    user: dev
+- name: perf
+  context:
+    cluster: perf
+    user: dev
+users:
+- name: dev
+  user:
+    token: secret
+`
+
+func TestClientPool(t *testing.T) {
+	dir, err := ioutil.TempDir("", "keeper")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "config")
+	assert.NoError(t, ioutil.WriteFile(path, []byte(kubeconfig), 0600))
+
+	pool := kubernetes.NewClientPool(path)
+
+	contexts, current, err := pool.Contexts()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"perf", "staging"}, contexts)
+	assert.Equal(t, "staging", current)
+
+	perf, err := pool.Get("perf")
+	assert.NoError(t, err)
+
+	again, err := pool.Get("perf")
+	assert.NoError(t, err)
+	assert.True(t, perf == again, "the client of a context should be created once")
+
+	staging, err := pool.Get("")
+	assert.NoError(t, err)
+	assert.False(t, perf == staging)
+
+	_, err = pool.Get("unknown")
+	assert.Error(t, err)
+
+	assert.Equal(t, "perf.example.com", kubernetes.GetKubernetesHost(path, "perf"))
+	assert.Equal(t, "staging.example.com", kubernetes.GetKubernetesHost(path, ""))
+}
@@ -0,0 +1,250 @@
+package kubernetes
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"sort"
+
+	"github.com/sirupsen/logrus"
+	"k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/tools/portforward"
+	"k8s.io/client-go/transport/spdy"
+
+	"github.com/DanielPickens/Keeper/pkg/resource"
+)
+
+// ForwardTargets returns the ports of the services of the namespace, each one targeting a ready pod of its service.
+// When names is empty, all the services of the namespace having a ready pod are returned, the other ones being
+// skipped. Otherwise, an error is returned if one of the services does not exist or has no ready pod.
+func (sr *serviceRepository) ForwardTargets(namespace string, names []string) ([]resource.ForwardedPort, error) {
+	var services []v1.Service
+
+	if len(names) == 0 {
+		sl, err := sr.kubernetes.CoreV1().Services(namespace).List(context.Background(), metav1.ListOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("unable to list services: %v", err)
+		}
+		services = sl.Items
+	} else {
+		for _, name := range names {
+			svc, err := sr.kubernetes.CoreV1().Services(namespace).Get(context.Background(), name, metav1.GetOptions{})
+			if err != nil {
+				return nil, fmt.Errorf("unable to get service %s: %v", name, err)
+			}
+			services = append(services, *svc)
+		}
+	}
+
+	ports := make([]resource.ForwardedPort, 0)
+
+	for _, svc := range services {
+		pod, err := sr.readyPod(svc)
+		if err != nil {
+			if len(names) > 0 {
+				return nil, err
+			}
+			logrus.WithField("service", svc.Name).Debug(err.Error())
+			continue
+		}
+
+		for _, p := range svc.Spec.Ports {
+			if p.Protocol != "" && p.Protocol != v1.ProtocolTCP {
+				// only TCP ports can be forwarded
+				continue
+			}
+
+			target, err := targetPort(p, pod)
+			if err != nil {
+				return nil, fmt.Errorf("unable to forward port %d of service %s: %v", p.Port, svc.Name, err)
+			}
+
+			ports = append(ports, resource.ForwardedPort{
+				Service:    svc.Name,
+				Pod:        pod.Name,
+				Port:       p.Port,
+				TargetPort: target,
+			})
+		}
+	}
+
+	return ports, nil
+}
+
+// readyPod returns a ready pod selected by a service, the first one by name
+func (sr *serviceRepository) readyPod(svc v1.Service) (v1.Pod, error) {
+	if len(svc.Spec.Selector) == 0 {
+		return v1.Pod{}, fmt.Errorf("the service %s has no selector", svc.Name)
+	}
+
+	pods, err := sr.kubernetes.CoreV1().Pods(svc.Namespace).List(context.Background(), metav1.ListOptions{
+		LabelSelector: labels.SelectorFromSet(svc.Spec.Selector).String(),
+	})
+	if err != nil {
+		return v1.Pod{}, fmt.Errorf("unable to list pods of service %s: %v", svc.Name, err)
+	}
+
+	sort.Slice(pods.Items, func(i, j int) bool {
+		return pods.Items[i].Name < pods.Items[j].Name
+	})
+
+	for _, pod := range pods.Items {
+		if pod.DeletionTimestamp == nil && isPodReady(pod) {
+			return pod, nil
+		}
+	}
+
+	return v1.Pod{}, fmt.Errorf("the service %s has no ready pod", svc.Name)
+}
+
+// isPodReady returns true if a pod is running and ready
+func isPodReady(pod v1.Pod) bool {
+	if pod.Status.Phase != v1.PodRunning {
+		return false
+	}
+
+	for _, c := range pod.Status.Conditions {
+		if c.Type == v1.PodReady {
+			return c.Status == v1.ConditionTrue
+		}
+	}
+
+	return false
+}
+
+// targetPort returns the port of the pod targeted by a port of a service.
+// A named target port is looked up in the ports of the containers of the pod.
+func targetPort(port v1.ServicePort, pod v1.Pod) (int32, error) {
+	if port.TargetPort.StrVal == "" {
+		if port.TargetPort.IntVal == 0 {
+			return port.Port, nil
+		}
+		return port.TargetPort.IntVal, nil
+	}
+
+	for _, c := range pod.Spec.Containers {
+		for _, cp := range c.Ports {
+			if cp.Name == port.TargetPort.StrVal {
+				return cp.ContainerPort, nil
+			}
+		}
+	}
+
+	return 0, fmt.Errorf("no container port of pod %s is named %s", pod.Name, port.TargetPort.StrVal)
+}
+
+// Forward listens on the local ports of the address, localhost when empty, and forwards their connections to the
+// target ports of their pods, through the port-forward API of the cluster, until the context is done.
+// Once all the ports listen, ready is called with the ports, the local ports chosen by the system being set.
+// An error is returned if a port cannot be listened on, or if the forward of a pod fails.
+func (sr *serviceRepository) Forward(ctx context.Context, namespace, address string, ports []resource.ForwardedPort, ready func([]resource.ForwardedPort)) error {
+	if len(ports) == 0 {
+		return errors.New("no port to forward")
+	}
+	if sr.config == nil {
+		return errors.New("unable to forward ports: no configuration of the cluster")
+	}
+	if address == "" {
+		address = "localhost"
+	}
+
+	// the local ports are set on a copy of the ports
+	ports = append([]resource.ForwardedPort(nil), ports...)
+
+	transport, upgrader, err := spdy.RoundTripperFor(sr.config)
+	if err != nil {
+		return fmt.Errorf("unable to forward ports: %v", err)
+	}
+
+	// the ports of a pod are forwarded by the same forwarder, in the order of the ports
+	var pods []string
+	podPorts := make(map[string][]int)
+	for i, p := range ports {
+		if _, ok := podPorts[p.Pod]; !ok {
+			pods = append(pods, p.Pod)
+		}
+		podPorts[p.Pod] = append(podPorts[p.Pod], i)
+	}
+
+	stop := make(chan struct{})
+	defer close(stop)
+
+	errs := make(chan error, len(pods))
+	forwarders := make([]*portforward.PortForwarder, len(pods))
+	readies := make([]chan struct{}, len(pods))
+
+	for i, pod := range pods {
+		specs := make([]string, 0, len(podPorts[pod]))
+		for _, j := range podPorts[pod] {
+			specs = append(specs, portSpec(ports[j]))
+		}
+
+		url := sr.kubernetes.CoreV1().RESTClient().Post().
+			Resource("pods").
+			Namespace(namespace).
+			Name(pod).
+			SubResource("portforward").
+			URL()
+		dialer := spdy.NewDialer(upgrader, &http.Client{Transport: transport}, http.MethodPost, url)
+
+		readies[i] = make(chan struct{})
+		forwarders[i], err = portforward.NewOnAddresses(dialer, []string{address}, specs, stop, readies[i], ioutil.Discard, ioutil.Discard)
+		if err != nil {
+			return fmt.Errorf("unable to forward ports of pod %s: %v", pod, err)
+		}
+
+		go func(pod string, fw *portforward.PortForwarder) {
+			if err := fw.ForwardPorts(); err != nil {
+				errs <- fmt.Errorf("unable to forward ports of pod %s: %v", pod, err)
+				return
+			}
+			errs <- nil
+		}(pod, forwarders[i])
+	}
+
+	for i, pod := range pods {
+		select {
+		case <-readies[i]:
+		case err := <-errs:
+			return err
+		case <-ctx.Done():
+			return nil
+		}
+
+		forwarded, err := forwarders[i].GetPorts()
+		if err != nil {
+			return fmt.Errorf("unable to forward ports of pod %s: %v", pod, err)
+		}
+		for k, j := range podPorts[pod] {
+			if k < len(forwarded) {
+				ports[j].LocalPort = int32(forwarded[k].Local)
+			}
+		}
+	}
+
+	ready(ports)
+
+	select {
+	case err := <-errs:
+		if err == nil {
+			err = errors.New("the port forward has been closed")
+		}
+		return err
+	case <-ctx.Done():
+		return nil
+	}
+}
+
+// portSpec returns the specification of the port forward API forwarding a local port to a pod port,
+// the local port being chosen by the system when it is 0
+func portSpec(p resource.ForwardedPort) string {
+	if p.LocalPort == 0 {
+		return fmt.Sprintf(":%d", p.TargetPort)
+	}
+
+	return fmt.Sprintf("%d:%d", p.LocalPort, p.TargetPort)
+}
@@ -0,0 +1,81 @@
+package kubernetes_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/DanielPickens/Keeper/pkg/kubernetes"
+	"github.com/DanielPickens/Keeper/pkg/resource"
+)
+
+func newServicePod(name string, ready bool) *v1.Pod {
+	status := v1.ConditionFalse
+	if ready {
+		status = v1.ConditionTrue
+	}
+
+	return &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "test", Labels: map[string]string{"app": "api"}},
+		Spec: v1.PodSpec{Containers: []v1.Container{{
+			Name:  "api",
+			Ports: []v1.ContainerPort{{Name: "http", ContainerPort: 8080}},
+		}}},
+		Status: v1.PodStatus{
+			Phase:      v1.PodRunning,
+			Conditions: []v1.PodCondition{{Type: v1.PodReady, Status: status}},
+		},
+	}
+}
+
+func TestForwardTargets(t *testing.T) {
+	client := fake.NewSimpleClientset(
+		&v1.Service{
+			ObjectMeta: metav1.ObjectMeta{Name: "api", Namespace: "test"},
+			Spec: v1.ServiceSpec{
+				Selector: map[string]string{"app": "api"},
+				Ports: []v1.ServicePort{
+					{Name: "http", Port: 80, TargetPort: intstr.FromString("http")},
+					{Name: "admin", Port: 9090, TargetPort: intstr.FromInt(9091)},
+					{Name: "metrics", Port: 9100},
+					{Name: "dns", Port: 53, Protocol: v1.ProtocolUDP},
+				},
+			},
+		},
+		&v1.Service{
+			ObjectMeta: metav1.ObjectMeta{Name: "external", Namespace: "test"},
+			Spec:       v1.ServiceSpec{Type: v1.ServiceTypeExternalName, ExternalName: "example.com"},
+		},
+		newServicePod("api-a", false),
+		newServicePod("api-b", true),
+	)
+	services := kubernetes.NewServiceRepository(client, nil, "localhost", nil)
+
+	expected := []resource.ForwardedPort{
+		{Service: "api", Pod: "api-b", Port: 80, TargetPort: 8080},
+		{Service: "api", Pod: "api-b", Port: 9090, TargetPort: 9091},
+		{Service: "api", Pod: "api-b", Port: 9100, TargetPort: 9100},
+	}
+
+	ports, err := services.ForwardTargets("test", []string{"api"})
+	assert.NoError(t, err)
+	assert.Equal(t, expected, ports)
+
+	// the services without ready pods are skipped when all the services are forwarded
+	ports, err = services.ForwardTargets("test", nil)
+	assert.NoError(t, err)
+	assert.Equal(t, expected, ports)
+
+	_, err = services.ForwardTargets("test", []string{"external"})
+	assert.Error(t, err)
+
+	_, err = services.ForwardTargets("test", []string{"missing"})
+	assert.Error(t, err)
+
+	assert.Error(t, services.Forward(context.Background(), "test", "", ports, func([]resource.ForwardedPort) {}))
+}
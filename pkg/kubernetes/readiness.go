@@ -0,0 +1,140 @@
+package kubernetes
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/util/jsonpath"
+
+	"github.com/DanielPickens/Keeper/pkg/resource"
+)
+
+// isReady evaluates a readiness rule against an object.
+// When the rule defines a condition type, the object is ready if its condition of this type has the expected status.
+// Otherwise the result of the rule JSONPath is compared to the rule value or to the result of the rule ValuePath.
+func isReady(obj map[string]interface{}, rule resource.ReadinessRule) (bool, error) {
+	if rule.ConditionType != "" {
+		expected := rule.ConditionStatus
+		if expected == "" {
+			expected = "True"
+		}
+
+		for _, cond := range objectConditions(obj) {
+			if cond.Type == rule.ConditionType {
+				return cond.Status == expected, nil
+			}
+		}
+
+		return false, nil
+	}
+
+	left, err := jsonPathValue(obj, rule.JSONPath)
+	if err != nil {
+		return false, err
+	}
+
+	right := rule.Value
+
+	if rule.ValuePath != "" {
+		if right, err = jsonPathValue(obj, rule.ValuePath); err != nil {
+			return false, err
+		}
+	}
+
+	return compare(left, rule.Operator, right)
+}
+
+// jsonPathValue returns the result of a JSONPath evaluated against an object.
+// Missing keys result in an empty string. Braces around the path are optional.
+func jsonPathValue(obj map[string]interface{}, path string) (string, error) {
+	if !strings.HasPrefix(path, "{") {
+		path = fmt.Sprintf("{%s}", path)
+	}
+
+	j := jsonpath.New("readiness")
+	j.AllowMissingKeys(true)
+
+	if err := j.Parse(path); err != nil {
+		return "", fmt.Errorf("invalid jsonpath %s: %v", path, err)
+	}
+
+	buf := bytes.Buffer{}
+	if err := j.Execute(&buf, obj); err != nil {
+		return "", fmt.Errorf("jsonpath %s: %v", path, err)
+	}
+
+	return strings.TrimSpace(buf.String()), nil
+}
+
+// compare compares two values with the given operator.
+// Values are compared as numbers when both of them are numeric, as strings otherwise.
+// Ordering operators are only allowed with numeric values.
+func compare(left, operator, right string) (bool, error) {
+	l, errL := strconv.ParseFloat(left, 64)
+	r, errR := strconv.ParseFloat(right, 64)
+	numeric := errL == nil && errR == nil
+
+	switch operator {
+	case "", "==":
+		if numeric {
+			return l == r, nil
+		}
+		return left == right, nil
+	case "!=":
+		if numeric {
+			return l != r, nil
+		}
+		return left != right, nil
+	}
+
+	if !numeric {
+		// a missing value, such as a status field not yet set, is never ready
+		if left == "" || right == "" {
+			return false, nil
+		}
+		return false, fmt.Errorf("operator %s requires numeric values, got %q and %q", operator, left, right)
+	}
+
+	switch operator {
+	case ">":
+		return l > r, nil
+	case ">=":
+		return l >= r, nil
+	case "<":
+		return l < r, nil
+	case "<=":
+		return l <= r, nil
+	}
+
+	return false, fmt.Errorf("unknown operator %s", operator)
+}
+
+// objectConditions returns the status conditions of an unstructured object
+func objectConditions(obj map[string]interface{}) []resource.Condition {
+	conditions := make([]resource.Condition, 0)
+
+	items, found, err := unstructured.NestedSlice(obj, "status", "conditions")
+	if !found || err != nil {
+		return conditions
+	}
+
+	for _, item := range items {
+		c, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		cond := resource.Condition{}
+		cond.Type, _, _ = unstructured.NestedString(c, "type")
+		cond.Status, _, _ = unstructured.NestedString(c, "status")
+		cond.Reason, _, _ = unstructured.NestedString(c, "reason")
+		cond.Message, _, _ = unstructured.NestedString(c, "message")
+
+		conditions = append(conditions, cond)
+	}
+
+	return conditions
+}
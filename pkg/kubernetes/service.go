@@ -0,0 +1,92 @@
+package kubernetes
+
+import (
+	"context"
+	"fmt"
+
+	"k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+
+	"github.com/DanielPickens/Keeper/pkg/resource"
+)
+
+type serviceRepository struct {
+	kubernetes kubernetes.Interface
+	dynamic    dynamic.Interface
+	host       string
+	config     *rest.Config
+}
+
+// NewServiceRepository returns a new ServiceRepository.
+// The parameters are a go-client kubernetes client, a go-client dynamic client used to get the Gateway API routes,
+// the host of the cluster, used as the address of NodePort services, and the configuration of the client,
+// used to forward the ports of the services.
+func NewServiceRepository(kubernetes kubernetes.Interface, dynamic dynamic.Interface, host string, config *rest.Config) resource.ServiceRepository {
+	return &serviceRepository{
+		kubernetes: kubernetes,
+		dynamic:    dynamic,
+		host:       host,
+		config:     config,
+	}
+}
+
+// ListExposed returns the NodePort and LoadBalancer services of the namespace.
+// LoadBalancer services are reached through their load balancer, on their own ports,
+// NodePort services through the cluster host, on their node ports.
+func (sr *serviceRepository) ListExposed(namespace string) ([]resource.Service, error) {
+	sl, err := sr.kubernetes.CoreV1().Services(namespace).List(context.Background(), metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("unable to list services: %v", err)
+	}
+
+	services := make([]resource.Service, 0)
+
+	for _, svc := range sl.Items {
+		switch svc.Spec.Type {
+		case v1.ServiceTypeLoadBalancer:
+			addr := loadBalancerAddr(svc)
+			if addr == "" {
+				// the load balancer is not provisioned yet, fall back on the node ports
+				services = append(services, nodePortService(svc, sr.host))
+				continue
+			}
+
+			service := resource.Service{Name: svc.Name, Addr: addr}
+			for _, p := range svc.Spec.Ports {
+				service.Ports = append(service.Ports, resource.Port{Port: p.Port, ExposedPort: p.Port})
+			}
+			services = append(services, service)
+		case v1.ServiceTypeNodePort:
+			services = append(services, nodePortService(svc, sr.host))
+		}
+	}
+
+	return services, nil
+}
+
+// nodePortService returns a service reached through the cluster host on its node ports
+func nodePortService(svc v1.Service, host string) resource.Service {
+	service := resource.Service{Name: svc.Name, Addr: host}
+
+	for _, p := range svc.Spec.Ports {
+		service.Ports = append(service.Ports, resource.Port{Port: p.Port, ExposedPort: p.NodePort})
+	}
+
+	return service
+}
+
+// loadBalancerAddr returns the ip or the hostname of the load balancer of a service
+func loadBalancerAddr(svc v1.Service) string {
+	for _, ingress := range svc.Status.LoadBalancer.Ingress {
+		if ingress.IP != "" {
+			return ingress.IP
+		}
+		if ingress.Hostname != "" {
+			return ingress.Hostname
+		}
+	}
+	return ""
+}
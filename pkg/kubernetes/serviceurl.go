@@ -0,0 +1,402 @@
+package kubernetes
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+	"k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/DanielPickens/Keeper/pkg/resource"
+)
+
+// clusterDomain is the domain of the DNS names of the services in the cluster
+const clusterDomain = "cluster.local"
+
+var (
+	httpRouteResource = schema.GroupVersionResource{Group: "gateway.networking.k8s.io", Version: "v1", Resource: "httproutes"}
+	gatewayResource   = schema.GroupVersionResource{Group: "gateway.networking.k8s.io", Version: "v1", Resource: "gateways"}
+)
+
+// ListURLs returns the URLs the services of the namespace can be reached at : the URLs of the ingress rules and of
+// the Gateway API HTTPRoutes targeting the services, the URLs of the load balancers and of the node ports of the
+// services, and the DNS names of the services in the cluster. The URLs are ordered by kind.
+// The HTTPRoutes are ignored when the Gateway API is not installed in the cluster.
+func (sr *serviceRepository) ListURLs(namespace string) (resource.ServiceURLs, error) {
+	sl, err := sr.kubernetes.CoreV1().Services(namespace).List(context.Background(), metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("unable to list services: %v", err)
+	}
+
+	services := make(map[string]v1.Service, len(sl.Items))
+	for _, svc := range sl.Items {
+		services[svc.Name] = svc
+	}
+
+	urls := make(resource.ServiceURLs, 0)
+
+	ingressURLs, err := sr.ingressURLs(namespace, services)
+	if err != nil {
+		return nil, err
+	}
+	urls = append(urls, ingressURLs...)
+
+	routeURLs, err := sr.httpRouteURLs(namespace)
+	if err != nil {
+		return nil, err
+	}
+	urls = append(urls, routeURLs...)
+
+	var clusterURLs resource.ServiceURLs
+
+	for _, svc := range sl.Items {
+		if svc.Spec.Type == v1.ServiceTypeExternalName {
+			continue
+		}
+
+		addr := ""
+		if svc.Spec.Type == v1.ServiceTypeLoadBalancer {
+			addr = loadBalancerAddr(svc)
+		}
+
+		for _, p := range svc.Spec.Ports {
+			switch {
+			case addr != "":
+				urls = append(urls, resource.ServiceURL{
+					Service: svc.Name,
+					Port:    p.Port,
+					Kind:    resource.ServiceURLLoadBalancer,
+					URL:     webURL(portScheme(p.Port), addr, p.Port, ""),
+				})
+			case p.NodePort != 0:
+				// a load balancer that is not provisioned yet is reached through the node ports
+				urls = append(urls, resource.ServiceURL{
+					Service: svc.Name,
+					Port:    p.Port,
+					Kind:    resource.ServiceURLNodePort,
+					URL:     webURL(portScheme(p.Port), sr.host, p.NodePort, ""),
+				})
+			}
+
+			clusterURLs = append(clusterURLs, resource.ServiceURL{
+				Service: svc.Name,
+				Port:    p.Port,
+				Kind:    resource.ServiceURLCluster,
+				URL:     net.JoinHostPort(fmt.Sprintf("%s.%s.svc.%s", svc.Name, namespace, clusterDomain), strconv.Itoa(int(p.Port))),
+			})
+		}
+	}
+
+	return append(urls, clusterURLs...), nil
+}
+
+// ingressURLs returns the URLs of the paths of the ingress rules of the namespace.
+// The rules without host are reached through the address of the ingress, and the hosts covered by a TLS certificate
+// through HTTPS. Wildcard hosts are ignored as they cannot be opened.
+func (sr *serviceRepository) ingressURLs(namespace string, services map[string]v1.Service) (resource.ServiceURLs, error) {
+	il, err := sr.kubernetes.NetworkingV1().Ingresses(namespace).List(context.Background(), metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("unable to list ingresses: %v", err)
+	}
+
+	urls := make(resource.ServiceURLs, 0)
+
+	for _, ing := range il.Items {
+		addr := ""
+		for _, lb := range ing.Status.LoadBalancer.Ingress {
+			if lb.Hostname != "" {
+				addr = lb.Hostname
+			} else if lb.IP != "" {
+				addr = lb.IP
+			}
+			if addr != "" {
+				break
+			}
+		}
+
+		add := func(host, path string, backend *networkingv1.IngressServiceBackend) {
+			if host == "" {
+				host = addr
+			}
+			if host == "" || strings.HasPrefix(host, "*") || backend == nil {
+				return
+			}
+
+			scheme := "http"
+			if ingressTLS(ing, host) {
+				scheme = "https"
+			}
+
+			urls = append(urls, resource.ServiceURL{
+				Service: backend.Name,
+				Port:    backendPort(services[backend.Name], backend.Port),
+				Kind:    resource.ServiceURLIngress,
+				Source:  ing.Name,
+				URL:     webURL(scheme, host, 0, path),
+			})
+		}
+
+		for _, rule := range ing.Spec.Rules {
+			if rule.HTTP == nil {
+				continue
+			}
+			for _, p := range rule.HTTP.Paths {
+				add(rule.Host, p.Path, p.Backend.Service)
+			}
+		}
+
+		if ing.Spec.DefaultBackend != nil {
+			add("", "", ing.Spec.DefaultBackend.Service)
+		}
+	}
+
+	return urls, nil
+}
+
+// ingressTLS returns true if a host of an ingress is covered by one of its TLS certificates.
+// A certificate without hosts covers all the hosts of the ingress.
+func ingressTLS(ing networkingv1.Ingress, host string) bool {
+	for _, tls := range ing.Spec.TLS {
+		if len(tls.Hosts) == 0 {
+			return true
+		}
+		for _, h := range tls.Hosts {
+			if h == host {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// backendPort returns the port of the service targeted by an ingress backend, looked up by name if needed
+func backendPort(svc v1.Service, port networkingv1.ServiceBackendPort) int32 {
+	if port.Number != 0 {
+		return port.Number
+	}
+
+	for _, p := range svc.Spec.Ports {
+		if p.Name == port.Name {
+			return p.Port
+		}
+	}
+
+	return 0
+}
+
+// gatewayListener is the scheme, the host and the port of a listener of a gateway
+type gatewayListener struct {
+	scheme string
+	host   string
+	port   int32
+}
+
+// httpRouteURLs returns the URLs of the path matches of the Gateway API HTTPRoutes of the namespace, through the
+// listeners of their parent gateways. The routes without hostnames are reached through the hostname of the listener,
+// or through the address of the gateway.
+func (sr *serviceRepository) httpRouteURLs(namespace string) (resource.ServiceURLs, error) {
+	urls := make(resource.ServiceURLs, 0)
+
+	if sr.dynamic == nil {
+		return urls, nil
+	}
+
+	rl, err := sr.dynamic.Resource(httpRouteResource).Namespace(namespace).List(context.Background(), metav1.ListOptions{})
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			// the Gateway API is not installed
+			return urls, nil
+		}
+		if apierrors.IsForbidden(err) {
+			// the other URLs of the services are still listed
+			logrus.Warnf("unable to list the httproutes of namespace %s: %v", namespace, err)
+			return urls, nil
+		}
+		return nil, fmt.Errorf("unable to list httproutes: %v", err)
+	}
+
+	for _, route := range rl.Items {
+		hostnames, _, _ := unstructured.NestedStringSlice(route.Object, "spec", "hostnames")
+		listeners := sr.routeListeners(route)
+
+		rules, _, _ := unstructured.NestedSlice(route.Object, "spec", "rules")
+		for _, r := range rules {
+			rule, ok := r.(map[string]interface{})
+			if !ok {
+				continue
+			}
+
+			paths := routePaths(rule)
+			backends, _, _ := unstructured.NestedSlice(rule, "backendRefs")
+
+			for _, b := range backends {
+				backend, ok := b.(map[string]interface{})
+				if !ok {
+					continue
+				}
+				if kind, _, _ := unstructured.NestedString(backend, "kind"); kind != "" && kind != "Service" {
+					continue
+				}
+				name, _, _ := unstructured.NestedString(backend, "name")
+				port, _, _ := unstructured.NestedInt64(backend, "port")
+
+				for _, l := range listeners {
+					hosts := hostnames
+					if len(hosts) == 0 {
+						hosts = []string{l.host}
+					}
+
+					for _, host := range hosts {
+						if host == "" || strings.HasPrefix(host, "*") {
+							continue
+						}
+						for _, path := range paths {
+							urls = append(urls, resource.ServiceURL{
+								Service: name,
+								Port:    int32(port),
+								Kind:    resource.ServiceURLHTTPRoute,
+								Source:  route.GetName(),
+								URL:     webURL(l.scheme, host, l.port, path),
+							})
+						}
+					}
+				}
+			}
+		}
+	}
+
+	return urls, nil
+}
+
+// routePaths returns the paths matched by a rule of an HTTPRoute, / when it matches all the paths.
+// A match without path, such as a header match, matches the / prefix.
+func routePaths(rule map[string]interface{}) []string {
+	var paths []string
+	seen := make(map[string]bool)
+
+	matches, _, _ := unstructured.NestedSlice(rule, "matches")
+	for _, m := range matches {
+		match, ok := m.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if typ, _, _ := unstructured.NestedString(match, "path", "type"); typ == "RegularExpression" {
+			// a regular expression cannot be opened
+			continue
+		}
+		path, _, _ := unstructured.NestedString(match, "path", "value")
+		if path == "" {
+			path = "/"
+		}
+		if !seen[path] {
+			seen[path] = true
+			paths = append(paths, path)
+		}
+	}
+
+	if len(matches) == 0 {
+		paths = append(paths, "/")
+	}
+
+	return paths
+}
+
+// routeListeners returns the HTTP and HTTPS listeners of the parent gateways of an HTTPRoute. When a gateway cannot
+// be found, the route is assumed to be reached through HTTP on the default port.
+func (sr *serviceRepository) routeListeners(route unstructured.Unstructured) []gatewayListener {
+	var listeners []gatewayListener
+
+	parents, _, _ := unstructured.NestedSlice(route.Object, "spec", "parentRefs")
+	for _, p := range parents {
+		parent, ok := p.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if kind, _, _ := unstructured.NestedString(parent, "kind"); kind != "" && kind != "Gateway" {
+			continue
+		}
+
+		name, _, _ := unstructured.NestedString(parent, "name")
+		namespace, _, _ := unstructured.NestedString(parent, "namespace")
+		if namespace == "" {
+			namespace = route.GetNamespace()
+		}
+		section, _, _ := unstructured.NestedString(parent, "sectionName")
+
+		gw, err := sr.dynamic.Resource(gatewayResource).Namespace(namespace).Get(context.Background(), name, metav1.GetOptions{})
+		if err != nil {
+			listeners = append(listeners, gatewayListener{scheme: "http"})
+			continue
+		}
+
+		addr := ""
+		addresses, _, _ := unstructured.NestedSlice(gw.Object, "status", "addresses")
+		if len(addresses) > 0 {
+			if a, ok := addresses[0].(map[string]interface{}); ok {
+				addr, _, _ = unstructured.NestedString(a, "value")
+			}
+		}
+
+		specs, _, _ := unstructured.NestedSlice(gw.Object, "spec", "listeners")
+		for _, s := range specs {
+			spec, ok := s.(map[string]interface{})
+			if !ok {
+				continue
+			}
+
+			listenerName, _, _ := unstructured.NestedString(spec, "name")
+			if section != "" && section != listenerName {
+				continue
+			}
+
+			protocol, _, _ := unstructured.NestedString(spec, "protocol")
+			if protocol != "HTTP" && protocol != "HTTPS" {
+				continue
+			}
+
+			host, _, _ := unstructured.NestedString(spec, "hostname")
+			if host == "" {
+				host = addr
+			}
+			port, _, _ := unstructured.NestedInt64(spec, "port")
+
+			listeners = append(listeners, gatewayListener{
+				scheme: strings.ToLower(protocol),
+				host:   host,
+				port:   int32(port),
+			})
+		}
+	}
+
+	return listeners
+}
+
+// portScheme returns the scheme of the URLs of a port : https for the port 443, http otherwise
+func portScheme(port int32) string {
+	if port == 443 {
+		return "https"
+	}
+
+	return "http"
+}
+
+// webURL returns the URL of a path of a host, the port being omitted when it is 0 or the default one of the scheme
+func webURL(scheme, host string, port int32, path string) string {
+	if port != 0 && !(scheme == "http" && port == 80) && !(scheme == "https" && port == 443) {
+		host = net.JoinHostPort(host, strconv.Itoa(int(port)))
+	}
+	if !strings.HasPrefix(path, "/") {
+		path = "/" + path
+	}
+
+	return fmt.Sprintf("%s://%s%s", scheme, host, path)
+}
@@ -0,0 +1,156 @@
+package kubernetes_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+
+	"github.com/DanielPickens/Keeper/pkg/kubernetes"
+	"github.com/DanielPickens/Keeper/pkg/resource"
+)
+
+func newGatewayObject(kind, name string, spec, status map[string]interface{}) *unstructured.Unstructured {
+	obj := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "gateway.networking.k8s.io/v1",
+		"kind":       kind,
+		"metadata":   map[string]interface{}{"name": name, "namespace": "test"},
+		"spec":       spec,
+	}}
+	if status != nil {
+		obj.Object["status"] = status
+	}
+
+	return obj
+}
+
+func TestListURLs(t *testing.T) {
+	client := fake.NewSimpleClientset(
+		&v1.Service{
+			ObjectMeta: metav1.ObjectMeta{Name: "api", Namespace: "test"},
+			Spec:       v1.ServiceSpec{Ports: []v1.ServicePort{{Name: "http", Port: 80}}},
+		},
+		&v1.Service{
+			ObjectMeta: metav1.ObjectMeta{Name: "front", Namespace: "test"},
+			Spec:       v1.ServiceSpec{Type: v1.ServiceTypeLoadBalancer, Ports: []v1.ServicePort{{Port: 8080, NodePort: 30080}}},
+			Status: v1.ServiceStatus{LoadBalancer: v1.LoadBalancerStatus{
+				Ingress: []v1.LoadBalancerIngress{{IP: "10.0.0.1"}},
+			}},
+		},
+		&v1.Service{
+			ObjectMeta: metav1.ObjectMeta{Name: "admin", Namespace: "test"},
+			Spec:       v1.ServiceSpec{Type: v1.ServiceTypeNodePort, Ports: []v1.ServicePort{{Port: 443, NodePort: 30443}}},
+		},
+		&networkingv1.Ingress{
+			ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "test"},
+			Spec: networkingv1.IngressSpec{
+				TLS: []networkingv1.IngressTLS{{Hosts: []string{"api.example.com"}}},
+				Rules: []networkingv1.IngressRule{
+					{
+						Host: "api.example.com",
+						IngressRuleValue: networkingv1.IngressRuleValue{HTTP: &networkingv1.HTTPIngressRuleValue{
+							Paths: []networkingv1.HTTPIngressPath{{
+								Path:    "/v1",
+								Backend: networkingv1.IngressBackend{Service: &networkingv1.IngressServiceBackend{Name: "api", Port: networkingv1.ServiceBackendPort{Name: "http"}}},
+							}},
+						}},
+					},
+					{
+						IngressRuleValue: networkingv1.IngressRuleValue{HTTP: &networkingv1.HTTPIngressRuleValue{
+							Paths: []networkingv1.HTTPIngressPath{{
+								Backend: networkingv1.IngressBackend{Service: &networkingv1.IngressServiceBackend{Name: "front", Port: networkingv1.ServiceBackendPort{Number: 8080}}},
+							}},
+						}},
+					},
+					{
+						Host: "*.example.com",
+						IngressRuleValue: networkingv1.IngressRuleValue{HTTP: &networkingv1.HTTPIngressRuleValue{
+							Paths: []networkingv1.HTTPIngressPath{{
+								Backend: networkingv1.IngressBackend{Service: &networkingv1.IngressServiceBackend{Name: "front", Port: networkingv1.ServiceBackendPort{Number: 8080}}},
+							}},
+						}},
+					},
+				},
+			},
+			Status: networkingv1.IngressStatus{LoadBalancer: networkingv1.IngressLoadBalancerStatus{
+				Ingress: []networkingv1.IngressLoadBalancerIngress{{Hostname: "lb.example.com"}},
+			}},
+		},
+	)
+
+	dynamic := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(
+		runtime.NewScheme(),
+		map[schema.GroupVersionResource]string{
+			{Group: "gateway.networking.k8s.io", Version: "v1", Resource: "httproutes"}: "HTTPRouteList",
+		},
+		newGatewayObject("HTTPRoute", "admin", map[string]interface{}{
+			"parentRefs": []interface{}{map[string]interface{}{"name": "gateway"}},
+			"rules": []interface{}{
+				map[string]interface{}{
+					"matches":     []interface{}{map[string]interface{}{"path": map[string]interface{}{"type": "PathPrefix", "value": "/admin"}}},
+					"backendRefs": []interface{}{map[string]interface{}{"name": "admin", "port": int64(443)}},
+				},
+				map[string]interface{}{
+					// a match without path matches the / prefix
+					"matches":     []interface{}{map[string]interface{}{"headers": []interface{}{map[string]interface{}{"name": "x-admin", "value": "true"}}}},
+					"backendRefs": []interface{}{map[string]interface{}{"name": "admin", "port": int64(443)}},
+				},
+			},
+		}, nil),
+	)
+
+	// the fake tracker guesses a wrong resource for the Gateway kind
+	_, err := dynamic.Resource(schema.GroupVersionResource{Group: "gateway.networking.k8s.io", Version: "v1", Resource: "gateways"}).
+		Namespace("test").
+		Create(context.Background(), newGatewayObject("Gateway", "gateway", map[string]interface{}{
+			"listeners": []interface{}{
+				map[string]interface{}{"name": "https", "protocol": "HTTPS", "port": int64(8443)},
+				map[string]interface{}{"name": "tcp", "protocol": "TCP", "port": int64(5432)},
+			},
+		}, map[string]interface{}{
+			"addresses": []interface{}{map[string]interface{}{"value": "gw.example.com"}},
+		}), metav1.CreateOptions{})
+	assert.NoError(t, err)
+
+	urls, err := kubernetes.NewServiceRepository(client, dynamic, "cluster.example.com", nil).ListURLs("test")
+
+	assert.NoError(t, err)
+	assert.Equal(t, resource.ServiceURLs{
+		{Service: "api", Port: 80, Kind: resource.ServiceURLIngress, Source: "web", URL: "https://api.example.com/v1"},
+		{Service: "front", Port: 8080, Kind: resource.ServiceURLIngress, Source: "web", URL: "http://lb.example.com/"},
+		{Service: "admin", Port: 443, Kind: resource.ServiceURLHTTPRoute, Source: "admin", URL: "https://gw.example.com:8443/admin"},
+		{Service: "admin", Port: 443, Kind: resource.ServiceURLHTTPRoute, Source: "admin", URL: "https://gw.example.com:8443/"},
+		{Service: "admin", Port: 443, Kind: resource.ServiceURLNodePort, URL: "https://cluster.example.com:30443/"},
+		{Service: "front", Port: 8080, Kind: resource.ServiceURLLoadBalancer, URL: "http://10.0.0.1:8080/"},
+		{Service: "admin", Port: 443, Kind: resource.ServiceURLCluster, URL: "admin.test.svc.cluster.local:443"},
+		{Service: "api", Port: 80, Kind: resource.ServiceURLCluster, URL: "api.test.svc.cluster.local:80"},
+		{Service: "front", Port: 8080, Kind: resource.ServiceURLCluster, URL: "front.test.svc.cluster.local:8080"},
+	}, urls)
+
+	primary, ok := urls.Primary()
+	assert.True(t, ok)
+	assert.Equal(t, "https://api.example.com/v1", primary.URL)
+
+	// the routes are skipped when they cannot be listed
+	dynamic.PrependReactor("list", "httproutes", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		return true, nil, apierrors.NewForbidden(schema.GroupResource{Group: "gateway.networking.k8s.io", Resource: "httproutes"}, "", nil)
+	})
+
+	urls, err = kubernetes.NewServiceRepository(client, dynamic, "cluster.example.com", nil).ListURLs("test")
+
+	assert.NoError(t, err)
+	assert.Len(t, urls, 7)
+	for _, u := range urls {
+		assert.NotEqual(t, resource.ServiceURLHTTPRoute, u.Kind)
+	}
+}
@@ -0,0 +1,67 @@
+package kubernetes
+
+import (
+	"context"
+	"fmt"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/DanielPickens/Keeper/pkg/resource"
+)
+
+type statefulsetRepository struct {
+	kubernetes kubernetes.Interface
+}
+
+// NewStatefulsetRepository returns a new StatefulsetRepository.
+// The parameter is a go-client kubernetes client.
+func NewStatefulsetRepository(kubernetes kubernetes.Interface) resource.StatefulsetRepository {
+	return &statefulsetRepository{
+		kubernetes: kubernetes,
+	}
+}
+
+// List returns the statefulsets of the given namespace with their number of ready replicas.
+func (c *statefulsetRepository) List(namespace string) (resource.Statefulsets, error) {
+	sl, err := c.kubernetes.AppsV1().StatefulSets(namespace).List(context.Background(), metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("unable to list statefulsets: %v", err)
+	}
+
+	statefulsets := make(resource.Statefulsets, 0)
+
+	for _, sf := range sl.Items {
+		desired := int32(1)
+		if sf.Spec.Replicas != nil {
+			desired = *sf.Spec.Replicas
+		}
+
+		status := resource.StatefulsetNotReady
+
+		if sf.Status.ReadyReplicas == desired {
+			status = resource.StatefulsetReady
+		}
+
+		conditions := make([]resource.Condition, 0, len(sf.Status.Conditions))
+		for _, cond := range sf.Status.Conditions {
+			conditions = append(conditions, resource.Condition{
+				Type:    string(cond.Type),
+				Status:  string(cond.Status),
+				Reason:  cond.Reason,
+				Message: cond.Message,
+			})
+		}
+
+		statefulsets = append(statefulsets, resource.Statefulset{
+			Name:       sf.Name,
+			Status:     status,
+			Ready:      sf.Status.ReadyReplicas,
+			Desired:    desired,
+			Conditions: conditions,
+			Containers: containers(sf.Spec.Template.Spec),
+		})
+	}
+
+	return statefulsets, nil
+}
@@ -0,0 +1,185 @@
+package kubernetes
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	apiresource "k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/DanielPickens/Keeper/pkg/resource"
+)
+
+// podMetricsResource is the resource of the pod metrics served by the metrics server
+var podMetricsResource = schema.GroupVersionResource{Group: "metrics.k8s.io", Version: "v1beta1", Resource: "pods"}
+
+type usageRepository struct {
+	kubernetes kubernetes.Interface
+	dynamic    dynamic.Interface
+}
+
+// NewUsageRepository returns a new UsageRepository.
+// The parameters are a go-client kubernetes client and a dynamic client, used to query the metrics API.
+func NewUsageRepository(kubernetes kubernetes.Interface, dynamic dynamic.Interface) resource.UsageRepository {
+	return &usageRepository{
+		kubernetes: kubernetes,
+		dynamic:    dynamic,
+	}
+}
+
+// Top returns the CPU and the memory used by each pod of the namespace, as reported by the metrics API, with the
+// resources the pod requests. It returns an ErrorMetricsUnavailable when the metrics API is not served.
+func (ur *usageRepository) Top(namespace string) ([]resource.PodUsage, error) {
+	ml, err := ur.dynamic.Resource(podMetricsResource).Namespace(namespace).List(context.Background(), metav1.ListOptions{})
+	if err != nil {
+		if apierrors.IsNotFound(err) || apierrors.IsServiceUnavailable(err) {
+			return nil, resource.ErrorMetricsUnavailable{Err: err}
+		}
+		return nil, fmt.Errorf("unable to list pod metrics: %v", err)
+	}
+
+	pl, err := ur.kubernetes.CoreV1().Pods(namespace).List(context.Background(), metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("unable to list pods: %v", err)
+	}
+
+	requests := make(map[string]v1.ResourceList, len(pl.Items))
+	for _, pod := range pl.Items {
+		requests[pod.Name] = podRequests(pod.Spec)
+	}
+
+	usages := make([]resource.PodUsage, 0, len(ml.Items))
+
+	for _, m := range ml.Items {
+		usage := resource.PodUsage{Name: m.GetName()}
+
+		containers, _, _ := unstructured.NestedSlice(m.Object, "containers")
+		for _, c := range containers {
+			container, ok := c.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			cpu, _, _ := unstructured.NestedString(container, "usage", "cpu")
+			memory, _, _ := unstructured.NestedString(container, "usage", "memory")
+
+			cpuUsage, memoryUsage := parseQuantity(cpu), parseQuantity(memory)
+			usage.CPU += cpuUsage.MilliValue()
+			usage.Memory += memoryUsage.Value()
+		}
+
+		if r, ok := requests[usage.Name]; ok {
+			usage.CPURequest = r.Cpu().MilliValue()
+			usage.MemoryRequest = r.Memory().Value()
+		}
+
+		usages = append(usages, usage)
+	}
+
+	sort.Slice(usages, func(i, j int) bool { return usages[i].Name < usages[j].Name })
+
+	return usages, nil
+}
+
+// Requests returns the labels of the namespace and the resources requested by its pods that are not terminated and
+// by its persistent volume claims
+func (ur *usageRepository) Requests(namespace string) (resource.ResourceRequests, error) {
+	requests := resource.ResourceRequests{Namespace: namespace}
+
+	ns, err := ur.kubernetes.CoreV1().Namespaces().Get(context.Background(), namespace, metav1.GetOptions{})
+	if err != nil {
+		return requests, fmt.Errorf("unable to get namespace %s: %v", namespace, err)
+	}
+	requests.Labels = ns.Labels
+
+	pl, err := ur.kubernetes.CoreV1().Pods(namespace).List(context.Background(), metav1.ListOptions{})
+	if err != nil {
+		return requests, fmt.Errorf("unable to list pods: %v", err)
+	}
+
+	for _, pod := range pl.Items {
+		if pod.Status.Phase == v1.PodSucceeded || pod.Status.Phase == v1.PodFailed {
+			continue
+		}
+		r := podRequests(pod.Spec)
+		requests.CPU += r.Cpu().MilliValue()
+		requests.Memory += r.Memory().Value()
+	}
+
+	pvcs, err := ur.kubernetes.CoreV1().PersistentVolumeClaims(namespace).List(context.Background(), metav1.ListOptions{})
+	if err != nil {
+		return requests, fmt.Errorf("unable to list persistent volume claims: %v", err)
+	}
+
+	for _, pvc := range pvcs.Items {
+		requests.Storage += pvc.Spec.Resources.Requests.Storage().Value()
+	}
+
+	return requests, nil
+}
+
+// podRequests returns the resources requested by a pod, as the scheduler computes them : the requests of its
+// containers and of its sidecars added up, or the requests of its largest init container when they are larger,
+// plus the overhead of the pod
+func podRequests(spec v1.PodSpec) v1.ResourceList {
+	requests := v1.ResourceList{}
+	for _, c := range spec.Containers {
+		addResources(requests, c.Resources.Requests)
+	}
+
+	sidecars := v1.ResourceList{}
+	initMax := v1.ResourceList{}
+
+	for _, c := range spec.InitContainers {
+		if c.RestartPolicy != nil && *c.RestartPolicy == v1.ContainerRestartPolicyAlways {
+			addResources(sidecars, c.Resources.Requests)
+			continue
+		}
+
+		// an init container runs along with the sidecars started before it
+		init := v1.ResourceList{}
+		addResources(init, c.Resources.Requests)
+		addResources(init, sidecars)
+		maxResources(initMax, init)
+	}
+
+	addResources(requests, sidecars)
+	maxResources(requests, initMax)
+	addResources(requests, spec.Overhead)
+
+	return requests
+}
+
+// addResources adds the quantities of resources to a list of resources
+func addResources(list, resources v1.ResourceList) {
+	for name, q := range resources {
+		sum := list[name]
+		sum.Add(q)
+		list[name] = sum
+	}
+}
+
+// maxResources sets the quantities of a list of resources to the ones of resources when they are larger
+func maxResources(list, resources v1.ResourceList) {
+	for name, q := range resources {
+		if current, ok := list[name]; !ok || q.Cmp(current) > 0 {
+			list[name] = q.DeepCopy()
+		}
+	}
+}
+
+// parseQuantity parses a quantity reported by the metrics API, zero when it is invalid
+func parseQuantity(value string) apiresource.Quantity {
+	q, err := apiresource.ParseQuantity(value)
+	if err != nil {
+		return apiresource.Quantity{}
+	}
+
+	return q
+}
@@ -0,0 +1,120 @@
+package kubernetes_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/api/core/v1"
+	apiresource "k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/DanielPickens/Keeper/pkg/kubernetes"
+	"github.com/DanielPickens/Keeper/pkg/resource"
+)
+
+var podMetricsResource = schema.GroupVersionResource{Group: "metrics.k8s.io", Version: "v1beta1", Resource: "pods"}
+
+func newRequestsPod(name string, phase v1.PodPhase, cpu, memory string) *v1.Pod {
+	return &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "test"},
+		Spec: v1.PodSpec{Containers: []v1.Container{{
+			Name: name,
+			Resources: v1.ResourceRequirements{Requests: v1.ResourceList{
+				v1.ResourceCPU:    apiresource.MustParse(cpu),
+				v1.ResourceMemory: apiresource.MustParse(memory),
+			}},
+		}}},
+		Status: v1.PodStatus{Phase: phase},
+	}
+}
+
+func newPodMetrics(name string, usages map[string][2]string) *unstructured.Unstructured {
+	containers := make([]interface{}, 0, len(usages))
+	for container, usage := range usages {
+		containers = append(containers, map[string]interface{}{
+			"name":  container,
+			"usage": map[string]interface{}{"cpu": usage[0], "memory": usage[1]},
+		})
+	}
+
+	return &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "metrics.k8s.io/v1beta1",
+		"kind":       "PodMetrics",
+		"metadata":   map[string]interface{}{"name": name, "namespace": "test"},
+		"containers": containers,
+	}}
+}
+
+func TestTop(t *testing.T) {
+	client := fake.NewSimpleClientset(
+		newRequestsPod("api", v1.PodRunning, "250m", "256Mi"),
+		newRequestsPod("worker", v1.PodRunning, "1", "1Gi"),
+	)
+	dynamic := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), map[schema.GroupVersionResource]string{
+		podMetricsResource: "PodMetricsList",
+	})
+
+	// the fake tracker guesses a wrong resource for the PodMetrics kind
+	for _, m := range []*unstructured.Unstructured{
+		newPodMetrics("worker", map[string][2]string{"worker": {"812345678n", "700Mi"}}),
+		newPodMetrics("api", map[string][2]string{"api": {"120m", "200Mi"}, "proxy": {"5m", "30Mi"}}),
+	} {
+		_, err := dynamic.Resource(podMetricsResource).Namespace("test").Create(context.Background(), m, metav1.CreateOptions{})
+		assert.NoError(t, err)
+	}
+
+	usages, err := kubernetes.NewUsageRepository(client, dynamic).Top("test")
+
+	assert.NoError(t, err)
+	assert.Equal(t, []resource.PodUsage{
+		{Name: "api", CPU: 125, Memory: 230 * 1024 * 1024, CPURequest: 250, MemoryRequest: 256 * 1024 * 1024},
+		{Name: "worker", CPU: 813, Memory: 700 * 1024 * 1024, CPURequest: 1000, MemoryRequest: 1024 * 1024 * 1024},
+	}, usages)
+}
+
+func TestRequests(t *testing.T) {
+	always := v1.ContainerRestartPolicyAlways
+
+	migrate := newRequestsPod("migrate", v1.PodSucceeded, "2", "4Gi")
+	sidecar := newRequestsPod("api", v1.PodRunning, "500m", "512Mi")
+	sidecar.Spec.InitContainers = []v1.Container{
+		{Name: "proxy", RestartPolicy: &always, Resources: v1.ResourceRequirements{Requests: v1.ResourceList{
+			v1.ResourceCPU:    apiresource.MustParse("100m"),
+			v1.ResourceMemory: apiresource.MustParse("128Mi"),
+		}}},
+		{Name: "init", Resources: v1.ResourceRequirements{Requests: v1.ResourceList{
+			v1.ResourceCPU: apiresource.MustParse("1"),
+		}}},
+	}
+
+	client := fake.NewSimpleClientset(
+		&v1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "test", Labels: map[string]string{"owner": "payments"}}},
+		sidecar,
+		newRequestsPod("worker", v1.PodPending, "250m", "256Mi"),
+		migrate,
+		&v1.PersistentVolumeClaim{
+			ObjectMeta: metav1.ObjectMeta{Name: "data", Namespace: "test"},
+			Spec: v1.PersistentVolumeClaimSpec{Resources: v1.VolumeResourceRequirements{Requests: v1.ResourceList{
+				v1.ResourceStorage: apiresource.MustParse("10Gi"),
+			}}},
+		},
+	)
+
+	requests, err := kubernetes.NewUsageRepository(client, nil).Requests("test")
+
+	assert.NoError(t, err)
+	assert.Equal(t, resource.ResourceRequests{
+		Namespace: "test",
+		Labels:    map[string]string{"owner": "payments"},
+		// the init container requests more CPU than the containers, along with the sidecar started before it
+		CPU:     1100 + 250,
+		Memory:  (512 + 128 + 256) * 1024 * 1024,
+		Storage: 10 * 1024 * 1024 * 1024,
+	}, requests)
+}
@@ -1,6 +1,8 @@
 package mock
 
 import (
+	"context"
+
 	"github.com/DanielPickens/Keeper/pkg/resource"
 	"k8s.io/client-go/kubernetes"
 )
@@ -27,6 +29,12 @@ func (ns *namespaceRepository) Create(namespace string) error {
 
 	return nil
 }
+
+// Adopt does nothing
+func (ns *namespaceRepository) Adopt(namespace string) error {
+	return nil
+}
+
 //Get provides resource property for API requests to be sent to namespace, return a resource if active, as "active", else return nil
 func (ns *namespaceRepository) Get(namespace string) (*resource.Namespace, error) {
 	return &resource.Namespace{Name: namespace, Phase: "Active", Status: 100}, nil
@@ -53,6 +61,47 @@ func (ns *namespaceRepository) List() ([]resource.Namespace, error) {
 }
 
 // ApplyConfig loads configuration files into kubernetes
-func (ns *namespaceRepository) ApplyConfig(namespace, configPath string) error {
+func (ns *namespaceRepository) ApplyConfig(namespace, configPath string, opts resource.ApplyOptions) ([]resource.ApplyResult, error) {
+	return nil, nil
+}
+
+// DiffConfig returns no change
+func (ns *namespaceRepository) DiffConfig(namespace, configPath string, opts resource.ApplyOptions) ([]resource.ApplyResult, error) {
+	return nil, nil
+}
+
+// RunHooks runs no hook
+func (ns *namespaceRepository) RunHooks(namespace, configPath string, hook resource.Hook) ([]resource.ApplyResult, error) {
+	return nil, nil
+}
+
+// RerunJob does nothing and returns the name of the job
+func (ns *namespaceRepository) RerunJob(ctx context.Context, namespace, configPath, name string) (string, error) {
+	return name, nil
+}
+
+// Watch sends no event
+func (ns *namespaceRepository) Watch(events chan<- resource.NamespaceEvent) error {
+	return nil
+}
+
+// WatchWorkloads blocks until the context is done
+func (ns *namespaceRepository) WatchWorkloads(ctx context.Context, namespace string, changes chan<- struct{}) error {
+	<-ctx.Done()
+	return nil
+}
+
+// WaitDeleted returns immediately as namespaces are deleted at once
+func (ns *namespaceRepository) WaitDeleted(ctx context.Context, namespace string) error {
+	return nil
+}
+
+// GetBlockers returns no blocker
+func (ns *namespaceRepository) GetBlockers(namespace string) (*resource.NamespaceBlockers, error) {
+	return &resource.NamespaceBlockers{Namespace: namespace}, nil
+}
+
+// ClearFinalizers does nothing
+func (ns *namespaceRepository) ClearFinalizers(blockers *resource.NamespaceBlockers) error {
 	return nil
 }
@@ -3,21 +3,24 @@ package playbook
 import (
 	"bytes"
 	"errors"
+	"fmt"
 	"time"
 )
 
 // Config represents a set of kubernetes configuration.
 // Usually, Values are expected to be yaml.
 type Config struct {
-	Name   string
-	Values string
+	Name   string `json:"name"`
+	Values string `json:"values"`
 }
 
 // Release represents information related to an inventory release.
 // An inventory may evolve with time. We want to keep trace of those evolution
-// and we may inject data specific a release in the templates
+// and we may inject data specific a release in the templates.
+// Revision is the number of the release in the history of the namespace, 0 for configs not released yet.
 type Release struct {
-	Date string `json:"date"`
+	Date     string `json:"date"`
+	Revision int    `json:"revision"`
 }
 
 // InventoryRelease represents an inventory enriched with release data.
@@ -27,16 +30,35 @@ type InventoryRelease struct {
 	Release   Release                `json:"release"`
 }
 
+// newInventoryRelease creates the InventoryRelease of an inventory, applied to the playbook templates
+func newInventoryRelease(inv Inventory, revision int) InventoryRelease {
+	return InventoryRelease{
+		inv.Namespace,
+		inv.Values,
+		Release{
+			Date:     time.Now().Format("2023012100000"),
+			Revision: revision,
+		},
+	}
+}
+
 // ConfigService define the way configuration are managed
 type ConfigService interface {
 	Generate(Inventory) error
+	Render(inv Inventory, revision int) ([]Config, error)
+	Release(inv Inventory, revision int) ([]Config, error)
+	Restore(namespace string, configs []Config) error
+	Get(namespace string) ([]Config, error)
 	Delete(namespace string) error
+	List() ([]string, error)
 }
 
 // ConfigRepository represents the service that implements configs management
 type ConfigRepository interface {
 	Save(namespace string, configs []Config) error
+	Get(namespace string) ([]Config, error)
 	Delete(namespace string) error
+	List() ([]string, error)
 }
 
 type configService struct {
@@ -55,24 +77,26 @@ func NewConfigService(configs ConfigRepository, playbooks PlaybookService) Confi
 // Generate creates a set of kubernetes configurations by applying an InventoryRelease to
 // Templates. It reads each template, creates an InventoryRelease for the given Inventory
 // and applies it to the template in order to generate a set of kubernetes configurations.
+// The configs are not released yet : their revision is 0.
 func (cs *configService) Generate(inv Inventory) error {
+	_, err := cs.Release(inv, 0)
+	return err
+}
+
+// Render generates the kubernetes configurations of the given revision of the inventory and returns them,
+// without saving them.
+func (cs *configService) Render(inv Inventory, revision int) ([]Config, error) {
 
 	if inv.Namespace == "" {
-		return errors.New("an namespace must be specified in the inventory")
+		return nil, errors.New("an namespace must be specified in the inventory")
 	}
 
 	tpls, err := cs.playbooks.GetTemplate()
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	invRelease := InventoryRelease{
-		inv.Namespace,
-		inv.Values,
-		Release{
-			Date: time.Now().Format("2023012100000"),
-		},
-	}
+	invRelease := newInventoryRelease(inv, revision)
 
 	var configs []Config
 
@@ -80,7 +104,9 @@ func (cs *configService) Generate(inv Inventory) error {
 
 		confVal := bytes.Buffer{}
 
-		tpl.Template.Execute(&confVal, invRelease)
+		if err := tpl.Template.Execute(&confVal, invRelease); err != nil {
+			return nil, fmt.Errorf("unable to render config %s: %v", tpl.Name, err)
+		}
 
 		conf := Config{
 			Name:   tpl.Name,
@@ -90,10 +116,40 @@ func (cs *configService) Generate(inv Inventory) error {
 		configs = append(configs, conf)
 	}
 
-	return cs.configs.Save(inv.Namespace, configs)
+	return configs, nil
+}
+
+// Release generates the kubernetes configurations of the given revision of the inventory, saves them
+// and returns them.
+func (cs *configService) Release(inv Inventory, revision int) ([]Config, error) {
+	configs, err := cs.Render(inv, revision)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := cs.configs.Save(inv.Namespace, configs); err != nil {
+		return nil, err
+	}
+
+	return configs, nil
+}
+
+// Restore replaces the kubernetes configs of the given namespace by previously released ones
+func (cs *configService) Restore(namespace string, configs []Config) error {
+	return cs.configs.Save(namespace, configs)
+}
+
+// Get returns the kubernetes configs rendered for the given namespace
+func (cs *configService) Get(namespace string) ([]Config, error) {
+	return cs.configs.Get(namespace)
 }
 
 // Delete deletes kubernetes configs for the given namespace.
 func (cs *configService) Delete(namespace string) error {
 	return cs.configs.Delete(namespace)
 }
+
+// List returns the namespaces having generated kubernetes configs
+func (cs *configService) List() ([]string, error) {
+	return cs.configs.List()
+}
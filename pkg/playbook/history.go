@@ -0,0 +1,146 @@
+package playbook
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// ReleaseStatus represents the outcome of the apply of a release
+type ReleaseStatus string
+
+// Release statuses
+const (
+	ReleaseDeployed ReleaseStatus = "deployed"
+	ReleaseFailed   ReleaseStatus = "failed"
+)
+
+// ReleaseRecord represents a release of a namespace kept in its history.
+// It holds a snapshot of the inventory and of the rendered configs, so that the namespace can be rolled back to it.
+// Revision numbers start at 1 and increase with each apply of the namespace.
+// RollbackOf is the revision whose inventory and configs have been applied again, when the release is a rollback.
+type ReleaseRecord struct {
+	Namespace       string        `json:"namespace"`
+	Revision        int           `json:"revision"`
+	Date            time.Time     `json:"date"`
+	Actor           string        `json:"actor,omitempty"`
+	PlaybookVersion string        `json:"playbookVersion,omitempty"`
+	Status          ReleaseStatus `json:"status"`
+	Error           string        `json:"error,omitempty"`
+	RollbackOf      int           `json:"rollbackOf,omitempty"`
+	Inventory       Inventory     `json:"inventory"`
+	Configs         []Config      `json:"configs"`
+}
+
+// HistoryService defines the way the release history of namespaces is managed
+type HistoryService interface {
+	List(namespace string) ([]ReleaseRecord, error)
+	Get(namespace string, revision int) (ReleaseRecord, error)
+	NextRevision(namespace string) (int, error)
+	LastDeployed(namespace string, before int) (ReleaseRecord, error)
+	Save(record ReleaseRecord) error
+	Delete(namespace string) error
+}
+
+// HistoryRepository defines the way the release history is actually stored
+type HistoryRepository interface {
+	List(namespace string) ([]ReleaseRecord, error)
+	Get(namespace string, revision int) (ReleaseRecord, error)
+	Save(record ReleaseRecord) error
+	Delete(namespace string) error
+}
+
+type historyService struct {
+	history HistoryRepository
+}
+
+// NewHistoryService creates a HistoryService
+func NewHistoryService(history HistoryRepository) HistoryService {
+	return &historyService{
+		history,
+	}
+}
+
+// List returns the releases of the namespace, ordered by revision
+func (hs *historyService) List(namespace string) ([]ReleaseRecord, error) {
+	records, err := hs.history.List(namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(records, func(i, j int) bool {
+		return records[i].Revision < records[j].Revision
+	})
+
+	return records, nil
+}
+
+// Get returns the given release of the namespace.
+// An ErrorReleaseNotFound is returned if the namespace has no such revision.
+func (hs *historyService) Get(namespace string, revision int) (ReleaseRecord, error) {
+	return hs.history.Get(namespace, revision)
+}
+
+// NextRevision returns the revision number of the next release of the namespace
+func (hs *historyService) NextRevision(namespace string) (int, error) {
+	records, err := hs.List(namespace)
+	if err != nil {
+		return 0, err
+	}
+
+	if len(records) == 0 {
+		return 1, nil
+	}
+
+	return records[len(records)-1].Revision + 1, nil
+}
+
+// LastDeployed returns the last successfully deployed release of the namespace older than the given revision.
+// An ErrorReleaseNotFound is returned if there is none.
+func (hs *historyService) LastDeployed(namespace string, before int) (ReleaseRecord, error) {
+	records, err := hs.List(namespace)
+	if err != nil {
+		return ReleaseRecord{}, err
+	}
+
+	for i := len(records) - 1; i >= 0; i-- {
+		if records[i].Revision < before && records[i].Status == ReleaseDeployed {
+			return records[i], nil
+		}
+	}
+
+	return ReleaseRecord{}, NewErrorReleaseNotFound(namespace, 0)
+}
+
+// Save records a release of a namespace, replacing the release with the same revision if any
+func (hs *historyService) Save(record ReleaseRecord) error {
+	if record.Namespace == "" || record.Revision < 1 {
+		return fmt.Errorf("a release must have a namespace and a positive revision")
+	}
+
+	return hs.history.Save(record)
+}
+
+// Delete deletes the release history of the namespace
+func (hs *historyService) Delete(namespace string) error {
+	return hs.history.Delete(namespace)
+}
+
+// ErrorReleaseNotFound represents an error due to a missing release in the history of a namespace
+type ErrorReleaseNotFound struct {
+	msg string
+}
+
+// Error returns the error message
+func (err ErrorReleaseNotFound) Error() string {
+	return err.msg
+}
+
+// NewErrorReleaseNotFound creates a new ErrorReleaseNotFound error.
+// A revision of 0 means that no previous deployed release has been found.
+func NewErrorReleaseNotFound(namespace string, revision int) ErrorReleaseNotFound {
+	if revision == 0 {
+		return ErrorReleaseNotFound{fmt.Sprintf("No previous deployed release found for %s.", namespace)}
+	}
+	return ErrorReleaseNotFound{fmt.Sprintf("The release %d of %s does not exist.", revision, namespace)}
+}
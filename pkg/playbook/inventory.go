@@ -7,8 +7,10 @@ import (
 // Inventory represents a set of variables to apply to the config templates.
 // Namespace is the namespace dedicated files where to apply the variables contained within templates into Values
 // Values is map of string that contains whatever the user set in the default inventory from a playbook
+// Context is the kubeconfig context of the cluster hosting the namespace. The current context is used when it is empty.
 type Inventory struct {
 	Namespace string                 `json:"namespace"`
+	Context   string                 `json:"context,omitempty"`
 	Values    map[string]interface{} `json:"values"`
 }
 
@@ -112,6 +114,11 @@ func (is *inventoryService) Reset(namespace string) (Inventory, error) {
 	inv.Namespace = namespace
 	inv.Values = def.Values
 
+	// the namespace stays on its cluster
+	if current, err := is.inventories.Get(namespace); err == nil {
+		inv.Context = current.Context
+	}
+
 	if err := is.inventories.Update(namespace, inv); err != nil {
 		return Inventory{}, err
 	}
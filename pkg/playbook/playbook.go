@@ -1,43 +1,70 @@
-package playbook 
+package playbook
 
 import (
 	"text/template"
 )
 
-func Configtemplate struct {
-	Name string
+// ConfigTemplate represents a template of kubernetes configuration from a playbook.
+type ConfigTemplate struct {
+	Name     string
 	Template *template.Template
-
 }
 
-//PlaybookService represents the way playbooks are managed
+// PlaybookService represents the way playbooks are managed
 type PlaybookService interface {
 	GetDefault() (Inventory, error)
-	GetTemplate() ([]Configtemplate, error)
+	GetTemplate() ([]ConfigTemplate, error)
+	GetSettings() (Settings, error)
+	GetSmokeTests() ([]ConfigTemplate, error)
 }
 
+// PlaybookRepository represents the way playbooks are actually read
 type PlaybookRepository interface {
 	GetDefault() (Inventory, error)
-	GetTemplate() ([]Configtemplate, error)
+	GetTemplate() ([]ConfigTemplate, error)
+	GetSettings() (Settings, error)
+	GetSmokeTests() ([]ConfigTemplate, error)
 }
 
-type playbookService structr {
+type playbookService struct {
 	playbooks PlaybookRepository
 }
 
-//newPlaybookService returns a new PlaybookService
-func newPlaybookService(playbooks PlaybookRepository) playbookService {
-	return &playbookService {
-		playbooks: playbooks, 
+// NewPlaybookService returns a new PlaybookService
+func NewPlaybookService(playbooks PlaybookRepository) PlaybookService {
+	return &playbookService{
+		playbooks: playbooks,
 	}
 }
 
-//GetTemplate returns the templates for a playbook
-func (ps *playbookService) GetTemplate() ([]Configtemplate, error) {
+// GetTemplate returns the templates for a playbook
+func (ps *playbookService) GetTemplate() ([]ConfigTemplate, error) {
 	return ps.playbooks.GetTemplate()
 }
 
-//GetDefault returns the default inventory for a playbook
+// GetDefault returns the default inventory for a playbook
 func (ps *playbookService) GetDefault() (Inventory, error) {
 	return ps.playbooks.GetDefault()
-}
\ No newline at end of file
+}
+
+// GetSmokeTests returns the templates of the smoke tests of a playbook
+func (ps *playbookService) GetSmokeTests() ([]ConfigTemplate, error) {
+	return ps.playbooks.GetSmokeTests()
+}
+
+// GetSettings returns the settings of a playbook.
+// An error is returned if a readiness rule is invalid.
+func (ps *playbookService) GetSettings() (Settings, error) {
+	settings, err := ps.playbooks.GetSettings()
+	if err != nil {
+		return Settings{}, err
+	}
+
+	for _, rule := range settings.Readiness.Rules {
+		if err := rule.Validate(); err != nil {
+			return Settings{}, NewErrorReadingSettingsFile(err)
+		}
+	}
+
+	return settings, nil
+}
@@ -0,0 +1,106 @@
+package playbook
+
+import (
+	"strings"
+)
+
+// DetectedWorkload represents a workload running in a namespace, used to seed the inventory of an adopted namespace
+type DetectedWorkload struct {
+	Name       string
+	Replicas   int32
+	Containers []DetectedContainer
+}
+
+// DetectedContainer represents a container of a running workload and its image
+type DetectedContainer struct {
+	Name  string
+	Image string
+}
+
+// SeedValues returns a copy of the values of an inventory where the values describing a running workload are
+// replaced by the detected ones. A map of values describes a workload, or one of its containers, when its key is
+// the name of the workload or of the container. In such a map :
+//   - "replicas" is replaced by the number of replicas of the workload,
+//   - "tag" is replaced by the tag of the image of the container (the first one for a workload),
+//   - "image" is replaced by the image of the container, without its tag if the map has a "tag" value.
+//
+// Values that do not match a running workload are kept as is.
+func SeedValues(values map[string]interface{}, workloads []DetectedWorkload) map[string]interface{} {
+	byName := make(map[string]DetectedWorkload)
+	images := make(map[string]string)
+
+	for _, w := range workloads {
+		byName[w.Name] = w
+		for _, c := range w.Containers {
+			if _, ok := images[c.Name]; !ok {
+				images[c.Name] = c.Image
+			}
+		}
+	}
+
+	return seed(values, byName, images)
+}
+
+func seed(values map[string]interface{}, workloads map[string]DetectedWorkload, images map[string]string) map[string]interface{} {
+	seeded := make(map[string]interface{}, len(values))
+
+	for k, v := range values {
+		m, ok := v.(map[string]interface{})
+		if !ok {
+			seeded[k] = v
+			continue
+		}
+
+		m = seed(m, workloads, images)
+
+		if w, ok := workloads[k]; ok {
+			if _, ok := m["replicas"]; ok {
+				m["replicas"] = float64(w.Replicas)
+			}
+			if len(w.Containers) > 0 {
+				seedImage(m, w.Containers[0].Image)
+			}
+		} else if image, ok := images[k]; ok {
+			seedImage(m, image)
+		}
+
+		seeded[k] = m
+	}
+
+	return seeded
+}
+
+// seedImage sets the "image" and "tag" values of a map, if it has them
+func seedImage(values map[string]interface{}, image string) {
+	repository, tag := splitImage(image)
+
+	_, hasTag := values["tag"]
+
+	if hasTag && tag != "" {
+		values["tag"] = tag
+	}
+
+	if _, ok := values["image"]; ok {
+		if hasTag && tag != "" {
+			values["image"] = repository
+		} else {
+			values["image"] = image
+		}
+	}
+}
+
+// splitImage splits an image reference into its repository and its tag.
+// The tag is empty for images referenced by digest or without tag.
+func splitImage(image string) (string, string) {
+	if strings.Contains(image, "@") {
+		return image, ""
+	}
+
+	i := strings.LastIndex(image, ":")
+	if i < 0 || strings.Contains(image[i:], "/") {
+		// no tag, the colon is the one of a registry port
+		return image, ""
+	}
+
+	return image[:i], image[i+1:]
+}
@@ -0,0 +1,104 @@
+package playbook
+
+import (
+	"fmt"
+)
+
+// Settings represents the playbook settings, defined in an optional settings.json file at the root of the playbook.
+// Version is the version of the playbook, recorded in the release history of the namespaces.
+type Settings struct {
+	Version   string            `json:"version"`
+	Readiness ReadinessSettings `json:"readiness"`
+	Prune     PruneSettings     `json:"prune"`
+}
+
+// PruneSettings defines which objects are pruned when they are removed from the playbook.
+// Kinds is the allowlist of kinds that may be pruned, such as {"group": "apps", "version": "v1", "kind": "Deployment"}.
+// A default list of common namespaced kinds is used when it is empty.
+type PruneSettings struct {
+	Kinds []PruneKind `json:"kinds"`
+}
+
+// PruneKind represents a kind of objects that may be pruned
+type PruneKind struct {
+	Group   string `json:"group"`
+	Version string `json:"version"`
+	Kind    string `json:"kind"`
+}
+
+// ReadinessSettings defines how the readiness of a namespace is computed.
+// Ignore is the list of kinds excluded from the namespace status, such as "DaemonSet",
+// "PersistentVolumeClaim", "Ingress" or "CronJob".
+// Rules declares how the readiness of custom resources deployed by the playbook is evaluated.
+type ReadinessSettings struct {
+	Ignore []string        `json:"ignore"`
+	Rules  []ReadinessRule `json:"rules"`
+}
+
+// ReadinessRule defines how the readiness of the objects of a given GroupVersionKind is evaluated.
+//
+// Using a condition, an object is ready when its status condition of the given type has the given status ("True" by default) :
+//
+//	{"group": "argoproj.io", "version": "v1alpha1", "kind": "Rollout", "condition": {"type": "Available"}}
+//
+// Using a JSONPath, an object is ready when the result of the path matches a value or the result of another path :
+//
+//	{"group": "kafka.strimzi.io", "version": "v1beta2", "kind": "KafkaTopic",
+//	 "jsonPath": "{.status.conditions[?(@.type==\"Ready\")].status}", "value": "True"}
+//	{"group": "acid.zalan.do", "version": "v1", "kind": "postgresql",
+//	 "jsonPath": "{.status.readyReplicas}", "operator": ">=", "valuePath": "{.spec.numberOfInstances}"}
+type ReadinessRule struct {
+	Group     string         `json:"group"`
+	Version   string         `json:"version"`
+	Kind      string         `json:"kind"`
+	Condition *ConditionRule `json:"condition,omitempty"`
+	JSONPath  string         `json:"jsonPath,omitempty"`
+	Operator  string         `json:"operator,omitempty"`
+	Value     string         `json:"value,omitempty"`
+	ValuePath string         `json:"valuePath,omitempty"`
+}
+
+// ConditionRule represents an expected status condition of an object.
+type ConditionRule struct {
+	Type   string `json:"type"`
+	Status string `json:"status,omitempty"`
+}
+
+// operators are the comparison operators allowed in a readiness rule
+var operators = map[string]bool{"": true, "==": true, "!=": true, ">": true, ">=": true, "<": true, "<=": true}
+
+// Validate returns an error if the readiness rule is incomplete.
+func (r ReadinessRule) Validate() error {
+	if r.Version == "" || r.Kind == "" {
+		return fmt.Errorf("readiness rule %s/%s: version and kind are required", r.Group, r.Kind)
+	}
+
+	if r.Condition == nil && r.JSONPath == "" {
+		return fmt.Errorf("readiness rule %s/%s: a condition or a jsonPath is required", r.Group, r.Kind)
+	}
+
+	if r.Condition != nil && r.Condition.Type == "" {
+		return fmt.Errorf("readiness rule %s/%s: the condition type is required", r.Group, r.Kind)
+	}
+
+	if !operators[r.Operator] {
+		return fmt.Errorf("readiness rule %s/%s: unknown operator %s", r.Group, r.Kind, r.Operator)
+	}
+
+	return nil
+}
+
+// ErrorReadingSettingsFile represents an error due to an unreadable settings file
+type ErrorReadingSettingsFile struct {
+	msg string
+}
+
+// Error returns the error message
+func (err ErrorReadingSettingsFile) Error() string {
+	return err.msg
+}
+
+// NewErrorReadingSettingsFile creates an ErrorReadingSettingsFile error
+func NewErrorReadingSettingsFile(err error) ErrorReadingSettingsFile {
+	return ErrorReadingSettingsFile{fmt.Sprintf("Error when reading settings file : %s", err.Error())}
+}
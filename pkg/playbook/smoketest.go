@@ -0,0 +1,97 @@
+package playbook
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// probesFile is the name of the rendered smoke test holding HTTP probes.
+// Every other smoke test is a Job manifest.
+const probesFile = "probes.json"
+
+// Probe represents an HTTP smoke test against a service exposed by the namespace.
+// The probe succeeds once the service answers on Path with ExpectedStatus (200 by default),
+// and with a body containing Contains if set, before TimeoutSeconds (30 by default).
+// Port is the service port to probe. The first port of the service is used if not set.
+type Probe struct {
+	Name           string `json:"name"`
+	Service        string `json:"service"`
+	Port           int32  `json:"port,omitempty"`
+	Path           string `json:"path,omitempty"`
+	ExpectedStatus int    `json:"expectedStatus,omitempty"`
+	Contains       string `json:"contains,omitempty"`
+	TimeoutSeconds int    `json:"timeoutSeconds,omitempty"`
+}
+
+// SmokeTests represents the smoke tests of a playbook rendered for an inventory :
+// test Jobs to run in the namespace and HTTP probes against its exposed services.
+type SmokeTests struct {
+	Jobs   []Config
+	Probes []Probe
+}
+
+// Empty returns true if there is no smoke test
+func (st SmokeTests) Empty() bool {
+	return len(st.Jobs) == 0 && len(st.Probes) == 0
+}
+
+// SmokeTestService defines the way smoke tests are managed
+type SmokeTestService interface {
+	Render(Inventory) (SmokeTests, error)
+}
+
+type smokeTestService struct {
+	playbooks PlaybookService
+}
+
+// NewSmokeTestService creates a SmokeTestService
+func NewSmokeTestService(playbooks PlaybookService) SmokeTestService {
+	return &smokeTestService{
+		playbooks: playbooks,
+	}
+}
+
+// Render applies an InventoryRelease of the given Inventory to the smoke tests templates of the playbook.
+// The template rendered as probes.json holds a list of HTTP probes, every other template renders a Job.
+func (sts *smokeTestService) Render(inv Inventory) (SmokeTests, error) {
+	var tests SmokeTests
+
+	tpls, err := sts.playbooks.GetSmokeTests()
+	if err != nil {
+		return tests, err
+	}
+
+	invRelease := newInventoryRelease(inv, 0)
+
+	for _, tpl := range tpls {
+		value := bytes.Buffer{}
+
+		if err := tpl.Template.Execute(&value, invRelease); err != nil {
+			return SmokeTests{}, fmt.Errorf("unable to render smoke test %s: %v", tpl.Name, err)
+		}
+
+		if tpl.Name != probesFile {
+			if strings.TrimSpace(value.String()) != "" {
+				tests.Jobs = append(tests.Jobs, Config{Name: tpl.Name, Values: value.String()})
+			}
+			continue
+		}
+
+		var probes []Probe
+		if err := json.Unmarshal(value.Bytes(), &probes); err != nil {
+			return SmokeTests{}, fmt.Errorf("unable to read smoke test %s: %v", tpl.Name, err)
+		}
+
+		for _, p := range probes {
+			if p.Name == "" || p.Service == "" {
+				return SmokeTests{}, fmt.Errorf("unable to read smoke test %s: a probe must have a name and a service", tpl.Name)
+			}
+		}
+
+		tests.Probes = append(tests.Probes, probes...)
+	}
+
+	return tests, nil
+}
@@ -0,0 +1,104 @@
+package resource
+
+import (
+	"fmt"
+)
+
+// ApplyAction represents what the apply of an object did
+type ApplyAction string
+
+// Results of the apply of an object
+const (
+	ApplyCreated    ApplyAction = "created"
+	ApplyConfigured ApplyAction = "configured"
+	ApplyUnchanged  ApplyAction = "unchanged"
+	ApplyPruned     ApplyAction = "pruned"
+	ApplyFailed     ApplyAction = "failed"
+)
+
+// Hook represents the moment a hook of the configs of a namespace is run.
+// Hooks are jobs annotated with keeper.io/hook, run apart from the other objects and waited for until completion.
+type Hook string
+
+// Hooks of the configs
+const (
+	// HookPreApply jobs are run before the other objects are applied, a failed one aborts the apply
+	HookPreApply Hook = "pre-apply"
+	// HookPostApply jobs are run once the other objects are applied
+	HookPostApply Hook = "post-apply"
+	// HookPreDelete jobs are run before the namespace is deleted, a failed one aborts the deletion
+	HookPreDelete Hook = "pre-delete"
+)
+
+// ApplyOptions defines how the configs of a namespace are applied.
+// With Prune, the objects previously applied to the namespace that are no longer part of its configs are deleted,
+// provided their kind is one of PruneKinds. Nothing is pruned if an object could not be applied.
+type ApplyOptions struct {
+	Prune      bool
+	PruneKinds []PruneKind
+}
+
+// PruneKind represents a kind of objects that may be pruned, such as apps/v1 Deployment
+type PruneKind struct {
+	Group   string
+	Version string
+	Kind    string
+}
+
+// DefaultPruneKinds are the kinds of objects pruned when the playbook does not define its own list.
+// Persistent volume claims are left out as pruning them deletes their data : the playbook has to list them explicitly.
+var DefaultPruneKinds = []PruneKind{
+	{Version: "v1", Kind: "ConfigMap"},
+	{Version: "v1", Kind: "Secret"},
+	{Version: "v1", Kind: "Service"},
+	{Version: "v1", Kind: "ServiceAccount"},
+	{Group: "apps", Version: "v1", Kind: "Deployment"},
+	{Group: "apps", Version: "v1", Kind: "StatefulSet"},
+	{Group: "apps", Version: "v1", Kind: "DaemonSet"},
+	{Group: "batch", Version: "v1", Kind: "Job"},
+	{Group: "batch", Version: "v1", Kind: "CronJob"},
+	{Group: "networking.k8s.io", Version: "v1", Kind: "Ingress"},
+	{Group: "networking.k8s.io", Version: "v1", Kind: "NetworkPolicy"},
+	{Group: "rbac.authorization.k8s.io", Version: "v1", Kind: "Role"},
+	{Group: "rbac.authorization.k8s.io", Version: "v1", Kind: "RoleBinding"},
+	{Group: "autoscaling", Version: "v2", Kind: "HorizontalPodAutoscaler"},
+	{Group: "policy", Version: "v1", Kind: "PodDisruptionBudget"},
+}
+
+// ApplyResult represents the result of the apply, or of the pruning, of an object of the configs of a namespace.
+// Error is the error returned by the api server when the apply failed.
+// Diff is the unified diff between the live and the applied object, or the pruned one, for dry-runs only.
+// Hook is set when the object is a hook job.
+type ApplyResult struct {
+	Kind      string      `json:"kind"`
+	Name      string      `json:"name"`
+	Namespace string      `json:"namespace,omitempty"`
+	Action    ApplyAction `json:"action"`
+	Error     string      `json:"error,omitempty"`
+	Diff      string      `json:"diff,omitempty"`
+	Hook      Hook        `json:"hook,omitempty"`
+}
+
+// ErrorApplyFailed represents an error due to objects of the configs of a namespace that could not be applied
+type ErrorApplyFailed struct {
+	Namespace string
+	Results   []ApplyResult
+}
+
+// Error returns the error message
+func (err ErrorApplyFailed) Error() string {
+	msg := fmt.Sprintf("the namespace %s could not be configured", err.Namespace)
+
+	for _, r := range err.Results {
+		if r.Action != ApplyFailed {
+			continue
+		}
+		if r.Hook != "" {
+			msg = fmt.Sprintf("%s\n- %s %s (%s hook): %s", msg, r.Kind, r.Name, r.Hook, r.Error)
+		} else {
+			msg = fmt.Sprintf("%s\n- %s %s: %s", msg, r.Kind, r.Name, r.Error)
+		}
+	}
+
+	return msg
+}
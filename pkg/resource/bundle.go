@@ -0,0 +1,59 @@
+package resource
+
+import (
+	"context"
+	"regexp"
+)
+
+// Redacted replaces the values of secrets in support bundles
+const Redacted = "REDACTED"
+
+// SensitiveKey matches the keys, or the names of environment variables, whose values are redacted from support bundles
+var SensitiveKey = regexp.MustCompile(`(?i)(password|passwd|secret|token|credential|api_?key|private)`)
+
+// BundleFile represents a file of a support bundle, such as the YAML of an object or the logs of a container
+type BundleFile struct {
+	Path    string
+	Content []byte
+}
+
+// BundleOptions defines what is collected in a support bundle.
+// TailLines is the number of the last log lines collected for each container.
+type BundleOptions struct {
+	TailLines int64
+}
+
+// BundleService defines the way the troubleshooting data of a namespace is collected
+type BundleService interface {
+	Collect(ctx context.Context, namespace string, opts BundleOptions) ([]BundleFile, error)
+	RedactConfig(content []byte) ([]byte, error)
+}
+
+// BundleRepository defines the way the troubleshooting data of a namespace is actually collected from Kubernetes
+type BundleRepository interface {
+	Collect(ctx context.Context, namespace string, opts BundleOptions) ([]BundleFile, error)
+	RedactConfig(content []byte) ([]byte, error)
+}
+
+type bundleService struct {
+	bundles BundleRepository
+}
+
+// NewBundleService creates a BundleService
+func NewBundleService(bundles BundleRepository) BundleService {
+	return &bundleService{
+		bundles: bundles,
+	}
+}
+
+// Collect returns the namespace, its events, the objects of its workloads with their secrets and their sensitive
+// environment variables redacted, and the description and the last log lines of its pods
+func (bs *bundleService) Collect(ctx context.Context, namespace string, opts BundleOptions) ([]BundleFile, error) {
+	return bs.bundles.Collect(ctx, namespace, opts)
+}
+
+// RedactConfig returns the kubernetes objects of a config with the values of their secrets and of the sensitive
+// environment variables of their containers redacted
+func (bs *bundleService) RedactConfig(content []byte) ([]byte, error) {
+	return bs.bundles.RedactConfig(content)
+}
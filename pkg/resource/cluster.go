@@ -18,15 +18,19 @@ func NewClusterService(client ClusterRepository) ClusterService {
 	}
 }
 
+// Version represents the version of the kubernetes client compiled in keeper and the version of the api server.
+// APIGroups are the group versions served by the api server, such as "batch/v1" or "networking.k8s.io/v1".
 type Version struct {
-	ClientVersion struct {
-		Major string `json:"major"`
-		Minor string `json:"minor"`
-	} `json:"clientVersion"`
-	ServerVersion struct {
-		Major string `json:"major"`
-		Minor string `json:"minor"`
-	} `json:"serverVersion"`
+	ClientVersion VersionInfo `json:"clientVersion"`
+	ServerVersion VersionInfo `json:"serverVersion"`
+	APIGroups     []string    `json:"apiGroups"`
+}
+
+// VersionInfo represents a kubernetes version, such as 1.20 for v1.20.4
+type VersionInfo struct {
+	Major      string `json:"major"`
+	Minor      string `json:"minor"`
+	GitVersion string `json:"gitVersion"`
 }
 
 func (cs *clusterService) GetVersion() (*Version, error) {
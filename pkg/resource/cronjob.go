@@ -0,0 +1,98 @@
+package resource
+
+import "time"
+
+// CronJob represents a Kubernetes cronjob.
+// A cronjob is ready when it has never been scheduled yet, when it is currently running
+// or when its last scheduled run succeeded.
+type CronJob struct {
+	Name               string
+	Status             CronJobStatus
+	Schedule           string
+	Suspended          bool
+	Active             int
+	LastScheduleTime   *time.Time
+	LastSuccessfulTime *time.Time
+}
+
+// CronJobs represents a list of cronjobs
+type CronJobs []CronJob
+
+// CronJobStatus represents the readiness of a cronjob
+type CronJobStatus string
+
+// CronJob statuses
+const (
+	CronJobReady    CronJobStatus = "Ready"
+	CronJobNotReady CronJobStatus = "NotReady"
+)
+
+// CronJobService defines the way cronjobs are managed
+type CronJobService interface {
+	List(namespace string) (CronJobs, error)
+	Suspend(namespace, name string, suspend bool) error
+	Trigger(namespace, name string) (string, error)
+}
+
+// CronJobRepository defines the way cronjobs are actually managed on Kubernetes
+type CronJobRepository interface {
+	List(namespace string) (CronJobs, error)
+	Suspend(namespace, name string, suspend bool) error
+	Trigger(namespace, name string) (string, error)
+}
+
+type cronJobService struct {
+	cronjobs CronJobRepository
+}
+
+// NewCronJobService creates a CronJobService
+func NewCronJobService(cronjobs CronJobRepository) CronJobService {
+	return &cronJobService{
+		cronjobs: cronjobs,
+	}
+}
+
+// List returns the cronjobs of the namespace
+func (cs *cronJobService) List(namespace string) (CronJobs, error) {
+	return cs.cronjobs.List(namespace)
+}
+
+// Suspend suspends the scheduling of a cronjob, or resumes it when suspend is false
+func (cs *cronJobService) Suspend(namespace, name string, suspend bool) error {
+	return cs.cronjobs.Suspend(namespace, name, suspend)
+}
+
+// Trigger creates a job from the template of a cronjob, and returns the name of the job
+func (cs *cronJobService) Trigger(namespace, name string) (string, error) {
+	return cs.cronjobs.Trigger(namespace, name)
+}
+
+// workloads returns the readiness of each cronjob
+func (cjs CronJobs) workloads() []WorkloadStatus {
+	workloads := make([]WorkloadStatus, 0, len(cjs))
+
+	for _, cj := range cjs {
+		w := WorkloadStatus{
+			Kind:       KindCronJob,
+			Name:       cj.Name,
+			Desired:    1,
+			IsReady:    cj.Status == CronJobReady,
+			Conditions: []Condition{},
+		}
+
+		if w.IsReady {
+			w.Ready = 1
+		} else {
+			w.Conditions = append(w.Conditions, Condition{
+				Type:    "LastRunSucceeded",
+				Status:  "False",
+				Reason:  "LastRunFailed",
+				Message: "the last scheduled run did not succeed",
+			})
+		}
+
+		workloads = append(workloads, w)
+	}
+
+	return workloads
+}
@@ -0,0 +1,71 @@
+package resource
+
+// ReadinessRule defines how the readiness of a custom resource is evaluated.
+// A rule targets every object of the given Group, Version and Kind in a namespace.
+//
+// If ConditionType is set, an object is ready when its status condition of this type
+// has the status ConditionStatus ("True" by default).
+//
+// Otherwise, JSONPath is evaluated against the object and compared, using Operator
+// ("==" by default, "!=", ">", ">=", "<" or "<="), either to Value or to the result of ValuePath.
+type ReadinessRule struct {
+	Group           string
+	Version         string
+	Kind            string
+	ConditionType   string
+	ConditionStatus string
+	JSONPath        string
+	Operator        string
+	Value           string
+	ValuePath       string
+}
+
+// CustomResource represents an object of a custom resource definition and its readiness
+// as evaluated with a ReadinessRule.
+type CustomResource struct {
+	Kind       string
+	Name       string
+	Status     CustomResourceStatus
+	Conditions []Condition
+}
+
+// CustomResources represents a list of custom resources
+type CustomResources []CustomResource
+
+// CustomResourceStatus represents the readiness of a custom resource
+type CustomResourceStatus string
+
+// Custom resource statuses
+const (
+	CustomResourceReady    CustomResourceStatus = "Ready"
+	CustomResourceNotReady CustomResourceStatus = "NotReady"
+)
+
+// CustomResourceRepository defines the way custom resources are actually retrieved from Kubernetes.
+// List returns the objects of the namespace targeted by the rule, with their readiness evaluated using the rule.
+type CustomResourceRepository interface {
+	List(namespace string, rule ReadinessRule) (CustomResources, error)
+}
+
+// workloads returns the readiness of each custom resource
+func (crs CustomResources) workloads() []WorkloadStatus {
+	workloads := make([]WorkloadStatus, 0, len(crs))
+
+	for _, cr := range crs {
+		w := WorkloadStatus{
+			Kind:       cr.Kind,
+			Name:       cr.Name,
+			Desired:    1,
+			IsReady:    cr.Status == CustomResourceReady,
+			Conditions: cr.Conditions,
+		}
+
+		if w.IsReady {
+			w.Ready = 1
+		}
+
+		workloads = append(workloads, w)
+	}
+
+	return workloads
+}
@@ -0,0 +1,48 @@
+package resource
+
+// Daemonset represents a Kubernetes daemonset and its readiness.
+// Desired is the number of nodes that should run the daemon pod and Ready the number of nodes where it is ready.
+type Daemonset struct {
+	Name       string
+	Status     DaemonsetStatus
+	Ready      int32
+	Desired    int32
+	Conditions []Condition
+	Containers []Container
+}
+
+// Daemonsets represents a list of daemonsets
+type Daemonsets []Daemonset
+
+// DaemonsetStatus represents the readiness of a daemonset
+type DaemonsetStatus string
+
+// Daemonset statuses
+const (
+	DaemonsetReady    DaemonsetStatus = "Ready"
+	DaemonsetNotReady DaemonsetStatus = "NotReady"
+)
+
+// DaemonsetRepository defines the way daemonsets are actually retrieved from Kubernetes
+type DaemonsetRepository interface {
+	List(namespace string) (Daemonsets, error)
+}
+
+// workloads returns the readiness of each daemonset
+func (dss Daemonsets) workloads() []WorkloadStatus {
+	workloads := make([]WorkloadStatus, 0, len(dss))
+
+	for _, ds := range dss {
+		workloads = append(workloads, WorkloadStatus{
+			Kind:       KindDaemonset,
+			Name:       ds.Name,
+			Ready:      ds.Ready,
+			Desired:    ds.Desired,
+			IsReady:    ds.Status == DaemonsetReady,
+			Conditions: ds.Conditions,
+			Containers: ds.Containers,
+		})
+	}
+
+	return workloads
+}
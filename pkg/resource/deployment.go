@@ -0,0 +1,47 @@
+package resource
+
+// Deployment represents a Kubernetes deployment and its readiness.
+type Deployment struct {
+	Name       string
+	Status     DeploymentStatus
+	Ready      int32
+	Desired    int32
+	Conditions []Condition
+	Containers []Container
+}
+
+// Deployments represents a list of deployments
+type Deployments []Deployment
+
+// DeploymentStatus represents the readiness of a deployment
+type DeploymentStatus string
+
+// Deployment statuses
+const (
+	DeploymentReady    DeploymentStatus = "Ready"
+	DeploymentNotReady DeploymentStatus = "NotReady"
+)
+
+// DeploymentRepository defines the way deployments are actually retrieved from Kubernetes
+type DeploymentRepository interface {
+	List(namespace string) (Deployments, error)
+}
+
+// workloads returns the readiness of each deployment
+func (dps Deployments) workloads() []WorkloadStatus {
+	workloads := make([]WorkloadStatus, 0, len(dps))
+
+	for _, dp := range dps {
+		workloads = append(workloads, WorkloadStatus{
+			Kind:       KindDeployment,
+			Name:       dp.Name,
+			Ready:      dp.Ready,
+			Desired:    dp.Desired,
+			IsReady:    dp.Status == DeploymentReady,
+			Conditions: dp.Conditions,
+			Containers: dp.Containers,
+		})
+	}
+
+	return workloads
+}
@@ -0,0 +1,82 @@
+package resource
+
+import (
+	"context"
+	"time"
+)
+
+// Event types reported by kubernetes
+const (
+	EventNormal  = "Normal"
+	EventWarning = "Warning"
+)
+
+// Event represents a kubernetes event of a namespace, such as a failed scheduling or a back-off restarting a container.
+// Kind and Name identify the object the event is about. Count is the number of times the event occurred between
+// FirstSeen and LastSeen.
+type Event struct {
+	Type      string    `json:"type"`
+	Reason    string    `json:"reason"`
+	Kind      string    `json:"kind"`
+	Name      string    `json:"name"`
+	Message   string    `json:"message"`
+	Count     int32     `json:"count"`
+	FirstSeen time.Time `json:"firstSeen"`
+	LastSeen  time.Time `json:"lastSeen"`
+}
+
+// Events represents a list of events
+type Events []Event
+
+// EventOptions defines which events of a namespace are listed.
+// Type restricts the events to a type, such as Warning. Kind and Name restrict them to the events about an object,
+// the kind being case insensitive. Empty options match all the events.
+type EventOptions struct {
+	Type string
+	Kind string
+	Name string
+}
+
+// EventService defines the way events are managed
+type EventService interface {
+	List(namespace string, opts EventOptions) (Events, error)
+	Watch(ctx context.Context, namespace string, opts EventOptions, events chan<- Event) error
+}
+
+// EventRepository defines the way events are actually retrieved from Kubernetes
+type EventRepository interface {
+	List(namespace string, opts EventOptions) (Events, error)
+	Watch(ctx context.Context, namespace string, opts EventOptions, events chan<- Event) error
+}
+
+type eventService struct {
+	events EventRepository
+}
+
+// NewEventService creates an EventService
+func NewEventService(events EventRepository) EventService {
+	return &eventService{
+		events: events,
+	}
+}
+
+// List returns the events of the given namespace matching the options, the events occurring several times being
+// listed once, sorted by the last time they occurred
+func (es *eventService) List(namespace string, opts EventOptions) (Events, error) {
+	return es.events.List(namespace, opts)
+}
+
+// Watch sends the events of the given namespace matching the options as they occur, until the context is done.
+// An event occurring again is sent again with its count.
+func (es *eventService) Watch(ctx context.Context, namespace string, opts EventOptions, events chan<- Event) error {
+	return es.events.Watch(ctx, namespace, opts, events)
+}
+
+// Latest returns the last n events of the list, the list being sorted by the last time the events occurred
+func (events Events) Latest(n int) Events {
+	if len(events) <= n {
+		return events
+	}
+
+	return events[len(events)-n:]
+}
@@ -0,0 +1,74 @@
+package resource
+
+import (
+	"fmt"
+)
+
+// Failure represents a terminal failure in a namespace, that will not be fixed by waiting longer :
+// a failed job, a container crash looping too many times or an image that cannot be pulled.
+type Failure struct {
+	Kind      string `json:"kind"`
+	Name      string `json:"name"`
+	Container string `json:"container,omitempty"`
+	Reason    string `json:"reason"`
+	Message   string `json:"message,omitempty"`
+}
+
+// String returns a human readable description of the failure
+func (f Failure) String() string {
+	s := fmt.Sprintf("%s %s", f.Kind, f.Name)
+
+	if f.Container != "" {
+		s = fmt.Sprintf("%s (container %s)", s, f.Container)
+	}
+
+	s = fmt.Sprintf("%s: %s", s, f.Reason)
+
+	if f.Message != "" {
+		s = fmt.Sprintf("%s: %s", s, f.Message)
+	}
+
+	return s
+}
+
+// TerminalFailures returns the failures of the namespace that will not be fixed by waiting longer :
+// failed jobs, pods that cannot pull their image and containers crash looping at least maxRestarts times.
+func (s *NamespaceStatus) TerminalFailures(maxRestarts int32) []Failure {
+	failures := make([]Failure, 0)
+
+	for _, w := range s.Workloads {
+		if !w.Failed {
+			continue
+		}
+
+		failure := Failure{Kind: w.Kind, Name: w.Name, Reason: "Failed"}
+
+		for _, cond := range w.Conditions {
+			if cond.Type == "Failed" && cond.Status == "True" {
+				failure.Reason = cond.Reason
+				failure.Message = cond.Message
+			}
+		}
+
+		failures = append(failures, failure)
+	}
+
+	for _, pf := range s.Failures {
+		terminal := pf.Reason == PodImagePullBackOff || pf.Reason == PodInvalidImageName ||
+			(pf.Reason == PodCrashLoopBackOff && pf.Restarts >= maxRestarts)
+
+		if !terminal {
+			continue
+		}
+
+		failures = append(failures, Failure{
+			Kind:      "Pod",
+			Name:      pf.Pod,
+			Container: pf.Container,
+			Reason:    pf.Reason,
+			Message:   pf.Message,
+		})
+	}
+
+	return failures
+}
@@ -0,0 +1,45 @@
+package resource_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/DanielPickens/Keeper/pkg/resource"
+)
+
+func TestTerminalFailures(t *testing.T) {
+	status := resource.NamespaceStatus{
+		Workloads: []resource.WorkloadStatus{
+			{Kind: resource.KindDeployment, Name: "api"},
+			{
+				Kind:   resource.KindJob,
+				Name:   "migrate",
+				Failed: true,
+				Conditions: []resource.Condition{
+					{Type: "Failed", Status: "True", Reason: "BackoffLimitExceeded", Message: "Job has reached the specified backoff limit"},
+				},
+			},
+		},
+		Failures: []resource.PodFailure{
+			{Pod: "api-1", Container: "api", Reason: resource.PodCrashLoopBackOff, Restarts: 2},
+			{Pod: "api-2", Container: "api", Reason: resource.PodCrashLoopBackOff, Restarts: 3},
+			{Pod: "front-1", Container: "front", Reason: resource.PodImagePullBackOff, Message: "tag not found"},
+			{Pod: "db-1", Reason: resource.PodUnschedulable},
+		},
+	}
+
+	assert.Equal(t, []resource.Failure{
+		{Kind: "Job", Name: "migrate", Reason: "BackoffLimitExceeded", Message: "Job has reached the specified backoff limit"},
+		{Kind: "Pod", Name: "api-2", Container: "api", Reason: "CrashLoopBackOff"},
+		{Kind: "Pod", Name: "front-1", Container: "front", Reason: "ImagePullBackOff", Message: "tag not found"},
+	}, status.TerminalFailures(3))
+}
+
+func TestTerminalFailuresNone(t *testing.T) {
+	status := resource.NamespaceStatus{
+		Workloads: []resource.WorkloadStatus{{Kind: resource.KindDeployment, Name: "api", IsReady: true}},
+	}
+
+	assert.Empty(t, status.TerminalFailures(3))
+}
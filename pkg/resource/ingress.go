@@ -0,0 +1,56 @@
+package resource
+
+// Ingress represents a Kubernetes ingress.
+// Addresses contains the IPs or hostnames assigned by the ingress controller. An ingress is ready once it has an address.
+type Ingress struct {
+	Name      string
+	Status    IngressStatus
+	Addresses []string
+}
+
+// Ingresses represents a list of ingresses
+type Ingresses []Ingress
+
+// IngressStatus represents the readiness of an ingress
+type IngressStatus string
+
+// Ingress statuses
+const (
+	IngressReady    IngressStatus = "Ready"
+	IngressNotReady IngressStatus = "NotReady"
+)
+
+// IngressRepository defines the way ingresses are actually retrieved from Kubernetes
+type IngressRepository interface {
+	List(namespace string) (Ingresses, error)
+}
+
+// workloads returns the readiness of each ingress
+func (ings Ingresses) workloads() []WorkloadStatus {
+	workloads := make([]WorkloadStatus, 0, len(ings))
+
+	for _, ing := range ings {
+		w := WorkloadStatus{
+			Kind:       KindIngress,
+			Name:       ing.Name,
+			Desired:    1,
+			IsReady:    ing.Status == IngressReady,
+			Conditions: []Condition{},
+		}
+
+		if w.IsReady {
+			w.Ready = 1
+		} else {
+			w.Conditions = append(w.Conditions, Condition{
+				Type:    "AddressAssigned",
+				Status:  "False",
+				Reason:  "NoAddress",
+				Message: "the ingress controller has not assigned any address yet",
+			})
+		}
+
+		workloads = append(workloads, w)
+	}
+
+	return workloads
+}
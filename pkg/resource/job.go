@@ -0,0 +1,122 @@
+package resource
+
+import (
+	"context"
+	"fmt"
+	"io"
+)
+
+// Job represents a Kubernetes job and its completion.
+// Ready is the number of succeeded pods and Desired the number of expected completions.
+type Job struct {
+	Name       string
+	Status     JobStatus
+	Ready      int32
+	Desired    int32
+	Conditions []Condition
+}
+
+// Jobs represents a list of jobs
+type Jobs []Job
+
+// JobStatus represents the completion of a job
+type JobStatus string
+
+// Job statuses
+const (
+	JobReady    JobStatus = "Ready"
+	JobNotReady JobStatus = "NotReady"
+	JobFailed   JobStatus = "Failed"
+)
+
+// Failure returns the reason why a job failed, as given by its failed condition
+func (job Job) Failure() string {
+	for _, cond := range job.Conditions {
+		if cond.Type == "Failed" && cond.Status == "True" {
+			if cond.Message != "" {
+				return fmt.Sprintf("%s: %s", cond.Reason, cond.Message)
+			}
+			return cond.Reason
+		}
+	}
+	return "unknown reason"
+}
+
+// ErrorJobNotInConfigs represents an error due to a job that is not part of the configs of a namespace
+type ErrorJobNotInConfigs struct {
+	Namespace string
+	Name      string
+}
+
+// Error returns the error message
+func (err ErrorJobNotInConfigs) Error() string {
+	return fmt.Sprintf("the job %s is not part of the configs of namespace %s", err.Name, err.Namespace)
+}
+
+// workloads returns the completion of each job
+func (jbs Jobs) workloads() []WorkloadStatus {
+	workloads := make([]WorkloadStatus, 0, len(jbs))
+
+	for _, job := range jbs {
+		workloads = append(workloads, WorkloadStatus{
+			Kind:       KindJob,
+			Name:       job.Name,
+			Ready:      job.Ready,
+			Desired:    job.Desired,
+			IsReady:    job.Status == JobReady,
+			Failed:     job.Status == JobFailed,
+			Conditions: job.Conditions,
+		})
+	}
+
+	return workloads
+}
+
+// JobService defines the way jobs are managed
+type JobService interface {
+	Delete(ctx context.Context, namespace, resourceName string) error
+	Run(ctx context.Context, namespace string, manifest []byte) (string, error)
+	Wait(ctx context.Context, namespace, name string) (*Job, error)
+	Logs(ctx context.Context, namespace, name string, w io.Writer) error
+}
+
+// JobRepository defines the way jobs are actually managed on Kubernetes
+type JobRepository interface {
+	List(namespace string) (Jobs, error)
+	Delete(ctx context.Context, namespace, resourceName string) error
+	Run(ctx context.Context, namespace string, manifest []byte) (string, error)
+	Wait(ctx context.Context, namespace, name string) (*Job, error)
+	Logs(ctx context.Context, namespace, name string, w io.Writer) error
+}
+
+type jobService struct {
+	jobs JobRepository
+}
+
+// NewJobService creates a JobService
+func NewJobService(jobs JobRepository) JobService {
+	return &jobService{
+		jobs: jobs,
+	}
+}
+
+// Delete deletes the given job from the namespace, along with its pods
+func (js *jobService) Delete(ctx context.Context, namespace, resourceName string) error {
+	return js.jobs.Delete(ctx, namespace, resourceName)
+}
+
+// Run creates the job described by a manifest in the namespace, replacing a previous job of the same name.
+// It returns the name of the job.
+func (js *jobService) Run(ctx context.Context, namespace string, manifest []byte) (string, error) {
+	return js.jobs.Run(ctx, namespace, manifest)
+}
+
+// Wait waits until the given job is completed or failed
+func (js *jobService) Wait(ctx context.Context, namespace, name string) (*Job, error) {
+	return js.jobs.Wait(ctx, namespace, name)
+}
+
+// Logs streams the logs of the pods of the given job until the job is finished
+func (js *jobService) Logs(ctx context.Context, namespace, name string, w io.Writer) error {
+	return js.jobs.Logs(ctx, namespace, name, w)
+}
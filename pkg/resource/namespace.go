@@ -1,7 +1,9 @@
 package resource
 
 import (
+	"context"
 	"fmt"
+	"strings"
 	"sync"
 	"time"
 
@@ -17,21 +19,38 @@ type Namespace struct {
 // NamespaceService defined the way namespace are managed.
 type NamespaceService interface {
 	Create(namespace string) error
-	ApplyConfig(namespace string, configPath string) error
+	Adopt(namespace string) error
+	ApplyConfig(namespace string, configPath string, opts ApplyOptions) ([]ApplyResult, error)
+	DiffConfig(namespace string, configPath string, opts ApplyOptions) ([]ApplyResult, error)
+	RunHooks(namespace string, configPath string, hook Hook) ([]ApplyResult, error)
+	RerunJob(ctx context.Context, namespace, configPath, name string) (string, error)
 	Delete(namespace string) error
 	GetStatus(namespace string) (*NamespaceStatus, error)
 	List() ([]Namespace, error)
+	ListWithoutStatus() ([]Namespace, error)
 	Watch(events chan NamespaceEvent)
+	WatchWorkloads(ctx context.Context, namespace string, changes chan<- struct{}) error
+	WaitDeleted(ctx context.Context, namespace string) error
+	GetBlockers(namespace string) (*NamespaceBlockers, error)
+	ClearFinalizers(blockers *NamespaceBlockers) error
 }
 
 // NamespaceRepository defined the way namespace area actually managed.
 type NamespaceRepository interface {
 	Create(namespace string) error
+	Adopt(namespace string) error
 	Get(namespace string) (*Namespace, error)
-	ApplyConfig(namespace string, configPath string) error
+	ApplyConfig(namespace string, configPath string, opts ApplyOptions) ([]ApplyResult, error)
+	DiffConfig(namespace string, configPath string, opts ApplyOptions) ([]ApplyResult, error)
+	RunHooks(namespace string, configPath string, hook Hook) ([]ApplyResult, error)
+	RerunJob(ctx context.Context, namespace, configPath, name string) (string, error)
 	Delete(namespace string) error
 	List() ([]Namespace, error)
 	Watch(events chan<- NamespaceEvent) error
+	WatchWorkloads(ctx context.Context, namespace string, changes chan<- struct{}) error
+	WaitDeleted(ctx context.Context, namespace string) error
+	GetBlockers(namespace string) (*NamespaceBlockers, error)
+	ClearFinalizers(blockers *NamespaceBlockers) error
 }
 
 type namespaceService struct {
@@ -40,12 +59,46 @@ type namespaceService struct {
 	deployments  DeploymentRepository
 	statefulsets StatefulsetRepository
 	jobs         JobRepository
+	daemonsets   DaemonsetRepository
+	pvcs         PersistentVolumeClaimRepository
+	ingresses    IngressRepository
+	cronjobs     CronJobRepository
+	customs      CustomResourceRepository
+	readiness    Readiness
 }
 
-// NamespaceStatus represent namespace with percentage of pods running and status phase (Active or Terminating)
+// NamespaceStatus represent namespace with percentage of pods running and status phase (Active or Terminating).
+// Workloads details the readiness of each deployment, statefulset, job, daemonset, persistent volume claim,
+// ingress and cronjob of the namespace and Failures lists the reasons why pods are failing (crash loops, image pull errors, etc.).
+// Warnings are the latest warning events of the namespace, telling why something is failing. They are not taken into
+// account in the readiness and are only reported by the status command and endpoint.
 type NamespaceStatus struct {
-	Status int    `json:"status"`
-	Phase  string `json:"phase"`
+	Status    int              `json:"status"`
+	Phase     string           `json:"phase"`
+	Workloads []WorkloadStatus `json:"workloads"`
+	Failures  []PodFailure     `json:"failures"`
+	Warnings  Events           `json:"warnings"`
+}
+
+// NamespaceBlockers describes what prevents a terminating namespace from being deleted :
+// the finalizers of the namespace itself, its deletion conditions reported by the namespace controller
+// (remaining content, remaining finalizers, discovery failures) and the resources of the namespace having finalizers.
+type NamespaceBlockers struct {
+	Namespace  string             `json:"namespace"`
+	Finalizers []string           `json:"finalizers"`
+	Conditions []Condition        `json:"conditions"`
+	Resources  []BlockingResource `json:"resources"`
+}
+
+// BlockingResource represents a resource of a terminating namespace that is not deleted because of its finalizers.
+// Group, Version and Resource identify the API of the resource.
+type BlockingResource struct {
+	Group      string   `json:"group"`
+	Version    string   `json:"version"`
+	Resource   string   `json:"resource"`
+	Kind       string   `json:"kind"`
+	Name       string   `json:"name"`
+	Finalizers []string `json:"finalizers"`
 }
 
 type NamespaceEvent struct {
@@ -53,13 +106,20 @@ type NamespaceEvent struct {
 	Type      string
 }
 
-// NewNamespaceService creates a new NamespaceService
+// NewNamespaceService creates a new NamespaceService.
+// readiness defines the kinds excluded from the namespace status and the readiness rules of custom resources.
 func NewNamespaceService(
 	namespaces NamespaceRepository,
 	pods PodRepository,
 	deployments DeploymentRepository,
 	statefulsets StatefulsetRepository,
 	jobs JobRepository,
+	daemonsets DaemonsetRepository,
+	pvcs PersistentVolumeClaimRepository,
+	ingresses IngressRepository,
+	cronjobs CronJobRepository,
+	customs CustomResourceRepository,
+	readiness Readiness,
 ) NamespaceService {
 
 	ns := &namespaceService{
@@ -68,6 +128,12 @@ func NewNamespaceService(
 		deployments:  deployments,
 		statefulsets: statefulsets,
 		jobs:         jobs,
+		daemonsets:   daemonsets,
+		pvcs:         pvcs,
+		ingresses:    ingresses,
+		cronjobs:     cronjobs,
+		customs:      customs,
+		readiness:    readiness,
 	}
 
 	return ns
@@ -84,11 +150,31 @@ func (ns *namespaceService) Create(n string) error {
 	return nil
 }
 
+// Adopt marks an existing kubernetes namespace as managed by keeper
+func (ns *namespaceService) Adopt(namespace string) error {
+	return ns.namespaces.Adopt(namespace)
+}
+
 // ApplyConfig apply kubernetes configurations to the given namespace.
 // Warning : For now, this method takes a configPath as parameter. This parameter is the directory containing configs in a playbook
 // This may change since the NamespaceService should not be aware that configs are stored in files.
-func (ns *namespaceService) ApplyConfig(namespace, configPath string) error {
-	return ns.namespaces.ApplyConfig(namespace, configPath)
+func (ns *namespaceService) ApplyConfig(namespace, configPath string, opts ApplyOptions) ([]ApplyResult, error) {
+	return ns.namespaces.ApplyConfig(namespace, configPath, opts)
+}
+
+// DiffConfig returns what applying the kubernetes configurations to the given namespace would change
+func (ns *namespaceService) DiffConfig(namespace, configPath string, opts ApplyOptions) ([]ApplyResult, error) {
+	return ns.namespaces.DiffConfig(namespace, configPath, opts)
+}
+
+// RunHooks runs the hook jobs of the kubernetes configurations of the given namespace for the given hook
+func (ns *namespaceService) RunHooks(namespace, configPath string, hook Hook) ([]ApplyResult, error) {
+	return ns.namespaces.RunHooks(namespace, configPath, hook)
+}
+
+// RerunJob deletes a job of the given namespace and creates it again from the kubernetes configurations of the namespace
+func (ns *namespaceService) RerunJob(ctx context.Context, namespace, configPath, name string) (string, error) {
+	return ns.namespaces.RerunJob(ctx, namespace, configPath, name)
 }
 
 // Delete deletes a kubernetes namespace
@@ -96,6 +182,22 @@ func (ns *namespaceService) Delete(namespace string) error {
 	return ns.namespaces.Delete(namespace)
 }
 
+// WaitDeleted waits until the given namespace does not exist anymore
+func (ns *namespaceService) WaitDeleted(ctx context.Context, namespace string) error {
+	return ns.namespaces.WaitDeleted(ctx, namespace)
+}
+
+// GetBlockers returns what prevents the given terminating namespace from being deleted
+func (ns *namespaceService) GetBlockers(namespace string) (*NamespaceBlockers, error) {
+	return ns.namespaces.GetBlockers(namespace)
+}
+
+// ClearFinalizers removes the finalizers of the blocking resources and of the namespace itself,
+// letting Kubernetes complete the deletion of the namespace.
+func (ns *namespaceService) ClearFinalizers(blockers *NamespaceBlockers) error {
+	return ns.namespaces.ClearFinalizers(blockers)
+}
+
 // List returns a slice of namespace from the kubernetes package and enrich each of the
 // returned namespace with their status.
 func (ns *namespaceService) List() ([]Namespace, error) {
@@ -110,14 +212,15 @@ func (ns *namespaceService) List() ([]Namespace, error) {
 		wg.Add(1)
 
 		go func(index int) {
-			status, err := ns.GetStatus(namespaces[index].Name)
+			defer wg.Done()
 
+			status, err := ns.GetStatus(namespaces[index].Name)
 			if err != nil {
 				namespaces[index].Status = 0
+				return
 			}
 
 			namespaces[index].Status = status.Status
-			wg.Done()
 		}(i)
 	}
 
@@ -126,8 +229,14 @@ func (ns *namespaceService) List() ([]Namespace, error) {
 	return namespaces, nil
 }
 
+// ListWithoutStatus returns a slice of namespace from the kubernetes package, without computing their status.
+func (ns *namespaceService) ListWithoutStatus() ([]Namespace, error) {
+	return ns.namespaces.List()
+}
+
 // GetStatus returns the status of an inventory
-// The status is an int that represents the percentage of pods in a "running" state inside the given namespace
+// The status is an int that represents the percentage of pods in a "running" state inside the given namespace.
+// It also returns the status of every workload and the failures harvested from the namespace pods.
 func (ns *namespaceService) GetStatus(namespace string) (*NamespaceStatus, error) {
 
 	// get namespace state
@@ -137,46 +246,119 @@ func (ns *namespaceService) GetStatus(namespace string) (*NamespaceStatus, error
 	}
 
 	if n.Phase == "Terminating" {
-		return &NamespaceStatus{0, n.Phase}, nil
+		return &NamespaceStatus{Status: 0, Phase: n.Phase}, nil
 	}
 
-	dps, errDps := ns.deployments.List(namespace)
-	sfs, errSfs := ns.statefulsets.List(namespace)
-	jbs, errJbs := ns.jobs.List(namespace)
+	workloads, err := ns.listWorkloads(namespace)
+	if err != nil {
+		return &NamespaceStatus{Status: 0, Phase: ""}, fmt.Errorf("namespace get status: %v", err)
+	}
 
-	if errDps != nil || errSfs != nil || errJbs != nil {
-		return &NamespaceStatus{0, ""}, fmt.Errorf("namespace get status: list deployments, statefulsets or jobs: %v", err)
+	pods, err := ns.pods.List(namespace)
+	if err != nil {
+		return &NamespaceStatus{Status: 0, Phase: ""}, fmt.Errorf("namespace get status: list pods: %v", err)
 	}
 
-	totalApps := len(dps) + len(sfs) + len(jbs)
+	return &NamespaceStatus{
+		Status:    readiness(workloads),
+		Phase:     n.Phase,
+		Workloads: workloads,
+		Failures:  pods.Failures(),
+	}, nil
+}
+
+// workloadLister lists the workloads of a given kind in a namespace
+type workloadLister struct {
+	kind string
+	list func(namespace string) ([]WorkloadStatus, error)
+}
 
-	if totalApps == 0 {
-		return &NamespaceStatus{0, n.Phase}, nil
+// listWorkloads returns the status of every workload of the namespace whose kind is not ignored,
+// including the custom resources targeted by the readiness rules.
+func (ns *namespaceService) listWorkloads(namespace string) ([]WorkloadStatus, error) {
+	listers := []workloadLister{
+		{KindDeployment, func(n string) ([]WorkloadStatus, error) {
+			dps, err := ns.deployments.List(n)
+			return dps.workloads(), err
+		}},
+		{KindStatefulset, func(n string) ([]WorkloadStatus, error) {
+			sfs, err := ns.statefulsets.List(n)
+			return sfs.workloads(), err
+		}},
+		{KindJob, func(n string) ([]WorkloadStatus, error) {
+			jbs, err := ns.jobs.List(n)
+			return jbs.workloads(), err
+		}},
+		{KindDaemonset, func(n string) ([]WorkloadStatus, error) {
+			dss, err := ns.daemonsets.List(n)
+			return dss.workloads(), err
+		}},
+		{KindPersistentVolumeClaim, func(n string) ([]WorkloadStatus, error) {
+			pvcs, err := ns.pvcs.List(n)
+			return pvcs.workloads(), err
+		}},
+		{KindIngress, func(n string) ([]WorkloadStatus, error) {
+			ings, err := ns.ingresses.List(n)
+			return ings.workloads(), err
+		}},
+		{KindCronJob, func(n string) ([]WorkloadStatus, error) {
+			cjs, err := ns.cronjobs.List(n)
+			return cjs.workloads(), err
+		}},
 	}
 
-	var i int
+	for _, rule := range ns.readiness.Rules {
+		rule := rule
+		listers = append(listers, workloadLister{rule.Kind, func(n string) ([]WorkloadStatus, error) {
+			crs, err := ns.customs.List(n, rule)
+			return crs.workloads(), err
+		}})
+	}
 
-	for _, dp := range dps {
-		if dp.Status == DeploymentReady {
-			i++
+	workloads := make([]WorkloadStatus, 0)
+
+	for _, l := range listers {
+		if ns.isIgnored(l.kind) {
+			continue
+		}
+
+		w, err := l.list(namespace)
+		if err != nil {
+			return nil, fmt.Errorf("list %s: %v", l.kind, err)
 		}
+
+		workloads = append(workloads, w...)
 	}
 
-	for _, sf := range sfs {
-		if sf.Status == StatefulsetReady {
-			i++
+	return workloads, nil
+}
+
+// isIgnored returns true if the given kind is excluded from the namespace status
+func (ns *namespaceService) isIgnored(kind string) bool {
+	for _, k := range ns.readiness.Ignore {
+		if strings.EqualFold(k, kind) {
+			return true
 		}
 	}
 
-	for _, job := range jbs {
-		if job.Status == JobReady {
+	return false
+}
+
+// readiness returns the percentage of ready workloads.
+func readiness(workloads []WorkloadStatus) int {
+	if len(workloads) == 0 {
+		return 0
+	}
+
+	var i int
+
+	for _, w := range workloads {
+		if w.IsReady {
 			i++
 		}
 	}
 
-	status := i * 100 / totalApps
-
-	return &NamespaceStatus{status, n.Phase}, nil
+	return i * 100 / len(workloads)
 }
 
 func (ns *namespaceService) Watch(events chan NamespaceEvent) {
@@ -199,6 +381,12 @@ func (ns *namespaceService) Watch(events chan NamespaceEvent) {
 		Error("watch namespace stopped due to error")
 }
 
+// WatchWorkloads notifies on the changes channel each time a workload or a pod of the namespace changes.
+// It blocks until the context is done or the watch is closed by Kubernetes.
+func (ns *namespaceService) WatchWorkloads(ctx context.Context, namespace string, changes chan<- struct{}) error {
+	return ns.namespaces.WatchWorkloads(ctx, namespace, changes)
+}
+
 // ErrorCreateNamespace represents an error due to a namespace creation failure on kubernetes cluster
 type ErrorCreateNamespace struct {
 	Msg string
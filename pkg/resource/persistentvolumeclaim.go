@@ -0,0 +1,50 @@
+package resource
+
+// PersistentVolumeClaim represents a Kubernetes persistent volume claim.
+// Phase is the claim phase ("Pending", "Bound" or "Lost"). A claim is ready once it is bound to a volume.
+type PersistentVolumeClaim struct {
+	Name       string
+	Status     PersistentVolumeClaimStatus
+	Phase      string
+	Conditions []Condition
+}
+
+// PersistentVolumeClaims represents a list of persistent volume claims
+type PersistentVolumeClaims []PersistentVolumeClaim
+
+// PersistentVolumeClaimStatus represents the readiness of a persistent volume claim
+type PersistentVolumeClaimStatus string
+
+// Persistent volume claim statuses
+const (
+	PersistentVolumeClaimReady    PersistentVolumeClaimStatus = "Ready"
+	PersistentVolumeClaimNotReady PersistentVolumeClaimStatus = "NotReady"
+)
+
+// PersistentVolumeClaimRepository defines the way persistent volume claims are actually retrieved from Kubernetes
+type PersistentVolumeClaimRepository interface {
+	List(namespace string) (PersistentVolumeClaims, error)
+}
+
+// workloads returns the readiness of each persistent volume claim
+func (pvcs PersistentVolumeClaims) workloads() []WorkloadStatus {
+	workloads := make([]WorkloadStatus, 0, len(pvcs))
+
+	for _, pvc := range pvcs {
+		w := WorkloadStatus{
+			Kind:       KindPersistentVolumeClaim,
+			Name:       pvc.Name,
+			Desired:    1,
+			IsReady:    pvc.Status == PersistentVolumeClaimReady,
+			Conditions: pvc.Conditions,
+		}
+
+		if w.IsReady {
+			w.Ready = 1
+		}
+
+		workloads = append(workloads, w)
+	}
+
+	return workloads
+}
@@ -0,0 +1,148 @@
+package resource
+
+import (
+	"context"
+	"time"
+)
+
+// Pod represents a Kubernetes pod.
+// Status is the pod phase ("Pending", "Running"...). Ready is the number of ready containers out of Total, and
+// Restarts the restarts of all the containers. Owner is the workload managing the pod, if any, and
+// LastTerminationReason the reason why a container of the pod last terminated, such as OOMKilled.
+// Failures contains the reasons why the pod or its containers are failing, if any.
+type Pod struct {
+	Name                  string         `json:"name"`
+	Status                string         `json:"status"`
+	Ready                 int32          `json:"ready"`
+	Total                 int32          `json:"total"`
+	Restarts              int32          `json:"restarts"`
+	Node                  string         `json:"node,omitempty"`
+	IP                    string         `json:"ip,omitempty"`
+	CreatedAt             time.Time      `json:"createdAt"`
+	Owner                 *PodOwner      `json:"owner,omitempty"`
+	LastTerminationReason string         `json:"lastTerminationReason,omitempty"`
+	Containers            []PodContainer `json:"containers"`
+	Failures              []PodFailure   `json:"failures,omitempty"`
+}
+
+// PodOwner is the workload managing a pod, such as a deployment rather than its replicaset
+type PodOwner struct {
+	Kind string `json:"kind"`
+	Name string `json:"name"`
+}
+
+// PodContainer represents a container of a pod.
+// State is the state of the container ("Running", "Terminated" or "Waiting"), Reason the reason of the waiting or
+// terminated state, and LastTerminationReason the reason why the previous instance of the container terminated.
+type PodContainer struct {
+	Name                  string `json:"name"`
+	Image                 string `json:"image"`
+	Ready                 bool   `json:"ready"`
+	Restarts              int32  `json:"restarts"`
+	State                 string `json:"state"`
+	Reason                string `json:"reason,omitempty"`
+	LastTerminationReason string `json:"lastTerminationReason,omitempty"`
+}
+
+// Pods represents a list of pods
+type Pods []Pod
+
+// PodFailure represents a reason why a pod is not able to run properly.
+// Container is empty when the failure concerns the whole pod (for instance when it cannot be scheduled).
+type PodFailure struct {
+	Pod       string `json:"pod"`
+	Container string `json:"container,omitempty"`
+	Reason    string `json:"reason"`
+	Message   string `json:"message,omitempty"`
+	Restarts  int32  `json:"restarts"`
+}
+
+// Container states of a pod
+const (
+	ContainerRunning    = "Running"
+	ContainerWaiting    = "Waiting"
+	ContainerTerminated = "Terminated"
+)
+
+// Pod failure reasons reported in a namespace status
+const (
+	PodCrashLoopBackOff = "CrashLoopBackOff"
+	PodImagePullBackOff = "ImagePullBackOff"
+	PodErrImagePull     = "ErrImagePull"
+	PodInvalidImageName = "InvalidImageName"
+	PodOOMKilled        = "OOMKilled"
+	PodUnschedulable    = "Unschedulable"
+)
+
+// LogOptions defines which logs of the pods of a namespace are streamed.
+// Selector is a label selector restricting the pods, such as app=api. Since only streams the logs newer than
+// a duration. Previous streams the logs of the previous instance of the restarted containers.
+// Follow keeps streaming the logs of the running containers, and of the ones started afterwards, until the
+// context is done.
+type LogOptions struct {
+	Selector string
+	Since    time.Duration
+	Previous bool
+	Follow   bool
+}
+
+// LogLine represents a line of log of a container.
+// Time is the time the line has been written at, as reported by kubernetes.
+type LogLine struct {
+	Pod       string    `json:"pod"`
+	Container string    `json:"container"`
+	Time      time.Time `json:"time"`
+	Message   string    `json:"message"`
+}
+
+// PodService defines the way pods are managed
+type PodService interface {
+	List(namespace string) (Pods, error)
+	Logs(ctx context.Context, namespace string, opts LogOptions, lines chan<- LogLine) error
+	WorkloadSelector(namespace, kind, name string) (string, error)
+}
+
+// PodRepository defines the way pods are actually retrieved from Kubernetes
+type PodRepository interface {
+	List(namespace string) (Pods, error)
+	Logs(ctx context.Context, namespace string, opts LogOptions, lines chan<- LogLine) error
+	WorkloadSelector(namespace, kind, name string) (string, error)
+}
+
+type podService struct {
+	pods PodRepository
+}
+
+// NewPodService creates a PodService
+func NewPodService(pods PodRepository) PodService {
+	return &podService{
+		pods: pods,
+	}
+}
+
+// List returns the pods of the given namespace, sorted by name
+func (ps *podService) List(namespace string) (Pods, error) {
+	return ps.pods.List(namespace)
+}
+
+// Logs streams the log lines of the containers of the pods of the given namespace, multiplexed into lines.
+// It returns once every stream ended, or once the context is done when following the logs.
+func (ps *podService) Logs(ctx context.Context, namespace string, opts LogOptions, lines chan<- LogLine) error {
+	return ps.pods.Logs(ctx, namespace, opts, lines)
+}
+
+// WorkloadSelector returns the label selector of the pods of the given workload, such as a deployment
+func (ps *podService) WorkloadSelector(namespace, kind, name string) (string, error) {
+	return ps.pods.WorkloadSelector(namespace, kind, name)
+}
+
+// Failures returns the failures of every pod in the list.
+func (pods Pods) Failures() []PodFailure {
+	failures := make([]PodFailure, 0)
+
+	for _, pod := range pods {
+		failures = append(failures, pod.Failures...)
+	}
+
+	return failures
+}
@@ -0,0 +1,147 @@
+package resource
+
+import (
+	"context"
+	"sort"
+	"strings"
+)
+
+// Service represents a Kubernetes service exposed outside of the cluster.
+// Addr is the address the service can be reached at.
+type Service struct {
+	Name  string
+	Ports []Port
+	Addr  string
+}
+
+// Port represents a port of a service and the port it is exposed on.
+type Port struct {
+	Port        int32
+	ExposedPort int32
+}
+
+// ServiceURL represents a URL a port of a service can be reached at, and what exposes it : an ingress rule,
+// a Gateway API HTTPRoute, a load balancer, a node port or the DNS of the cluster.
+// Source is the name of the ingress or of the HTTPRoute. The URLs of the cluster DNS have no scheme.
+type ServiceURL struct {
+	Service string
+	Port    int32
+	Kind    ServiceURLKind
+	Source  string
+	URL     string
+}
+
+// ServiceURLs represents a list of service URLs
+type ServiceURLs []ServiceURL
+
+// ServiceURLKind represents what exposes a service URL
+type ServiceURLKind string
+
+// Service URL kinds, from the most to the least relevant to open
+const (
+	ServiceURLIngress      ServiceURLKind = "Ingress"
+	ServiceURLHTTPRoute    ServiceURLKind = "HTTPRoute"
+	ServiceURLLoadBalancer ServiceURLKind = "LoadBalancer"
+	ServiceURLNodePort     ServiceURLKind = "NodePort"
+	ServiceURLCluster      ServiceURLKind = "ClusterDNS"
+)
+
+var serviceURLKindRanks = map[ServiceURLKind]int{
+	ServiceURLIngress:      0,
+	ServiceURLHTTPRoute:    1,
+	ServiceURLLoadBalancer: 2,
+	ServiceURLNodePort:     3,
+	ServiceURLCluster:      4,
+}
+
+// Primary returns the URL to open to reach the namespace : a URL of an ingress first, then of an HTTPRoute, of a load
+// balancer and of a node port. Among URLs of the same kind, HTTPS and shorter URLs come first.
+// The cluster DNS addresses cannot be opened from outside of the cluster : the second value is false when there is no
+// other URL.
+func (urls ServiceURLs) Primary() (ServiceURL, bool) {
+	var ranked ServiceURLs
+	for _, u := range urls {
+		if u.Kind != ServiceURLCluster {
+			ranked = append(ranked, u)
+		}
+	}
+
+	if len(ranked) == 0 {
+		return ServiceURL{}, false
+	}
+
+	sort.SliceStable(ranked, func(i, j int) bool {
+		ri, rj := serviceURLKindRanks[ranked[i].Kind], serviceURLKindRanks[ranked[j].Kind]
+		if ri != rj {
+			return ri < rj
+		}
+
+		si, sj := strings.HasPrefix(ranked[i].URL, "https://"), strings.HasPrefix(ranked[j].URL, "https://")
+		if si != sj {
+			return si
+		}
+
+		return len(ranked[i].URL) < len(ranked[j].URL)
+	})
+
+	return ranked[0], true
+}
+
+// ForwardedPort represents a port of a service forwarded to localhost through one of its ready pods.
+// TargetPort is the port of the pod the service port targets, LocalPort the port it is forwarded from,
+// chosen by the system when it is 0.
+type ForwardedPort struct {
+	Service    string
+	Pod        string
+	Port       int32
+	TargetPort int32
+	LocalPort  int32
+}
+
+// ServiceService defines the way services are managed
+type ServiceService interface {
+	ListExposed(namespace string) ([]Service, error)
+	ListURLs(namespace string) (ServiceURLs, error)
+	ForwardTargets(namespace string, names []string) ([]ForwardedPort, error)
+	Forward(ctx context.Context, namespace, address string, ports []ForwardedPort, ready func([]ForwardedPort)) error
+}
+
+// ServiceRepository defines the way services are actually retrieved from Kubernetes
+type ServiceRepository interface {
+	ListExposed(namespace string) ([]Service, error)
+	ListURLs(namespace string) (ServiceURLs, error)
+	ForwardTargets(namespace string, names []string) ([]ForwardedPort, error)
+	Forward(ctx context.Context, namespace, address string, ports []ForwardedPort, ready func([]ForwardedPort)) error
+}
+
+type serviceService struct {
+	services ServiceRepository
+}
+
+// NewServiceService creates a ServiceService
+func NewServiceService(services ServiceRepository) ServiceService {
+	return &serviceService{
+		services: services,
+	}
+}
+
+// ListExposed returns the services of the namespace reachable from outside of the cluster
+func (ss *serviceService) ListExposed(namespace string) ([]Service, error) {
+	return ss.services.ListExposed(namespace)
+}
+
+// ListURLs returns the URLs the services of the namespace can be reached at
+func (ss *serviceService) ListURLs(namespace string) (ServiceURLs, error) {
+	return ss.services.ListURLs(namespace)
+}
+
+// ForwardTargets returns the ports of the services of the namespace, each one targeting a ready pod of its service.
+// All the services of the namespace are returned when no name is given.
+func (ss *serviceService) ForwardTargets(namespace string, names []string) ([]ForwardedPort, error) {
+	return ss.services.ForwardTargets(namespace, names)
+}
+
+// Forward forwards the ports from localhost to their pods until the context is done
+func (ss *serviceService) Forward(ctx context.Context, namespace, address string, ports []ForwardedPort, ready func([]ForwardedPort)) error {
+	return ss.services.Forward(ctx, namespace, address, ports, ready)
+}
@@ -0,0 +1,33 @@
+package resource_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/DanielPickens/Keeper/pkg/resource"
+)
+
+func TestPrimaryServiceURL(t *testing.T) {
+	urls := resource.ServiceURLs{
+		{Service: "api", Kind: resource.ServiceURLCluster, URL: "api.test.svc.cluster.local:80"},
+		{Service: "api", Kind: resource.ServiceURLNodePort, URL: "http://cluster:30080/"},
+		{Service: "api", Kind: resource.ServiceURLIngress, URL: "http://api.example.com/v1"},
+		{Service: "api", Kind: resource.ServiceURLIngress, URL: "https://api.example.com/v1/admin"},
+		{Service: "api", Kind: resource.ServiceURLIngress, URL: "https://api.example.com/"},
+	}
+
+	primary, ok := urls.Primary()
+	assert.True(t, ok)
+	assert.Equal(t, "https://api.example.com/", primary.URL)
+
+	primary, ok = urls[:2].Primary()
+	assert.True(t, ok)
+	assert.Equal(t, "http://cluster:30080/", primary.URL)
+
+	_, ok = urls[:1].Primary()
+	assert.False(t, ok)
+
+	_, ok = resource.ServiceURLs{}.Primary()
+	assert.False(t, ok)
+}
@@ -0,0 +1,47 @@
+package resource
+
+// Statefulset represents a Kubernetes statefulset and its readiness.
+type Statefulset struct {
+	Name       string
+	Status     StatefulsetStatus
+	Ready      int32
+	Desired    int32
+	Conditions []Condition
+	Containers []Container
+}
+
+// Statefulsets represents a list of statefulsets
+type Statefulsets []Statefulset
+
+// StatefulsetStatus represents the readiness of a statefulset
+type StatefulsetStatus string
+
+// Statefulset statuses
+const (
+	StatefulsetReady    StatefulsetStatus = "Ready"
+	StatefulsetNotReady StatefulsetStatus = "NotReady"
+)
+
+// StatefulsetRepository defines the way statefulsets are actually retrieved from Kubernetes
+type StatefulsetRepository interface {
+	List(namespace string) (Statefulsets, error)
+}
+
+// workloads returns the readiness of each statefulset
+func (sfs Statefulsets) workloads() []WorkloadStatus {
+	workloads := make([]WorkloadStatus, 0, len(sfs))
+
+	for _, sf := range sfs {
+		workloads = append(workloads, WorkloadStatus{
+			Kind:       KindStatefulset,
+			Name:       sf.Name,
+			Ready:      sf.Ready,
+			Desired:    sf.Desired,
+			IsReady:    sf.Status == StatefulsetReady,
+			Conditions: sf.Conditions,
+			Containers: sf.Containers,
+		})
+	}
+
+	return workloads
+}
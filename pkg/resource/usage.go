@@ -0,0 +1,67 @@
+package resource
+
+import "fmt"
+
+// PodUsage represents the resources used by a pod, as reported by the metrics API, and the resources it requests.
+// CPU is in millicores and Memory in bytes.
+type PodUsage struct {
+	Name          string `json:"name"`
+	CPU           int64  `json:"cpu"`
+	Memory        int64  `json:"memory"`
+	CPURequest    int64  `json:"cpuRequest"`
+	MemoryRequest int64  `json:"memoryRequest"`
+}
+
+// ResourceRequests represents the resources requested in a namespace : the CPU in millicores and the memory in bytes
+// requested by its pods that are not terminated, and the storage in bytes requested by its persistent volume claims.
+// Labels are the labels of the namespace, telling which team owns it.
+type ResourceRequests struct {
+	Namespace string            `json:"namespace"`
+	Labels    map[string]string `json:"labels,omitempty"`
+	CPU       int64             `json:"cpu"`
+	Memory    int64             `json:"memory"`
+	Storage   int64             `json:"storage"`
+}
+
+// UsageService defines the way the resources used and requested in namespaces are retrieved
+type UsageService interface {
+	Top(namespace string) ([]PodUsage, error)
+	Requests(namespace string) (ResourceRequests, error)
+}
+
+// UsageRepository defines the way the resources used and requested in namespaces are actually retrieved from Kubernetes
+type UsageRepository interface {
+	Top(namespace string) ([]PodUsage, error)
+	Requests(namespace string) (ResourceRequests, error)
+}
+
+// ErrorMetricsUnavailable represents an error due to the metrics API (metrics.k8s.io) not being served by the cluster
+type ErrorMetricsUnavailable struct {
+	Err error
+}
+
+// Error returns the error message
+func (err ErrorMetricsUnavailable) Error() string {
+	return fmt.Sprintf("the metrics API is not available, is the metrics server installed? %v", err.Err)
+}
+
+type usageService struct {
+	usages UsageRepository
+}
+
+// NewUsageService creates a UsageService
+func NewUsageService(usages UsageRepository) UsageService {
+	return &usageService{
+		usages: usages,
+	}
+}
+
+// Top returns the CPU and the memory used by each pod of the given namespace, sorted by name
+func (us *usageService) Top(namespace string) ([]PodUsage, error) {
+	return us.usages.Top(namespace)
+}
+
+// Requests returns the resources requested by the pods and the persistent volume claims of the given namespace
+func (us *usageService) Requests(namespace string) (ResourceRequests, error) {
+	return us.usages.Requests(namespace)
+}
@@ -0,0 +1,50 @@
+package resource
+
+// Kinds of workloads taken into account when computing a namespace status.
+const (
+	KindDeployment            = "Deployment"
+	KindStatefulset           = "StatefulSet"
+	KindJob                   = "Job"
+	KindDaemonset             = "DaemonSet"
+	KindPersistentVolumeClaim = "PersistentVolumeClaim"
+	KindIngress               = "Ingress"
+	KindCronJob               = "CronJob"
+)
+
+// WorkloadStatus represents the readiness of a single workload (deployment, statefulset, job...) of a namespace.
+// Ready and Desired are the number of ready and expected replicas (or completions for a job).
+// Failed is true when the workload will never become ready by itself, such as a job that reached its backoff limit.
+// Containers are the containers of the pods of deployments, statefulsets and daemonsets.
+type WorkloadStatus struct {
+	Kind       string      `json:"kind"`
+	Name       string      `json:"name"`
+	Ready      int32       `json:"ready"`
+	Desired    int32       `json:"desired"`
+	IsReady    bool        `json:"isReady"`
+	Failed     bool        `json:"failed"`
+	Conditions []Condition `json:"conditions"`
+	Containers []Container `json:"containers,omitempty"`
+}
+
+// Container represents a container of a workload and the image it runs
+type Container struct {
+	Name  string `json:"name"`
+	Image string `json:"image"`
+}
+
+// Condition is a condition reported by Kubernetes on a workload, such as "Available" for a deployment
+// or "Complete" for a job.
+type Condition struct {
+	Type    string `json:"type"`
+	Status  string `json:"status"`
+	Reason  string `json:"reason,omitempty"`
+	Message string `json:"message,omitempty"`
+}
+
+// Readiness defines how the readiness of a namespace is computed.
+// Ignore is the list of kinds (such as "DaemonSet" or "Ingress") excluded from the namespace status and
+// Rules are the readiness rules of the custom resources taken into account in the namespace status.
+type Readiness struct {
+	Ignore []string
+	Rules  []ReadinessRule
+}